@@ -38,12 +38,18 @@ func NewApp(hostname, appClientID, appClientSecret string) *App {
 	}
 }
 
+// NewClient returns a client of a store sharing the HTTP client of the app, in the channel of the app
+// if it is set, the default channel otherwise
 func (a *App) NewClient(storeHash, xAuthToken string) *Client {
+	channelID := a.ChannelID
+	if channelID == 0 {
+		channelID = defaultChannelID
+	}
 	return &Client{
 		StoreHash:  storeHash,
 		XAuthToken: xAuthToken,
 		MaxRetries: 1,
 		HTTPClient: a.HTTPClient,
-		ChannelID:  1,
+		ChannelID:  channelID,
 	}
 }
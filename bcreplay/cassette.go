@@ -0,0 +1,158 @@
+package bcreplay
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Redacted replaces scrubbed header values in cassettes
+const Redacted = "[REDACTED]"
+
+// DefaultScrubHeaders are the headers removed from recorded requests and responses
+var DefaultScrubHeaders = []string{
+	"X-Auth-Token",
+	"X-Auth-Client",
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// Cassette is a list of recorded interactions, stored as a JSON file
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a recorded request and the response it got
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the recorded part of an HTTP request
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is the recorded part of an HTTP response
+type RecordedResponse struct {
+	StatusCode int         `json:"status_code"`
+	Status     string      `json:"status"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// LoadCassette reads a cassette file
+func LoadCassette(path string) (*Cassette, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Cassette
+	err = json.Unmarshal(b, &c)
+	if err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// Save writes the cassette to path, creating the directory if needed
+func (c *Cassette) Save(path string) error {
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	err = ioutil.WriteFile(tmp, b, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// scrubHeader returns a copy of h with the given headers redacted
+func scrubHeader(h http.Header, names []string) http.Header {
+	ret := h.Clone()
+	for _, n := range names {
+		if _, ok := ret[http.CanonicalHeaderKey(n)]; ok {
+			ret.Set(n, Redacted)
+		}
+	}
+	return ret
+}
+
+// ScrubJSONFields returns a filter that redacts top level and nested fields with the given
+// names in JSON request and response bodies, e.g. "access_token" or "client_secret"
+func ScrubJSONFields(fields ...string) func(*Interaction) {
+	names := map[string]bool{}
+	for _, f := range fields {
+		names[f] = true
+	}
+	scrub := func(body string) string {
+		var v interface{}
+		if json.Unmarshal([]byte(body), &v) != nil {
+			return body
+		}
+		if !redactFields(v, names) {
+			return body
+		}
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+	return func(i *Interaction) {
+		i.Request.Body = scrub(i.Request.Body)
+		i.Response.Body = scrub(i.Response.Body)
+	}
+}
+
+func redactFields(v interface{}, names map[string]bool) bool {
+	changed := false
+	switch val := v.(type) {
+	case map[string]interface{}:
+		for k, f := range val {
+			if names[k] {
+				val[k] = Redacted
+				changed = true
+				continue
+			}
+			changed = redactFields(f, names) || changed
+		}
+	case []interface{}:
+		for _, f := range val {
+			changed = redactFields(f, names) || changed
+		}
+	}
+	return changed
+}
+
+// ScrubQueryParams returns a filter that redacts query parameters in recorded URLs
+func ScrubQueryParams(params ...string) func(*Interaction) {
+	return func(i *Interaction) {
+		base, query := i.Request.URL, ""
+		if n := strings.Index(base, "?"); n >= 0 {
+			base, query = base[:n], base[n+1:]
+		}
+		if query == "" {
+			return
+		}
+		parts := strings.Split(query, "&")
+		for n, p := range parts {
+			for _, name := range params {
+				if strings.HasPrefix(p, name+"=") {
+					parts[n] = name + "=" + Redacted
+				}
+			}
+		}
+		i.Request.URL = base + "?" + strings.Join(parts, "&")
+	}
+}
@@ -0,0 +1,280 @@
+package bcreplay_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+	"github.com/mvalenziano/bigcommerce-api-go/bcreplay"
+	"github.com/mvalenziano/bigcommerce-api-go/bctest"
+)
+
+func TestCassetteRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "products.json")
+	s := bctest.NewServer()
+	s.AddProduct(bigcommerce.Product{Name: "Shirt", Type: "physical", Sku: "SHIRT", Price: 10})
+
+	rec, err := bcreplay.New(path, bcreplay.ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != bcreplay.ModeRecord {
+		t.Fatalf("mode %v without a cassette, want record", rec.Mode())
+	}
+	rec.Client = s.HTTPClient()
+	bc := s.Client()
+	bc.HTTPClient = rec
+	recorded, err := bc.GetAllProducts(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, err := bc.CreateProduct(&bigcommerce.Product{Name: "Hat", Type: "physical", Sku: "HAT", Weight: 1, Price: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	c, err := bcreplay.LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Interactions) != 2 {
+		t.Fatalf("%d interactions recorded, want 2", len(c.Interactions))
+	}
+	for _, i := range c.Interactions {
+		if got := i.Request.Header.Get("X-Auth-Token"); got != bcreplay.Redacted {
+			t.Errorf("%s %s recorded with auth token %q", i.Request.Method, i.Request.URL, got)
+		}
+	}
+
+	// the server is gone, the same calls are answered from the cassette
+	rec, err = bcreplay.New(path, bcreplay.ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != bcreplay.ModeReplay {
+		t.Fatalf("mode %v with a cassette, want replay", rec.Mode())
+	}
+	bc = bigcommerce.NewClient(bctest.StoreHash, bctest.AuthToken)
+	bc.HTTPClient = rec
+	replayed, err := bc.GetAllProducts(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed) != len(recorded) || replayed[0].Sku != recorded[0].Sku {
+		t.Errorf("replayed %v, want %v", replayed, recorded)
+	}
+	again, err := bc.CreateProduct(&bigcommerce.Product{Name: "Hat", Type: "physical", Sku: "HAT", Weight: 1, Price: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != created.ID {
+		t.Errorf("replayed product %d, want %d", again.ID, created.ID)
+	}
+	if unused := rec.Unused(); len(unused) != 0 {
+		t.Errorf("%d interactions not replayed", len(unused))
+	}
+
+	// a request that was not recorded fails
+	_, err = bc.CreateProduct(&bigcommerce.Product{Name: "Cap", Type: "physical", Sku: "CAP", Weight: 1, Price: 5})
+	if !errors.Is(err, bcreplay.ErrNoInteraction) {
+		t.Errorf("error %v, want %v", err, bcreplay.ErrNoInteraction)
+	}
+}
+
+func TestModeEnv(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.json")
+	if err := (&bcreplay.Cassette{}).Save(existing); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(dir, "missing.json")
+	tests := []struct {
+		env     string
+		mode    bcreplay.Mode
+		path    string
+		want    bcreplay.Mode
+		wantErr bool
+	}{
+		{env: "", mode: bcreplay.ModeRecord, path: existing, want: bcreplay.ModeRecord},
+		{env: "", mode: bcreplay.ModeAuto, path: existing, want: bcreplay.ModeReplay},
+		{env: "", mode: bcreplay.ModeAuto, path: missing, want: bcreplay.ModeRecord},
+		{env: "", mode: bcreplay.ModeReplay, path: missing, wantErr: true},
+		{env: "record", mode: bcreplay.ModeReplay, path: existing, want: bcreplay.ModeRecord},
+		{env: "RECORD", mode: bcreplay.ModeReplay, path: missing, want: bcreplay.ModeRecord},
+		{env: "replay", mode: bcreplay.ModeRecord, path: existing, want: bcreplay.ModeReplay},
+		{env: "replay", mode: bcreplay.ModeAuto, path: missing, wantErr: true},
+		{env: "auto", mode: bcreplay.ModeRecord, path: existing, want: bcreplay.ModeReplay},
+		{env: "other", mode: bcreplay.ModeRecord, path: existing, want: bcreplay.ModeRecord},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env+" "+filepath.Base(tt.path), func(t *testing.T) {
+			t.Setenv(bcreplay.ModeEnv, tt.env)
+			rec, err := bcreplay.New(tt.path, tt.mode)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("no error, mode %v", rec.Mode())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rec.Mode() != tt.want {
+				t.Errorf("mode %v, want %v", rec.Mode(), tt.want)
+			}
+		})
+	}
+}
+
+func TestScrubbing(t *testing.T) {
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"live-token","context":"stores/abc","user":{"id":1}}`))
+	}))
+	defer api.Close()
+	path := filepath.Join(t.TempDir(), "auth.json")
+	body := `{"client_id":"id","client_secret":"live-secret","code":"c"}`
+
+	rec, err := bcreplay.New(path, bcreplay.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, _ := http.NewRequest(http.MethodPost, api.URL+"/oauth2/token", strings.NewReader(body))
+	req.Header.Set("X-Auth-Token", "live-auth")
+	req.Header.Set("Authorization", "Bearer live-bearer")
+	res, err := rec.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := ioutil.ReadAll(res.Body)
+	if !strings.Contains(string(got), "live-token") {
+		t.Errorf("recording changed the response given to the caller: %s", got)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	saved, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"live-secret", "live-token", "live-auth", "live-bearer", "session=abc"} {
+		if strings.Contains(string(saved), secret) {
+			t.Errorf("cassette contains %s", secret)
+		}
+	}
+	if !strings.Contains(string(saved), `"code\":\"c\"`) || !strings.Contains(string(saved), "stores/abc") {
+		t.Errorf("cassette lost fields that are not secrets:\n%s", saved)
+	}
+
+	// the request with the real secret still matches the scrubbed recording
+	rec, err = bcreplay.New(path, bcreplay.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err = rec.Post(api.URL+"/oauth2/token", "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ = ioutil.ReadAll(res.Body)
+	if !strings.Contains(string(got), bcreplay.Redacted) || strings.Contains(string(got), "live-token") {
+		t.Errorf("replayed %s", got)
+	}
+}
+
+func TestReplayOrder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "order.json")
+	c := &bcreplay.Cassette{}
+	for _, body := range []string{"first", "second"} {
+		c.Interactions = append(c.Interactions, bcreplay.Interaction{
+			Request:  bcreplay.RecordedRequest{Method: http.MethodGet, URL: "https://api.bigcommerce.com/stores/x/v2/time"},
+			Response: bcreplay.RecordedResponse{StatusCode: http.StatusOK, Status: "200 OK", Body: body},
+		})
+	}
+	if err := c.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	for _, allowRepeat := range []bool{false, true} {
+		rec, err := bcreplay.New(path, bcreplay.ModeReplay)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec.AllowRepeat = allowRepeat
+		want := []string{"first", "second", ""}
+		if allowRepeat {
+			want[2] = "first"
+		}
+		for n, w := range want {
+			res, err := rec.Get("https://api.bigcommerce.com/stores/x/v2/time")
+			if w == "" {
+				if !errors.Is(err, bcreplay.ErrNoInteraction) {
+					t.Errorf("repeat %v, request %d: error %v, want %v", allowRepeat, n+1, err, bcreplay.ErrNoInteraction)
+				}
+				continue
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _ := ioutil.ReadAll(res.Body)
+			if string(got) != w {
+				t.Errorf("repeat %v, request %d: replayed %q, want %q", allowRepeat, n+1, got, w)
+			}
+		}
+	}
+}
+
+func TestMatcher(t *testing.T) {
+	const base = "https://api.bigcommerce.com/stores/x/v3/catalog/products"
+	rec := bcreplay.RecordedRequest{Method: http.MethodPut, URL: base + "?include=variants&limit=250", Body: `{"id":1,"name":"Shirt"}`}
+	tests := []struct {
+		name    string
+		matcher bcreplay.Matcher
+		req     bcreplay.RecordedRequest
+		want    bool
+	}{
+		{"same request", bcreplay.DefaultMatcher, rec, true},
+		{"query in another order", bcreplay.DefaultMatcher,
+			bcreplay.RecordedRequest{Method: http.MethodPut, URL: base + "?limit=250&include=variants", Body: rec.Body}, true},
+		{"JSON body in another order", bcreplay.DefaultMatcher,
+			bcreplay.RecordedRequest{Method: http.MethodPut, URL: rec.URL, Body: `{"name":"Shirt","id":1}`}, true},
+		{"other method", bcreplay.DefaultMatcher,
+			bcreplay.RecordedRequest{Method: http.MethodPost, URL: rec.URL, Body: rec.Body}, false},
+		{"other method ignored", bcreplay.Matcher{Path: true, Query: true, Body: true},
+			bcreplay.RecordedRequest{Method: http.MethodPost, URL: rec.URL, Body: rec.Body}, true},
+		{"other path", bcreplay.DefaultMatcher,
+			bcreplay.RecordedRequest{Method: http.MethodPut, URL: base + "/1?include=variants&limit=250", Body: rec.Body}, false},
+		{"other host", bcreplay.DefaultMatcher,
+			bcreplay.RecordedRequest{Method: http.MethodPut, URL: strings.Replace(rec.URL, "api.", "other.", 1), Body: rec.Body}, false},
+		{"other query", bcreplay.DefaultMatcher,
+			bcreplay.RecordedRequest{Method: http.MethodPut, URL: base + "?include=variants&limit=50", Body: rec.Body}, false},
+		{"missing query parameter", bcreplay.DefaultMatcher,
+			bcreplay.RecordedRequest{Method: http.MethodPut, URL: base + "?include=variants", Body: rec.Body}, false},
+		{"ignored query parameter", bcreplay.Matcher{Method: true, Path: true, Query: true, Body: true, IgnoreQuery: []string{"limit"}},
+			bcreplay.RecordedRequest{Method: http.MethodPut, URL: base + "?include=variants&limit=50", Body: rec.Body}, true},
+		{"query ignored", bcreplay.Matcher{Method: true, Path: true, Body: true},
+			bcreplay.RecordedRequest{Method: http.MethodPut, URL: base, Body: rec.Body}, true},
+		{"other body", bcreplay.DefaultMatcher,
+			bcreplay.RecordedRequest{Method: http.MethodPut, URL: rec.URL, Body: `{"id":1,"name":"Hat"}`}, false},
+		{"body ignored", bcreplay.Matcher{Method: true, Path: true, Query: true},
+			bcreplay.RecordedRequest{Method: http.MethodPut, URL: rec.URL, Body: `{"id":1,"name":"Hat"}`}, true},
+		{"text body", bcreplay.DefaultMatcher,
+			bcreplay.RecordedRequest{Method: http.MethodPut, URL: rec.URL, Body: `{"id":1, "name":"Shirt"`}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.matcher.Match(tt.req, rec); got != tt.want {
+				t.Errorf("match %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package bctest
+
+import (
+	"bytes"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// brandRoutes registers the brands with their image, brand names are unique ignoring case
+func (s *Server) brandRoutes(brands *resource) {
+	s.handle(http.MethodPost, "/v3/catalog/brands", func(c *call) {
+		var o object
+		if !c.decode(&o) {
+			return
+		}
+		if brandNameTaken(brands, idString(o["name"]), "") {
+			writeError(c.w, http.StatusConflict, "Brand name is a duplicate", nil)
+			return
+		}
+		s.restCreate(c, brands)
+	})
+	s.handle(http.MethodPut, "/v3/catalog/brands/{id}", func(c *call) {
+		var o object
+		if !c.decode(&o) {
+			return
+		}
+		if name, ok := o["name"]; ok && brandNameTaken(brands, idString(name), c.params["id"]) {
+			writeError(c.w, http.StatusConflict, "Brand name is a duplicate", nil)
+			return
+		}
+		s.restUpdate(c, brands)
+	})
+	s.handle(http.MethodPost, "/v3/catalog/brands/{id}/image", func(c *call) { s.uploadBrandImage(c, brands) })
+	s.handle(http.MethodDelete, "/v3/catalog/brands/{id}/image", func(c *call) {
+		if _, ok := brands.coll.update(c.params["id"], object{"image_url": ""}); !ok {
+			writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+			return
+		}
+		c.w.WriteHeader(http.StatusNoContent)
+	})
+	s.rest("/v3/catalog/brands", brands)
+}
+
+// brandNameTaken checks whether another brand than exceptID has the name
+func brandNameTaken(brands *resource, name, exceptID string) bool {
+	for _, b := range brands.coll.items {
+		if idString(b["id"]) != exceptID && strings.EqualFold(strings.TrimSpace(idString(b["name"])), strings.TrimSpace(name)) {
+			return true
+		}
+	}
+	return false
+}
+
+// uploadBrandImage handles the multipart upload of a brand image
+func (s *Server) uploadBrandImage(c *call, brands *resource) {
+	if _, ok := brands.coll.get(c.params["id"]); !ok {
+		writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+		return
+	}
+	mediaType, params, _ := mime.ParseMediaType(c.r.Header.Get("Content-Type"))
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		writeError(c.w, http.StatusUnprocessableEntity, "image_file is required", nil)
+		return
+	}
+	form, err := multipart.NewReader(bytes.NewReader(c.body), params["boundary"]).ReadForm(10 << 20)
+	if err != nil {
+		writeError(c.w, http.StatusBadRequest, "Input is invalid: "+err.Error(), nil)
+		return
+	}
+	defer form.RemoveAll()
+	files := form.File["image_file"]
+	if len(files) == 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "image_file is required", nil)
+		return
+	}
+	u := "https://cdn.bctest.local/s-" + s.StoreHash + "/brands/" + c.params["id"] + "/" + path.Base(files[0].Filename)
+	brands.coll.update(c.params["id"], object{"image_url": u})
+	writeData(c.w, http.StatusOK, object{"image_url": u}, nil)
+}
@@ -0,0 +1,391 @@
+package bctest
+
+import (
+	"math"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+func (s *Server) cartRoutes() {
+	s.resources["carts"] = &resource{coll: newCollection().withUUIDs("id")}
+
+	s.handle(http.MethodPost, "/v3/carts", s.createCart)
+	s.handle(http.MethodGet, "/v3/carts/{id}", s.getCart)
+	s.handle(http.MethodPut, "/v3/carts/{id}", s.updateCart)
+	s.handle(http.MethodDelete, "/v3/carts/{id}", s.deleteCart)
+	s.handle(http.MethodPost, "/v3/carts/{id}/items", s.addCartItems)
+	s.handle(http.MethodPut, "/v3/carts/{id}/items/{item}", s.editCartItem)
+	s.handle(http.MethodDelete, "/v3/carts/{id}/items/{item}", s.deleteCartItem)
+	s.handle(http.MethodGet, "/v3/checkouts/{id}", s.getCheckout)
+	s.handle(http.MethodPost, "/v3/checkouts/{id}/discounts", s.addCheckoutDiscount)
+}
+
+// lineItem resolves a requested line item against the catalog, returns a field error if it can't
+func (s *Server) lineItem(in object) (object, map[string]string) {
+	pid := idString(in["product_id"])
+	p, ok := s.resources["products"].coll.get(pid)
+	if !ok {
+		return nil, map[string]string{"product_id": "product " + pid + " not found"}
+	}
+	variants := s.resources["variants"].coll
+	var v object
+	if vid := idString(in["variant_id"]); vid != "" && vid != "0" {
+		v, ok = variants.get(vid)
+		if !ok || idString(v["product_id"]) != pid {
+			return nil, map[string]string{"variant_id": "variant " + vid + " not found"}
+		}
+	} else {
+		v, _ = variants.get(idString(p["base_variant_id"]))
+	}
+	qty := floatValue(in["quantity"])
+	if qty <= 0 {
+		return nil, map[string]string{"quantity": "quantity must be greater than 0"}
+	}
+	price := floatValue(p["price"])
+	sku := idString(p["sku"])
+	variantID := in["variant_id"]
+	if v != nil {
+		if vp := floatValue(v["price"]); vp > 0 {
+			price = vp
+		}
+		if vs := idString(v["sku"]); vs != "" {
+			sku = vs
+		}
+		variantID = v["id"]
+	}
+	if lp, ok := in["list_price"]; ok {
+		price = floatValue(lp)
+	}
+	return object{
+		"id":                  newUUID(),
+		"product_id":          p["id"],
+		"variant_id":          variantID,
+		"sku":                 sku,
+		"name":                p["name"],
+		"quantity":            qty,
+		"list_price":          price,
+		"sale_price":          price,
+		"original_price":      price,
+		"extended_list_price": round(price * qty),
+		"extended_sale_price": round(price * qty),
+		"discount_amount":     0.0,
+		"coupon_amount":       0.0,
+		"discounts":           []interface{}{},
+		"is_require_shipping": p["type"] != "digital",
+		"is_mutable":          true,
+		"taxable":             true,
+	}, nil
+}
+
+func round(f float64) float64 {
+	return math.Round(f*100) / 100
+}
+
+// recalculate updates the cart amounts after a change
+func recalculate(cart object) {
+	base := 0.0
+	for _, li := range cartItems(cart) {
+		base += floatValue(li["extended_sale_price"])
+	}
+	discount := 0.0
+	if ds, ok := cart["discounts"].([]interface{}); ok {
+		for _, d := range ds {
+			if dm, ok := d.(map[string]interface{}); ok {
+				discount += floatValue(dm["discounted_amount"])
+			}
+		}
+	}
+	cart["base_amount"] = round(base)
+	cart["discount_amount"] = round(discount)
+	cart["cart_amount"] = round(base - discount)
+	cart["updated_time"] = time.Now().UTC().Format(time.RFC3339)
+}
+
+func cartItems(cart object) []object {
+	lis, _ := cart["line_items"].(object)
+	items, _ := lis["physical_items"].([]object)
+	return items
+}
+
+func setCartItems(cart object, items []object) {
+	lis, _ := cart["line_items"].(object)
+	lis["physical_items"] = items
+}
+
+func (s *Server) renderCart(cart object, q url.Values) object {
+	c := copyObject(cart)
+	if strings.Contains(q.Get("include"), "redirect_urls") {
+		base := "https://" + idString(s.storeInfo["domain"])
+		id := idString(cart["id"])
+		c["redirect_urls"] = object{
+			"cart_url":              base + "/cart.php?action=load&id=" + id,
+			"checkout_url":          base + "/cart.php?action=loadInCheckout&id=" + id,
+			"embedded_checkout_url": base + "/cart.php?embedded=1&action=loadInCheckout&id=" + id,
+		}
+	}
+	return c
+}
+
+// cart returns the cart in the path, writing a 404 response if it doesn't exist
+func (s *Server) cart(c *call) (object, bool) {
+	cart, ok := s.resources["carts"].coll.get(c.params["id"])
+	if !ok {
+		writeError(c.w, http.StatusNotFound, "Cart not found", nil)
+	}
+	return cart, ok
+}
+
+func (s *Server) createCart(c *call) {
+	var in struct {
+		ChannelID  int64    `json:"channel_id"`
+		CustomerID int64    `json:"customer_id"`
+		Email      string   `json:"email"`
+		LineItems  []object `json:"line_items"`
+	}
+	if !c.decode(&in) {
+		return
+	}
+	if len(in.LineItems) == 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid",
+			map[string]string{"line_items": "line_items is a required field"})
+		return
+	}
+	items := []object{}
+	for _, li := range in.LineItems {
+		item, errs := s.lineItem(li)
+		if errs != nil {
+			writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+			return
+		}
+		items = append(items, item)
+	}
+	if in.ChannelID == 0 {
+		in.ChannelID = 1
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	cart := object{
+		"customer_id":  float64(in.CustomerID),
+		"channel_id":   float64(in.ChannelID),
+		"email":        in.Email,
+		"currency":     object{"code": idString(s.storeInfo["currency"])},
+		"tax_included": false,
+		"discounts":    []interface{}{},
+		"coupons":      []interface{}{},
+		"line_items": object{
+			"physical_items":    items,
+			"digital_items":     []object{},
+			"gift_certificates": []object{},
+			"custom_items":      []object{},
+		},
+		"created_time": now,
+		"locale":       "en",
+	}
+	cart = s.resources["carts"].coll.insert(cart)
+	recalculate(cart)
+	writeData(c.w, http.StatusCreated, s.renderCart(cart, c.query), nil)
+}
+
+func (s *Server) getCart(c *call) {
+	cart, ok := s.cart(c)
+	if !ok {
+		return
+	}
+	writeData(c.w, http.StatusOK, s.renderCart(cart, c.query), nil)
+}
+
+func (s *Server) updateCart(c *call) {
+	cart, ok := s.cart(c)
+	if !ok {
+		return
+	}
+	var in struct {
+		CustomerID *int64 `json:"customer_id"`
+	}
+	if !c.decode(&in) {
+		return
+	}
+	if in.CustomerID != nil {
+		if _, ok := s.resources["customers"].coll.get(idString(float64(*in.CustomerID))); !ok && *in.CustomerID != 0 {
+			writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid",
+				map[string]string{"customer_id": "customer not found"})
+			return
+		}
+		cart["customer_id"] = float64(*in.CustomerID)
+	}
+	recalculate(cart)
+	writeData(c.w, http.StatusOK, s.renderCart(cart, c.query), nil)
+}
+
+func (s *Server) deleteCart(c *call) {
+	if _, ok := s.cart(c); !ok {
+		return
+	}
+	s.resources["carts"].coll.remove(c.params["id"])
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) addCartItems(c *call) {
+	cart, ok := s.cart(c)
+	if !ok {
+		return
+	}
+	var in struct {
+		LineItems []object `json:"line_items"`
+	}
+	if !c.decode(&in) {
+		return
+	}
+	items := cartItems(cart)
+	for _, li := range in.LineItems {
+		item, errs := s.lineItem(li)
+		if errs != nil {
+			writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+			return
+		}
+		merged := false
+		for _, existing := range items {
+			if idString(existing["variant_id"]) == idString(item["variant_id"]) &&
+				idString(existing["product_id"]) == idString(item["product_id"]) {
+				setQuantity(existing, floatValue(existing["quantity"])+floatValue(item["quantity"]))
+				merged = true
+			}
+		}
+		if !merged {
+			items = append(items, item)
+		}
+	}
+	setCartItems(cart, items)
+	recalculate(cart)
+	writeData(c.w, http.StatusCreated, s.renderCart(cart, c.query), nil)
+}
+
+func setQuantity(item object, qty float64) {
+	item["quantity"] = qty
+	item["extended_list_price"] = round(floatValue(item["list_price"]) * qty)
+	item["extended_sale_price"] = round(floatValue(item["sale_price"]) * qty)
+}
+
+func (s *Server) editCartItem(c *call) {
+	cart, ok := s.cart(c)
+	if !ok {
+		return
+	}
+	var in struct {
+		LineItem object `json:"line_item"`
+	}
+	if !c.decode(&in) {
+		return
+	}
+	for _, item := range cartItems(cart) {
+		if idString(item["id"]) != c.params["item"] {
+			continue
+		}
+		qty := floatValue(in.LineItem["quantity"])
+		if qty <= 0 {
+			writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid",
+				map[string]string{"quantity": "quantity must be greater than 0"})
+			return
+		}
+		setQuantity(item, qty)
+		recalculate(cart)
+		writeData(c.w, http.StatusOK, s.renderCart(cart, c.query), nil)
+		return
+	}
+	writeError(c.w, http.StatusNotFound, "Line item not found", nil)
+}
+
+// deleteCartItem removes a line item, an empty cart is deleted like in BigCommerce
+func (s *Server) deleteCartItem(c *call) {
+	cart, ok := s.cart(c)
+	if !ok {
+		return
+	}
+	items := cartItems(cart)
+	for i, item := range items {
+		if idString(item["id"]) != c.params["item"] {
+			continue
+		}
+		items = append(items[:i], items[i+1:]...)
+		if len(items) == 0 {
+			s.resources["carts"].coll.remove(c.params["id"])
+			c.w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		setCartItems(cart, items)
+		recalculate(cart)
+		writeData(c.w, http.StatusOK, s.renderCart(cart, c.query), nil)
+		return
+	}
+	writeError(c.w, http.StatusNotFound, "Line item not found", nil)
+}
+
+// checkout builds the checkout of a cart, checkout IDs are cart IDs
+func (s *Server) checkout(cart object) object {
+	subtotal := floatValue(cart["cart_amount"])
+	return object{
+		"id":                          cart["id"],
+		"cart":                        cart,
+		"billing_address":             object{},
+		"consignments":                []interface{}{},
+		"taxes":                       []interface{}{},
+		"coupons":                     []interface{}{},
+		"shipping_cost_total_inc_tax": 0.0,
+		"shipping_cost_total_ex_tax":  0.0,
+		"handling_cost_total_inc_tax": 0.0,
+		"handling_cost_total_ex_tax":  0.0,
+		"tax_total":                   0.0,
+		"subtotal_inc_tax":            subtotal,
+		"subtotal_ex_tax":             subtotal,
+		"grand_total":                 subtotal,
+		"created_time":                cart["created_time"],
+		"updated_time":                cart["updated_time"],
+	}
+}
+
+func (s *Server) getCheckout(c *call) {
+	cart, ok := s.cart(c)
+	if !ok {
+		return
+	}
+	writeData(c.w, http.StatusOK, s.checkout(cart), nil)
+}
+
+func (s *Server) addCheckoutDiscount(c *call) {
+	cart, ok := s.cart(c)
+	if !ok {
+		return
+	}
+	// the API documents "cart", older clients send "carts"
+	type discounts struct {
+		Discounts []object `json:"discounts"`
+	}
+	var in struct {
+		Cart  discounts `json:"cart"`
+		Carts discounts `json:"carts"`
+	}
+	if !c.decode(&in) {
+		return
+	}
+	ds, _ := cart["discounts"].([]interface{})
+	for _, d := range append(in.Cart.Discounts, in.Carts.Discounts...) {
+		ds = append(ds, map[string]interface{}{
+			"id":                newUUID(),
+			"discounted_amount": floatValue(d["discounted_amount"]),
+			"name":              d["name"],
+		})
+	}
+	cart["discounts"] = ds
+	recalculate(cart)
+	writeData(c.w, http.StatusOK, s.checkout(cart), nil)
+}
+
+// Carts returns the carts currently in the store
+func (s *Server) Carts() []bigcommerce.Cart {
+	var ret []bigcommerce.Cart
+	s.snapshot("carts", &ret)
+	return ret
+}
@@ -0,0 +1,405 @@
+package bctest
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+func (s *Server) catalogRoutes() {
+	variants := &resource{
+		coll:        newCollection(),
+		parentParam: "product_id",
+		parentKey:   "product_id",
+		batchUpdate: true,
+		defaults:    object{"option_values": []interface{}{}},
+		render:      renderVariant,
+	}
+	allVariants := &resource{coll: variants.coll, batchUpdate: true, render: renderVariant}
+	images := &resource{
+		coll:        newCollection(),
+		parentParam: "product_id",
+		parentKey:   "product_id",
+		defaults:    object{"description": "", "sort_order": float64(0), "is_thumbnail": false},
+	}
+	images.created = func(o object) { s.imageSaved(o, images) }
+	images.updated = images.created
+	products := &resource{
+		coll:        newCollection().withDates(time.RFC3339),
+		batchUpdate: true,
+		required:    []string{"name", "type"},
+	}
+	products.created = func(o object) {
+		s.productCreated(o, variants, images)
+	}
+	products.removed = func(o object) {
+		id := idString(o["id"])
+		for _, v := range variants.coll.find(url.Values{"product_id": {id}}) {
+			variants.coll.remove(idString(v["id"]))
+		}
+		for _, i := range images.coll.find(url.Values{"product_id": {id}}) {
+			images.coll.remove(idString(i["id"]))
+		}
+	}
+	products.render = func(o object, q url.Values) object {
+		return s.renderProduct(o, q, variants, images)
+	}
+	assignments := &resource{
+		coll:   newCollection(),
+		render: renderAssignment,
+	}
+
+	s.resources["products"] = products
+	s.resources["variants"] = variants
+	s.resources["images"] = images
+	s.resources["channel_assignments"] = assignments
+	s.resources["brands"] = &resource{coll: newCollection(), required: []string{"name"}}
+	s.resources["categories"] = &resource{
+		coll:     newCollection(),
+		required: []string{"name"},
+		defaults: object{"parent_id": float64(0), "is_visible": true},
+	}
+
+	// the fixed paths have to be registered before /v3/catalog/products/{id}
+	s.handle(http.MethodGet, "/v3/catalog/products/channel-assignments", func(c *call) { s.restList(c, assignments) })
+	s.handle(http.MethodPut, "/v3/catalog/products/channel-assignments", func(c *call) { s.upsertAssignments(c, assignments) })
+	s.handle(http.MethodDelete, "/v3/catalog/products/channel-assignments", func(c *call) { s.restBatchDelete(c, assignments) })
+	s.categoryAssignmentRoutes(products)
+	s.handle(http.MethodGet, "/v3/catalog/variants", func(c *call) { s.restList(c, allVariants) })
+	s.handle(http.MethodPut, "/v3/catalog/variants", func(c *call) { s.restBatchUpdate(c, allVariants) })
+	s.rest("/v3/catalog/products", products)
+	s.rest("/v3/catalog/products/{product_id}/variants", variants)
+	s.handle(http.MethodPost, "/v3/catalog/products/{product_id}/images", func(c *call) { s.createImage(c, images) })
+	s.rest("/v3/catalog/products/{product_id}/images", images)
+	for _, sub := range []string{"videos", "custom-fields", "bulk-pricing-rules"} {
+		s.resources[sub] = &resource{coll: newCollection(), parentParam: "product_id", parentKey: "product_id"}
+		s.rest("/v3/catalog/products/{product_id}/"+sub, s.resources[sub])
+	}
+	s.resources["custom-fields"].required = []string{"name", "value"}
+	s.resources["bulk-pricing-rules"].required = []string{"quantity_min", "type", "amount"}
+	s.resources["videos"].required = []string{"video_id"}
+	s.optionRoutes("options")
+	s.optionRoutes("modifiers")
+	s.brandRoutes(s.resources["brands"])
+	s.treeRoutes(s.resources["categories"])
+	s.rest("/v3/catalog/categories", s.resources["categories"])
+}
+
+// productCreated moves nested variants and images to their own collections,
+// products without variants get a base variant like in BigCommerce
+func (s *Server) productCreated(o object, variants, images *resource) {
+	id := o["id"]
+	nested, _ := o["variants"].([]interface{})
+	delete(o, "variants")
+	if len(nested) == 0 {
+		nested = []interface{}{map[string]interface{}{"sku": o["sku"], "price": o["price"]}}
+	}
+	for i, n := range nested {
+		v, ok := n.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		v["product_id"] = id
+		if _, ok := v["option_values"]; !ok {
+			v["option_values"] = []interface{}{}
+		}
+		v = variants.coll.insert(v)
+		if i == 0 {
+			o["base_variant_id"] = v["id"]
+		}
+	}
+	nestedImages, _ := o["images"].([]interface{})
+	delete(o, "images")
+	for _, n := range nestedImages {
+		img, ok := n.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		img["product_id"] = id
+		images.coll.insert(img)
+	}
+}
+
+// renderProduct adds calculated fields and the requested subresources
+func (s *Server) renderProduct(o object, q url.Values, variants, images *resource) object {
+	p := copyObject(o)
+	if _, ok := p["calculated_price"]; !ok {
+		p["calculated_price"] = p["price"]
+	}
+	include := q.Get("include")
+	id := idString(o["id"])
+	if strings.Contains(include, "variants") {
+		vs := []object{}
+		for _, v := range variants.coll.find(url.Values{"product_id": {id}}) {
+			vs = append(vs, renderVariant(v, nil))
+		}
+		p["variants"] = vs
+	}
+	for _, sub := range []string{"videos", "custom_fields", "bulk_pricing_rules"} {
+		if strings.Contains(include, sub) {
+			p[sub] = s.resources[strings.ReplaceAll(sub, "_", "-")].coll.find(url.Values{"product_id": {id}})
+		}
+	}
+	if strings.Contains(include, "images") || strings.Contains(include, "primary_image") {
+		is := images.coll.find(url.Values{"product_id": {id}})
+		if strings.Contains(include, "images") {
+			p["images"] = is
+		}
+		if strings.Contains(include, "primary_image") {
+			for _, i := range is {
+				if i["is_thumbnail"] == true {
+					p["primary_image"] = i
+				}
+			}
+		}
+	}
+	return p
+}
+
+// optionRoutes registers the options or modifiers of products with their values,
+// values have their own collection and are rendered in option_values
+func (s *Server) optionRoutes(name string) {
+	values := &resource{
+		coll:        newCollection(),
+		parentParam: "option_id",
+		parentKey:   "option_id",
+		required:    []string{"label"},
+	}
+	options := &resource{
+		coll:        newCollection(),
+		parentParam: "product_id",
+		parentKey:   "product_id",
+		required:    []string{"display_name", "type"},
+	}
+	options.created = func(o object) {
+		nested, _ := o["option_values"].([]interface{})
+		delete(o, "option_values")
+		for _, n := range nested {
+			v, ok := n.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			v["option_id"] = o["id"]
+			values.coll.insert(v)
+		}
+	}
+	options.removed = func(o object) {
+		for _, v := range values.coll.find(url.Values{"option_id": {idString(o["id"])}}) {
+			values.coll.remove(idString(v["id"]))
+		}
+	}
+	options.render = func(o object, q url.Values) object {
+		ret := copyObject(o)
+		ret["option_values"] = values.coll.find(url.Values{"option_id": {idString(o["id"])}})
+		return ret
+	}
+	s.resources[name] = options
+	s.rest("/v3/catalog/products/{product_id}/"+name, options)
+	s.rest("/v3/catalog/products/{product_id}/"+name+"/{option_id}/values", values)
+}
+
+// createImage handles POST /v3/catalog/products/{product_id}/images with a JSON
+// body with image_url or a multipart form with an image_file upload
+func (s *Server) createImage(c *call, images *resource) {
+	o := object{}
+	mediaType, params, _ := mime.ParseMediaType(c.r.Header.Get("Content-Type"))
+	if strings.HasPrefix(mediaType, "multipart/") {
+		form, err := multipart.NewReader(bytes.NewReader(c.body), params["boundary"]).ReadForm(10 << 20)
+		if err != nil {
+			writeError(c.w, http.StatusBadRequest, "Input is invalid: "+err.Error(), nil)
+			return
+		}
+		defer form.RemoveAll()
+		files := form.File["image_file"]
+		if len(files) == 0 {
+			writeError(c.w, http.StatusUnprocessableEntity, "image_file or image_url is required", nil)
+			return
+		}
+		o["image_file"] = files[0].Filename
+		if v := form.Value["description"]; len(v) > 0 {
+			o["description"] = v[0]
+		}
+		if v := form.Value["sort_order"]; len(v) > 0 {
+			o["sort_order"] = float64(intValue(v[0]))
+		}
+		if v := form.Value["is_thumbnail"]; len(v) > 0 {
+			o["is_thumbnail"] = v[0] == "true"
+		}
+	} else {
+		if !c.decode(&o) {
+			return
+		}
+		src := idString(o["image_url"])
+		if src == "" {
+			writeError(c.w, http.StatusUnprocessableEntity, "image_file or image_url is required", nil)
+			return
+		}
+		if u, err := url.Parse(src); err == nil {
+			src = u.Path
+		}
+		o["image_file"] = path.Base(src)
+	}
+	// stored like BigCommerce does: shirt.jpg becomes s/123/shirt__00042.jpg
+	name := idString(o["image_file"])
+	ext := path.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	if base == "" {
+		base = "image"
+	}
+	o["image_file"] = fmt.Sprintf("%s/%s/%s__%05d%s", strings.ToLower(base[:1]), c.params["product_id"], base, images.coll.nextID, ext)
+	o = s.insert(c, images, o)
+	writeData(c.w, http.StatusOK, images.output(o, c.query), nil)
+}
+
+// imageSaved sets the image URLs and keeps a single thumbnail per product
+func (s *Server) imageSaved(o object, images *resource) {
+	file := idString(o["image_file"])
+	for k, size := range map[string]string{"url_zoom": "1280x1280", "url_standard": "500x659", "url_thumbnail": "100x100", "url_tiny": "30x30"} {
+		if idString(o[k]) == "" && file != "" {
+			o[k] = "https://cdn.bctest.local/s-" + s.StoreHash + "/products/" + idString(o["product_id"]) + "/images/" + idString(o["id"]) + "/" + path.Base(file) + "?size=" + size
+		}
+	}
+	o["date_modified"] = time.Now().UTC().Format(time.RFC3339)
+	if o["is_thumbnail"] != true {
+		return
+	}
+	for _, i := range images.coll.find(url.Values{"product_id": {idString(o["product_id"])}}) {
+		if idString(i["id"]) != idString(o["id"]) {
+			i["is_thumbnail"] = false
+		}
+	}
+}
+
+func renderVariant(o object, q url.Values) object {
+	v := copyObject(o)
+	if _, ok := v["calculated_price"]; !ok {
+		v["calculated_price"] = v["price"]
+	}
+	return v
+}
+
+// renderAssignment hides the internal ID of a channel assignment
+func renderAssignment(o object, q url.Values) object {
+	return object{"product_id": o["product_id"], "channel_id": o["channel_id"]}
+}
+
+// upsertAssignments handles PUT /v3/catalog/products/channel-assignments, nothing is assigned
+// when a product is missing
+func (s *Server) upsertAssignments(c *call, assignments *resource) {
+	var in []object
+	if !c.decode(&in) {
+		return
+	}
+	errs := map[string]string{}
+	for i, a := range in {
+		if _, ok := s.resources["products"].coll.get(idString(a["product_id"])); !ok {
+			errs[strconv.Itoa(i)+".product_id"] = "product " + idString(a["product_id"]) + " not found"
+		}
+		if intValue(a["channel_id"]) == 0 {
+			errs[strconv.Itoa(i)+".channel_id"] = "channel_id is a required field"
+		}
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	for _, a := range in {
+		q := url.Values{
+			"product_id": {idString(a["product_id"])},
+			"channel_id": {idString(a["channel_id"])},
+		}
+		if len(assignments.coll.find(q)) > 0 {
+			continue
+		}
+		assignments.coll.insert(object{"product_id": a["product_id"], "channel_id": a["channel_id"]})
+	}
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+// AddProduct adds a product to the store, nested variants and images are stored too.
+// Products without variants get a base variant. Returns the product with its assigned ID.
+func (s *Server) AddProduct(p bigcommerce.Product) bigcommerce.Product {
+	var ret bigcommerce.Product
+	decodeObject(s.seed("products", p), &ret)
+	return ret
+}
+
+// AddVariant adds a variant to a product
+func (s *Server) AddVariant(productID int64, v bigcommerce.Variant) bigcommerce.Variant {
+	v.ProductID = productID
+	var ret bigcommerce.Variant
+	decodeObject(s.seed("variants", v), &ret)
+	return ret
+}
+
+// AddImage adds an image to a product
+func (s *Server) AddImage(productID int64, img bigcommerce.Image) bigcommerce.Image {
+	img.ProductID = productID
+	var ret bigcommerce.Image
+	decodeObject(s.seed("images", img), &ret)
+	return ret
+}
+
+// AddProductMetafield adds a metafield to a product
+func (s *Server) AddProductMetafield(productID int64, m bigcommerce.Metafield) bigcommerce.Metafield {
+	return s.AddMetafield(bigcommerce.MetafieldProduct, strconv.FormatInt(productID, 10), m)
+}
+
+// AddVariantOption adds a variant option with its values to a product
+func (s *Server) AddVariantOption(productID int64, opt bigcommerce.VariantOption) bigcommerce.VariantOption {
+	opt.ProductID = productID
+	var ret bigcommerce.VariantOption
+	o := s.seed("options", opt)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	decodeObject(s.resources["options"].render(o, nil), &ret)
+	return ret
+}
+
+// AddModifier adds a modifier with its values to a product
+func (s *Server) AddModifier(productID int64, m bigcommerce.Modifier) bigcommerce.Modifier {
+	m.ProductID = productID
+	var ret bigcommerce.Modifier
+	o := s.seed("modifiers", m)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	decodeObject(s.resources["modifiers"].render(o, nil), &ret)
+	return ret
+}
+
+// AddBrand adds a brand to the store
+func (s *Server) AddBrand(b bigcommerce.Brand) bigcommerce.Brand {
+	var ret bigcommerce.Brand
+	decodeObject(s.seed("brands", b), &ret)
+	return ret
+}
+
+// AddCategory adds a category to the store
+func (s *Server) AddCategory(cat bigcommerce.Category) bigcommerce.Category {
+	var ret bigcommerce.Category
+	decodeObject(s.seed("categories", cat), &ret)
+	return ret
+}
+
+// Products returns the products currently in the store
+func (s *Server) Products() []bigcommerce.Product {
+	var ret []bigcommerce.Product
+	s.snapshot("products", &ret)
+	return ret
+}
+
+// Variants returns the variants currently in the store
+func (s *Server) Variants() []bigcommerce.Variant {
+	var ret []bigcommerce.Variant
+	s.snapshot("variants", &ret)
+	return ret
+}
@@ -0,0 +1,177 @@
+package bctest
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// categoryAssignmentRoutes registers the category assignments, kept in the categories of the products,
+// and the sort order of the products of a category. They have to be registered before the products.
+func (s *Server) categoryAssignmentRoutes(products *resource) {
+	sortOrders := &resource{coll: newCollection()}
+	s.resources["category_sort_orders"] = sortOrders
+
+	s.handle(http.MethodGet, "/v3/catalog/products/category-assignments", func(c *call) { s.listCategoryAssignments(c, products) })
+	s.handle(http.MethodPut, "/v3/catalog/products/category-assignments", func(c *call) { s.createCategoryAssignments(c, products) })
+	s.handle(http.MethodDelete, "/v3/catalog/products/category-assignments", func(c *call) { s.deleteCategoryAssignments(c, products) })
+	s.handle(http.MethodGet, "/v3/catalog/categories/{category_id}/products/sort-order", func(c *call) { s.getSortOrder(c, products, sortOrders) })
+	s.handle(http.MethodPut, "/v3/catalog/categories/{category_id}/products/sort-order", func(c *call) { s.setSortOrder(c, products, sortOrders) })
+}
+
+// productCategories returns the category IDs of a product
+func productCategories(p object) []string {
+	ret := []string{}
+	list, _ := p["categories"].([]interface{})
+	for _, id := range list {
+		ret = append(ret, idString(id))
+	}
+	return ret
+}
+
+// inFilter returns the values of an :in filter, nil if there is none
+func inFilter(q url.Values, key string) []string {
+	if v := q.Get(key); v != "" {
+		return strings.Split(v, ",")
+	}
+	return nil
+}
+
+// categoryAssignments returns the assignments matching the product_id:in and category_id:in filters
+func categoryAssignments(products *resource, q url.Values) []object {
+	productIDs, categoryIDs := inFilter(q, "product_id:in"), inFilter(q, "category_id:in")
+	ret := []object{}
+	for _, p := range products.coll.find(url.Values{}) {
+		if productIDs != nil && !contains(productIDs, idString(p["id"])) {
+			continue
+		}
+		for _, cat := range productCategories(p) {
+			if categoryIDs != nil && !contains(categoryIDs, cat) {
+				continue
+			}
+			ret = append(ret, object{"product_id": p["id"], "category_id": float64(intValue(cat))})
+		}
+	}
+	return ret
+}
+
+func (s *Server) listCategoryAssignments(c *call, products *resource) {
+	p := paginate(categoryAssignments(products, c.query), c.query)
+	writeData(c.w, http.StatusOK, p.items, p.meta)
+}
+
+func (s *Server) createCategoryAssignments(c *call, products *resource) {
+	var in []object
+	if !c.decode(&in) {
+		return
+	}
+	errs := map[string]string{}
+	for i, a := range in {
+		if _, ok := products.coll.get(idString(a["product_id"])); !ok {
+			errs[strconv.Itoa(i)+".product_id"] = "product " + idString(a["product_id"]) + " not found"
+		}
+		if _, ok := s.resources["categories"].coll.get(idString(a["category_id"])); !ok {
+			errs[strconv.Itoa(i)+".category_id"] = "category " + idString(a["category_id"]) + " not found"
+		}
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	for _, a := range in {
+		p, _ := products.coll.get(idString(a["product_id"]))
+		if !contains(productCategories(p), idString(a["category_id"])) {
+			list, _ := p["categories"].([]interface{})
+			p["categories"] = append(list, float64(intValue(idString(a["category_id"]))))
+		}
+	}
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) deleteCategoryAssignments(c *call, products *resource) {
+	productIDs, categoryIDs := inFilter(c.query, "product_id:in"), inFilter(c.query, "category_id:in")
+	if productIDs == nil && categoryIDs == nil {
+		writeError(c.w, http.StatusUnprocessableEntity, "At least one filter is required", nil)
+		return
+	}
+	for _, p := range products.coll.find(url.Values{}) {
+		if productIDs != nil && !contains(productIDs, idString(p["id"])) {
+			continue
+		}
+		kept := []interface{}{}
+		for _, cat := range productCategories(p) {
+			if categoryIDs == nil || contains(categoryIDs, cat) {
+				continue
+			}
+			kept = append(kept, float64(intValue(cat)))
+		}
+		p["categories"] = kept
+	}
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+// getSortOrder lists the products of a category by sort order, products without one are last
+func (s *Server) getSortOrder(c *call, products, sortOrders *resource) {
+	catID := c.params["category_id"]
+	if _, ok := s.resources["categories"].coll.get(catID); !ok {
+		writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+		return
+	}
+	items := []object{}
+	for _, a := range categoryAssignments(products, url.Values{"category_id:in": {catID}}) {
+		order := float64(-1)
+		stored := sortOrders.coll.find(url.Values{"category_id": {catID}, "product_id": {idString(a["product_id"])}})
+		if len(stored) > 0 {
+			order = floatValue(stored[0]["sort_order"])
+		}
+		items = append(items, object{"product_id": a["product_id"], "sort_order": order})
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		oi, oj := floatValue(items[i]["sort_order"]), floatValue(items[j]["sort_order"])
+		if (oi < 0) != (oj < 0) {
+			return oj < 0
+		}
+		return oi < oj
+	})
+	for i, o := range items {
+		if floatValue(o["sort_order"]) < 0 {
+			o["sort_order"] = float64(len(items) + i)
+		}
+	}
+	p := paginate(items, c.query)
+	writeData(c.w, http.StatusOK, p.items, p.meta)
+}
+
+// setSortOrder stores the sort order of products assigned to the category
+func (s *Server) setSortOrder(c *call, products, sortOrders *resource) {
+	catID := c.params["category_id"]
+	if _, ok := s.resources["categories"].coll.get(catID); !ok {
+		writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+		return
+	}
+	var in []object
+	if !c.decode(&in) {
+		return
+	}
+	errs := map[string]string{}
+	for i, o := range in {
+		p, ok := products.coll.get(idString(o["product_id"]))
+		if !ok || !contains(productCategories(p), catID) {
+			errs[strconv.Itoa(i)+".product_id"] = "product " + idString(o["product_id"]) + " is not assigned to category " + catID
+		}
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	for _, o := range in {
+		q := url.Values{"category_id": {catID}, "product_id": {idString(o["product_id"])}}
+		for _, old := range sortOrders.coll.find(q) {
+			sortOrders.coll.remove(idString(old["id"]))
+		}
+		sortOrders.coll.insert(object{"category_id": float64(intValue(catID)), "product_id": o["product_id"], "sort_order": o["sort_order"]})
+	}
+	c.w.WriteHeader(http.StatusNoContent)
+}
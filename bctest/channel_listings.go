@@ -0,0 +1,160 @@
+package bctest
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+// channelListingRoutes registers the listings of the channels, a product is listed at most once per channel
+func (s *Server) channelListingRoutes() {
+	listings := &resource{coll: newCollection().withDates(time.RFC3339), render: renderListing}
+	s.resources["channel_listings"] = listings
+
+	s.handle(http.MethodGet, "/v3/channels/{channel_id}/listings", func(c *call) { s.listListings(c, listings) })
+	s.handle(http.MethodPost, "/v3/channels/{channel_id}/listings", func(c *call) { s.createListings(c, listings) })
+	s.handle(http.MethodPut, "/v3/channels/{channel_id}/listings", func(c *call) { s.updateListings(c, listings) })
+	s.handle(http.MethodGet, "/v3/channels/{channel_id}/listings/{listing_id}", func(c *call) {
+		o, ok := listings.coll.get(c.params["listing_id"])
+		if !ok || idString(o["channel_id"]) != c.params["channel_id"] {
+			writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+			return
+		}
+		writeData(c.w, http.StatusOK, renderListing(o, c.query), nil)
+	})
+}
+
+// renderListing names the ID listing_id like the listings API
+func renderListing(o object, q url.Values) object {
+	ret := copyObject(o)
+	ret["listing_id"] = o["id"]
+	delete(ret, "id")
+	return ret
+}
+
+// listListings handles GET /v3/channels/{channel_id}/listings, paged with limit and after,
+// the ID of the last listing of the previous page
+func (s *Server) listListings(c *call, listings *resource) {
+	limit, _ := strconv.Atoi(c.query.Get("limit"))
+	if limit <= 0 || limit > 250 {
+		limit = 250
+	}
+	after := intValue(c.query.Get("after"))
+	q := url.Values{"channel_id": {c.params["channel_id"]}}
+	if ids := c.query.Get("product_id:in"); ids != "" {
+		q.Set("product_id:in", ids)
+	}
+	items := listings.coll.find(q)
+	sort.Slice(items, func(i, j int) bool { return intValue(items[i]["id"]) < intValue(items[j]["id"]) })
+	data := []object{}
+	for _, o := range items {
+		if intValue(o["id"]) > after && len(data) < limit {
+			data = append(data, renderListing(o, c.query))
+		}
+	}
+	writeData(c.w, http.StatusOK, data, object{"pagination": object{"count": len(data), "limit": limit, "total": len(items)}})
+}
+
+// setListingVariants sets the product of the variant listings and their dates
+func setListingVariants(o object, now string) {
+	variants, _ := o["variants"].([]interface{})
+	for _, v := range variants {
+		if vo, ok := v.(map[string]interface{}); ok {
+			vo["product_id"] = o["product_id"]
+			if d := idString(vo["date_created"]); d == "" || strings.HasPrefix(d, "0001-01-01") {
+				vo["date_created"] = now
+			}
+			vo["date_modified"] = now
+		}
+	}
+	if o["variants"] == nil {
+		o["variants"] = []interface{}{}
+	}
+}
+
+// createListings handles POST /v3/channels/{channel_id}/listings
+func (s *Server) createListings(c *call, listings *resource) {
+	var in []object
+	if !c.decode(&in) {
+		return
+	}
+	errs := map[string]string{}
+	for i, o := range in {
+		pid := idString(o["product_id"])
+		if _, ok := s.resources["products"].coll.get(pid); !ok {
+			errs[strconv.Itoa(i)+".product_id"] = "product " + pid + " not found"
+			continue
+		}
+		if idString(o["state"]) == "" {
+			errs[strconv.Itoa(i)+".state"] = "state is a required field"
+		}
+		if len(listings.coll.find(url.Values{"channel_id": {c.params["channel_id"]}, "product_id": {pid}})) > 0 {
+			errs[strconv.Itoa(i)+".product_id"] = "product " + pid + " is already listed in the channel"
+		}
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	data := []object{}
+	for _, o := range in {
+		delete(o, "listing_id")
+		delete(o, "date_created")
+		delete(o, "date_modified")
+		o["channel_id"] = float64(intValue(c.params["channel_id"]))
+		setListingVariants(o, now)
+		data = append(data, renderListing(listings.coll.insert(o), c.query))
+	}
+	writeData(c.w, http.StatusOK, data, nil)
+}
+
+// updateListings handles PUT /v3/channels/{channel_id}/listings, listings are matched by listing_id
+func (s *Server) updateListings(c *call, listings *resource) {
+	var in []object
+	if !c.decode(&in) {
+		return
+	}
+	errs := map[string]string{}
+	for i, o := range in {
+		old, ok := listings.coll.get(idString(o["listing_id"]))
+		if !ok || idString(old["channel_id"]) != c.params["channel_id"] {
+			errs[strconv.Itoa(i)+".listing_id"] = "listing " + idString(o["listing_id"]) + " not found"
+		}
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	data := []object{}
+	for _, o := range in {
+		id := idString(o["listing_id"])
+		old, _ := listings.coll.get(id)
+		for _, k := range []string{"listing_id", "channel_id", "product_id", "date_created", "date_modified"} {
+			delete(o, k)
+		}
+		o["product_id"] = old["product_id"]
+		setListingVariants(o, now)
+		updated, _ := listings.coll.update(id, o)
+		data = append(data, renderListing(updated, c.query))
+	}
+	writeData(c.w, http.StatusOK, data, nil)
+}
+
+// AddListing adds a listing to a channel
+func (s *Server) AddListing(channelID int64, l bigcommerce.ChannelListing) bigcommerce.ChannelListing {
+	l.ChannelID = channelID
+	o := toObject(l)
+	o["id"] = o["listing_id"]
+	delete(o, "listing_id")
+	setListingVariants(o, time.Now().UTC().Format(time.RFC3339))
+	var ret bigcommerce.ChannelListing
+	decodeObject(renderListing(s.seed("channel_listings", o), nil), &ret)
+	return ret
+}
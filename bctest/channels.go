@@ -0,0 +1,259 @@
+package bctest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+// channelRoutes registers the sites and routes of the channels, their currency assignments and menus.
+// It has to be called before the channels routes.
+func (s *Server) channelRoutes() {
+	sites := &resource{coll: newCollection(), required: []string{"url", "channel_id"}}
+	routes := &resource{
+		coll:        newCollection(),
+		parentParam: "site_id",
+		parentKey:   "site_id",
+		required:    []string{"type", "route"},
+		defaults:    object{"matching": "*"},
+	}
+	currencies := &resource{coll: newCollection()}
+	menus := &resource{coll: newCollection()}
+	s.resources["sites"] = sites
+	s.resources["site_routes"] = routes
+	s.resources["channel_currencies"] = currencies
+	s.resources["channel_menus"] = menus
+
+	sites.created = func(o object) {
+		now := time.Now().UTC().Format(time.RFC3339)
+		o["created_at"], o["updated_at"] = now, now
+		o["ssl_status"] = "dedicated"
+		setSiteURLs(o)
+	}
+	sites.updated = func(o object) {
+		o["updated_at"] = time.Now().UTC().Format(time.RFC3339)
+		setSiteURLs(o)
+	}
+	sites.removed = func(o object) {
+		for _, r := range routes.coll.find(url.Values{"site_id": {idString(o["id"])}}) {
+			routes.coll.remove(idString(r["id"]))
+		}
+	}
+
+	s.handle(http.MethodPost, "/v3/sites", func(c *call) { s.createSite(c, sites) })
+	s.rest("/v3/sites", sites)
+	s.handle(http.MethodPut, "/v3/sites/{site_id}/routes", func(c *call) { s.upsertRoutes(c, sites, routes) })
+	s.handle(http.MethodPost, "/v3/sites/{site_id}/routes", func(c *call) {
+		if _, ok := sites.coll.get(c.params["site_id"]); !ok {
+			writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+			return
+		}
+		s.restCreate(c, routes)
+	})
+	s.rest("/v3/sites/{site_id}/routes", routes)
+	s.handle(http.MethodGet, "/v3/channels/{channel_id}/site", func(c *call) {
+		found := sites.coll.find(url.Values{"channel_id": {c.params["channel_id"]}})
+		if len(found) == 0 {
+			writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+			return
+		}
+		writeData(c.w, http.StatusOK, found[0], nil)
+	})
+
+	s.handle(http.MethodGet, "/v3/channels/currency-assignments", func(c *call) {
+		writeData(c.w, http.StatusOK, renderByChannel(currencies.coll.find(url.Values{})), nil)
+	})
+	s.handle(http.MethodGet, "/v3/channels/{channel_id}/currency-assignments", func(c *call) { s.getByChannel(c, currencies) })
+	s.handle(http.MethodPost, "/v3/channels/{channel_id}/currency-assignments", func(c *call) { s.setCurrencies(c, currencies, true) })
+	s.handle(http.MethodPut, "/v3/channels/{channel_id}/currency-assignments", func(c *call) { s.setCurrencies(c, currencies, false) })
+	s.handle(http.MethodDelete, "/v3/channels/{channel_id}/currency-assignments", func(c *call) { s.deleteByChannel(c, currencies) })
+
+	s.handle(http.MethodGet, "/v3/channels/{channel_id}/channel-menus", func(c *call) {
+		if !s.channelExists(c.params["channel_id"]) {
+			writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+			return
+		}
+		found := menus.coll.find(url.Values{"channel_id": {c.params["channel_id"]}})
+		if len(found) == 0 {
+			writeData(c.w, http.StatusOK, object{"bigcommerce_protected_app_sections": []interface{}{}, "custom_app_sections": []interface{}{}}, nil)
+			return
+		}
+		ret := renderByChannel(found)[0]
+		delete(ret, "channel_id")
+		writeData(c.w, http.StatusOK, ret, nil)
+	})
+	s.handle(http.MethodPost, "/v3/channels/{channel_id}/channel-menus", func(c *call) { s.setMenus(c, menus) })
+	s.handle(http.MethodDelete, "/v3/channels/{channel_id}/channel-menus", func(c *call) { s.deleteByChannel(c, menus) })
+}
+
+// channelExists checks a channel, the default channel 1 always exists
+func (s *Server) channelExists(id string) bool {
+	if id == "1" {
+		return true
+	}
+	_, ok := s.resources["channels"].coll.get(id)
+	return ok
+}
+
+// setSiteURLs sets the primary and canonical URLs of a site to its URL
+func setSiteURLs(o object) {
+	urls := []interface{}{}
+	for _, t := range []string{"primary", "canonical"} {
+		urls = append(urls, object{"url": o["url"], "type": t, "created_at": o["created_at"], "updated_at": o["updated_at"]})
+	}
+	o["urls"] = urls
+}
+
+// renderByChannel drops the IDs of objects stored once per channel
+func renderByChannel(items []object) []object {
+	ret := []object{}
+	for _, o := range items {
+		r := copyObject(o)
+		delete(r, "id")
+		ret = append(ret, r)
+	}
+	return ret
+}
+
+// createSite handles POST /v3/sites, a channel has at most one site
+func (s *Server) createSite(c *call, sites *resource) {
+	var o object
+	if !c.decode(&o) || !sites.validate(c, o) {
+		return
+	}
+	channel := idString(o["channel_id"])
+	if !s.channelExists(channel) {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid",
+			map[string]string{"channel_id": "channel " + channel + " not found"})
+		return
+	}
+	if len(sites.coll.find(url.Values{"channel_id": {channel}})) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid",
+			map[string]string{"channel_id": "channel " + channel + " already has a site"})
+		return
+	}
+	writeData(c.w, http.StatusOK, s.insert(c, sites, object{"url": o["url"], "channel_id": o["channel_id"]}), nil)
+}
+
+// upsertRoutes handles PUT /v3/sites/{site_id}/routes, routes with an ID are updated and the others created
+func (s *Server) upsertRoutes(c *call, sites, routes *resource) {
+	if _, ok := sites.coll.get(c.params["site_id"]); !ok {
+		writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+		return
+	}
+	var in []object
+	if !c.decode(&in) {
+		return
+	}
+	errs := map[string]string{}
+	for i, o := range in {
+		if id := idString(o["id"]); id != "" && id != "0" {
+			if stored, ok := routes.coll.get(id); !ok || !routes.owned(c, stored) {
+				errs[strconv.Itoa(i)+".id"] = "route " + id + " not found"
+			}
+		} else if idString(o["type"]) == "" || idString(o["route"]) == "" {
+			errs[strconv.Itoa(i)+".route"] = "type and route are required fields"
+		}
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	data := []object{}
+	for _, o := range in {
+		id := idString(o["id"])
+		delete(o, "site_id")
+		if id == "" || id == "0" {
+			delete(o, "id")
+			data = append(data, routes.output(s.insert(c, routes, o), c.query))
+			continue
+		}
+		updated, _ := routes.coll.update(id, o)
+		data = append(data, routes.output(updated, c.query))
+	}
+	writeData(c.w, http.StatusOK, data, nil)
+}
+
+func (s *Server) getByChannel(c *call, res *resource) {
+	found := res.coll.find(url.Values{"channel_id": {c.params["channel_id"]}})
+	if len(found) == 0 {
+		writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+		return
+	}
+	writeData(c.w, http.StatusOK, renderByChannel(found)[0], nil)
+}
+
+func (s *Server) deleteByChannel(c *call, res *resource) {
+	for _, o := range res.coll.find(url.Values{"channel_id": {c.params["channel_id"]}}) {
+		res.coll.remove(idString(o["id"]))
+	}
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+// storeByChannel replaces the object of a channel
+func storeByChannel(res *resource, channel string, o object) object {
+	for _, old := range res.coll.find(url.Values{"channel_id": {channel}}) {
+		res.coll.remove(idString(old["id"]))
+	}
+	o["channel_id"] = float64(intValue(channel))
+	return res.coll.insert(o)
+}
+
+// setCurrencies handles POST and PUT /v3/channels/{channel_id}/currency-assignments,
+// POST fails if the channel has assignments and PUT if it has none
+func (s *Server) setCurrencies(c *call, currencies *resource, create bool) {
+	channel := c.params["channel_id"]
+	if !s.channelExists(channel) {
+		writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+		return
+	}
+	exists := len(currencies.coll.find(url.Values{"channel_id": {channel}})) > 0
+	if !create && !exists {
+		writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+		return
+	}
+	if create && exists {
+		writeError(c.w, http.StatusConflict, "Currency assignments already exist for channel "+channel, nil)
+		return
+	}
+	var in struct {
+		EnabledCurrencies []string `json:"enabled_currencies"`
+		DefaultCurrency   string   `json:"default_currency"`
+	}
+	if !c.decode(&in) {
+		return
+	}
+	if !contains(in.EnabledCurrencies, in.DefaultCurrency) {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid",
+			map[string]string{"default_currency": "default currency " + in.DefaultCurrency + " is not enabled"})
+		return
+	}
+	o := storeByChannel(currencies, channel, toObject(in))
+	writeData(c.w, http.StatusOK, renderByChannel([]object{o})[0], nil)
+}
+
+// setMenus handles POST /v3/channels/{channel_id}/channel-menus
+func (s *Server) setMenus(c *call, menus *resource) {
+	if !s.channelExists(c.params["channel_id"]) {
+		writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+		return
+	}
+	var in bigcommerce.ChannelMenus
+	if !c.decode(&in) {
+		return
+	}
+	o := storeByChannel(menus, c.params["channel_id"], toObject(in))
+	ret := renderByChannel([]object{o})[0]
+	delete(ret, "channel_id")
+	writeData(c.w, http.StatusOK, ret, nil)
+}
+
+// AddSite adds a site to the store
+func (s *Server) AddSite(site bigcommerce.Site) bigcommerce.Site {
+	var ret bigcommerce.Site
+	decodeObject(s.seed("sites", site), &ret)
+	return ret
+}
@@ -0,0 +1,396 @@
+package bctest
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// object is a resource as stored by the server
+type object map[string]interface{}
+
+// query parameters that are not filters
+var reservedParams = map[string]bool{
+	"page":           true,
+	"limit":          true,
+	"include":        true,
+	"include_fields": true,
+	"exclude_fields": true,
+	"sort":           true,
+	"direction":      true,
+}
+
+// collection is an in-memory list of resources of one type
+type collection struct {
+	idKey      string // "id" for numeric IDs, "uuid" for UUIDs
+	uuids      bool
+	dateFormat string // format of date_created/date_modified, empty if the resource has none
+	items      []object
+	nextID     int64
+}
+
+func newCollection() *collection {
+	return &collection{idKey: "id", nextID: 1}
+}
+
+func (c *collection) withUUIDs(idKey string) *collection {
+	c.idKey = idKey
+	c.uuids = true
+	return c
+}
+
+func (c *collection) withDates(format string) *collection {
+	c.dateFormat = format
+	return c
+}
+
+// insert stores a new object, assigning its ID and dates
+func (c *collection) insert(o object) object {
+	if c.uuids {
+		o[c.idKey] = newUUID()
+	} else {
+		o[c.idKey] = float64(c.nextID)
+		c.nextID++
+	}
+	if c.dateFormat != "" {
+		now := time.Now().UTC().Format(c.dateFormat)
+		o["date_created"] = now
+		o["date_modified"] = now
+	}
+	c.items = append(c.items, o)
+	return o
+}
+
+// get returns the object with the given ID
+func (c *collection) get(id string) (object, bool) {
+	i := c.index(id)
+	if i < 0 {
+		return nil, false
+	}
+	return c.items[i], true
+}
+
+func (c *collection) index(id string) int {
+	for i, o := range c.items {
+		if idString(o[c.idKey]) == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// update merges the patch into the stored object, the ID can't be changed
+func (c *collection) update(id string, patch object) (object, bool) {
+	o, ok := c.get(id)
+	if !ok {
+		return nil, false
+	}
+	for k, v := range patch {
+		if k == c.idKey || k == "date_created" {
+			continue
+		}
+		o[k] = v
+	}
+	if c.dateFormat != "" {
+		o["date_modified"] = time.Now().UTC().Format(c.dateFormat)
+	}
+	return o, true
+}
+
+// remove deletes the object with the given ID
+func (c *collection) remove(id string) bool {
+	i := c.index(id)
+	if i < 0 {
+		return false
+	}
+	c.items = append(c.items[:i], c.items[i+1:]...)
+	return true
+}
+
+// find returns the objects matching the filters, sorted as requested
+func (c *collection) find(q url.Values) []object {
+	ret := []object{}
+	for _, o := range c.items {
+		if matches(o, q) {
+			ret = append(ret, o)
+		}
+	}
+	if !c.uuids || q.Get("sort") != "" {
+		sortObjects(ret, q, c.idKey)
+	}
+	return ret
+}
+
+// page is a page of results and its v3 pagination metadata
+type page struct {
+	items []object
+	meta  object
+}
+
+// paginate cuts a page from the results using the page and limit parameters
+func paginate(items []object, q url.Values) page {
+	limit, _ := strconv.Atoi(q.Get("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+	if limit > 250 {
+		limit = 250
+	}
+	current, _ := strconv.Atoi(q.Get("page"))
+	if current <= 0 {
+		current = 1
+	}
+	total := len(items)
+	totalPages := (total + limit - 1) / limit
+	if totalPages == 0 {
+		totalPages = 1
+	}
+	from := (current - 1) * limit
+	if from > total {
+		from = total
+	}
+	to := from + limit
+	if to > total {
+		to = total
+	}
+	links := object{"current": fmt.Sprintf("?page=%d&limit=%d", current, limit)}
+	if current < totalPages {
+		links["next"] = fmt.Sprintf("?page=%d&limit=%d", current+1, limit)
+	}
+	if current > 1 {
+		links["previous"] = fmt.Sprintf("?page=%d&limit=%d", current-1, limit)
+	}
+	return page{
+		items: items[from:to],
+		meta: object{"pagination": object{
+			"total":        total,
+			"count":        to - from,
+			"per_page":     limit,
+			"current_page": current,
+			"total_pages":  totalPages,
+			"links":        links,
+		}},
+	}
+}
+
+// matches checks an object against query filters:
+// field=value, field:in=a,b, field:not_in=a,b, field:min=x, field:max=x, field:like=x,
+// and the v2 style min_field=x and max_field=x
+func matches(o object, q url.Values) bool {
+	for key, values := range q {
+		if reservedParams[key] || len(values) == 0 {
+			continue
+		}
+		value := values[0]
+		field, op := key, ""
+		if i := strings.Index(key, ":"); i > 0 {
+			field, op = key[:i], key[i+1:]
+		} else if _, ok := o[key]; !ok {
+			if strings.HasPrefix(key, "min_") {
+				field, op = key[4:], "min"
+			} else if strings.HasPrefix(key, "max_") {
+				field, op = key[4:], "max"
+			}
+		}
+		actual := idString(o[field])
+		switch op {
+		case "":
+			if actual != value {
+				return false
+			}
+		case "in":
+			if !contains(strings.Split(value, ","), actual) {
+				return false
+			}
+		case "not_in":
+			if contains(strings.Split(value, ","), actual) {
+				return false
+			}
+		case "like":
+			if !strings.Contains(strings.ToLower(actual), strings.ToLower(value)) {
+				return false
+			}
+		case "min":
+			if compare(actual, value) < 0 {
+				return false
+			}
+		case "max":
+			if compare(actual, value) > 0 {
+				return false
+			}
+		case "greater":
+			if compare(actual, value) <= 0 {
+				return false
+			}
+		case "less":
+			if compare(actual, value) >= 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, l := range list {
+		if strings.TrimSpace(l) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// compare compares two values as numbers, dates or strings, whichever fits both
+func compare(a, b string) int {
+	fa, errA := strconv.ParseFloat(a, 64)
+	fb, errB := strconv.ParseFloat(b, 64)
+	if errA == nil && errB == nil {
+		switch {
+		case fa < fb:
+			return -1
+		case fa > fb:
+			return 1
+		}
+		return 0
+	}
+	ta, okA := parseTime(a)
+	tb, okB := parseTime(b)
+	if okA && okB {
+		switch {
+		case ta.Before(tb):
+			return -1
+		case ta.After(tb):
+			return 1
+		}
+		return 0
+	}
+	return strings.Compare(a, b)
+}
+
+func parseTime(s string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339, time.RFC1123Z, time.RFC1123, "2006-01-02"} {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// sortObjects sorts by the sort parameter (field, field:asc or field:desc) or by ID
+func sortObjects(items []object, q url.Values, idKey string) {
+	field := q.Get("sort")
+	desc := strings.EqualFold(q.Get("direction"), "desc")
+	if i := strings.Index(field, ":"); i > 0 {
+		desc = strings.EqualFold(field[i+1:], "desc")
+		field = field[:i]
+	}
+	if field == "" {
+		field = idKey
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		c := compare(idString(items[i][field]), idString(items[j][field]))
+		if desc {
+			return c > 0
+		}
+		return c < 0
+	})
+}
+
+// idString formats a JSON value for comparisons
+func idString(v interface{}) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(val)
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+// project applies include_fields and exclude_fields to an object
+func project(o object, q url.Values, idKey string) object {
+	include := q.Get("include_fields")
+	exclude := q.Get("exclude_fields")
+	if include == "" && exclude == "" {
+		return o
+	}
+	ret := object{}
+	if include != "" {
+		ret[idKey] = o[idKey]
+		for _, f := range strings.Split(include, ",") {
+			if v, ok := o[f]; ok {
+				ret[f] = v
+			}
+		}
+	} else {
+		for k, v := range o {
+			ret[k] = v
+		}
+	}
+	for _, f := range strings.Split(exclude, ",") {
+		if f != idKey {
+			delete(ret, f)
+		}
+	}
+	return ret
+}
+
+// toObject converts any JSON serializable value to an object
+func toObject(v interface{}) object {
+	b, _ := json.Marshal(v)
+	o := object{}
+	json.Unmarshal(b, &o)
+	return o
+}
+
+// copyObject returns a shallow copy
+func copyObject(o object) object {
+	ret := object{}
+	for k, v := range o {
+		ret[k] = v
+	}
+	return ret
+}
+
+// intValue returns a numeric field as int64
+func intValue(v interface{}) int64 {
+	switch val := v.(type) {
+	case float64:
+		return int64(val)
+	case string:
+		i, _ := strconv.ParseInt(val, 10, 64)
+		return i
+	}
+	return 0
+}
+
+// floatValue returns a numeric or string field as float64
+func floatValue(v interface{}) float64 {
+	switch val := v.(type) {
+	case float64:
+		return val
+	case string:
+		f, _ := strconv.ParseFloat(val, 64)
+		return f
+	}
+	return 0
+}
+
+func newUUID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
@@ -0,0 +1,178 @@
+package bctest
+
+import (
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+func (s *Server) contentRoutes() {
+	hooks := &resource{
+		coll:     newCollection(),
+		required: []string{"scope", "destination"},
+		defaults: object{"is_active": true, "headers": nil},
+	}
+	hooks.created = func(o object) {
+		now := float64(time.Now().Unix())
+		o["created_at"] = now
+		o["updated_at"] = now
+		o["store_hash"] = s.StoreHash
+	}
+	hooks.updated = func(o object) {
+		o["updated_at"] = float64(time.Now().Unix())
+	}
+	themes := &resource{coll: newCollection().withUUIDs("uuid")}
+	themes.removed = func(o object) {
+		configs := s.resources["theme_configurations"].coll
+		for _, c := range configs.find(url.Values{"theme_uuid": {idString(o["uuid"])}}) {
+			configs.remove(idString(c["uuid"]))
+		}
+	}
+	configs := &resource{
+		coll:        newCollection().withUUIDs("uuid"),
+		parentParam: "theme_uuid",
+		parentKey:   "theme_uuid",
+	}
+	configs.created = func(o object) {
+		o["store_hash"] = s.StoreHash
+		o["created_at"] = time.Now().UTC().Format(time.RFC3339)
+	}
+
+	s.resources["coupons"] = &resource{
+		coll:     newCollection().withDates(time.RFC1123Z),
+		required: []string{"name", "code", "type", "amount"},
+		defaults: object{"enabled": true, "num_uses": float64(0)},
+	}
+	s.resources["webhooks"] = hooks
+	s.resources["scripts"] = &resource{
+		coll:     newCollection().withUUIDs("uuid").withDates(time.RFC3339),
+		required: []string{"name"},
+		defaults: object{"enabled": true, "channel_id": float64(1)},
+	}
+	s.resources["widget_templates"] = &resource{
+		coll:     newCollection().withUUIDs("uuid").withDates(time.RFC3339),
+		required: []string{"name", "template"},
+		defaults: object{"channel_id": float64(1), "kind": "custom", "template_engine": "handlebars_v3"},
+	}
+	s.resources["themes"] = themes
+	s.resources["theme_configurations"] = configs
+	// channel 1 is the default storefront, see channelExists
+	channels := newCollection().withDates(time.RFC3339)
+	channels.nextID = 2
+	s.resources["channels"] = &resource{
+		coll:     channels,
+		required: []string{"name", "type", "platform"},
+		defaults: object{"status": "active", "is_enabled": true, "is_visible": true},
+	}
+	s.resources["posts"] = &resource{coll: newCollection(), v2: true, required: []string{"title", "body"}}
+	s.resources["currencies"] = &resource{coll: newCollection(), v2: true, required: []string{"currency_code"}}
+	s.resources["tax_zones"] = &resource{coll: newCollection(), batchCreate: true, batchUpdate: true, required: []string{"name"}}
+	s.resources["tax_rates"] = &resource{coll: newCollection(), batchCreate: true, batchUpdate: true, required: []string{"tax_zone_id"}}
+
+	s.rest("/v3/coupons", s.resources["coupons"])
+	s.rest("/v3/hooks", hooks)
+	s.rest("/v3/content/scripts", s.resources["scripts"])
+	s.rest("/v3/content/widget-templates", s.resources["widget_templates"])
+	s.rest("/v3/themes", themes)
+	s.rest("/v3/themes/{theme_uuid}/configurations", configs)
+	s.rest("/v3/channels", s.resources["channels"])
+	s.rest("/v2/blog/posts", s.resources["posts"])
+	s.rest("/v2/currencies", s.resources["currencies"])
+	s.rest("/v3/tax/zones", s.resources["tax_zones"])
+	s.rest("/v3/tax/rates", s.resources["tax_rates"])
+	s.handle(http.MethodGet, "/v2/store", func(c *call) { writeJSON(c.w, http.StatusOK, s.storeInfo) })
+}
+
+// AddCoupon adds a coupon to the store
+func (s *Server) AddCoupon(c bigcommerce.Coupon) bigcommerce.Coupon {
+	var ret bigcommerce.Coupon
+	decodeObject(s.seed("coupons", c), &ret)
+	return ret
+}
+
+// AddWebhook adds a webhook to the store
+func (s *Server) AddWebhook(w bigcommerce.Webhook) bigcommerce.Webhook {
+	var ret bigcommerce.Webhook
+	decodeObject(s.seed("webhooks", w), &ret)
+	return ret
+}
+
+// AddScript adds a script to the store
+func (s *Server) AddScript(sc bigcommerce.Script) bigcommerce.Script {
+	var ret bigcommerce.Script
+	decodeObject(s.seed("scripts", sc), &ret)
+	return ret
+}
+
+// AddWidgetTemplate adds a widget template to the store
+func (s *Server) AddWidgetTemplate(t bigcommerce.PageBuilderTemplate) bigcommerce.PageBuilderTemplate {
+	var ret bigcommerce.PageBuilderTemplate
+	decodeObject(s.seed("widget_templates", t), &ret)
+	return ret
+}
+
+// AddTheme adds a theme to the store
+func (s *Server) AddTheme(t bigcommerce.Theme) bigcommerce.Theme {
+	var ret bigcommerce.Theme
+	decodeObject(s.seed("themes", t), &ret)
+	return ret
+}
+
+// AddThemeConfig adds a configuration to a theme
+func (s *Server) AddThemeConfig(themeUUID string, cfg bigcommerce.ThemeConfig) bigcommerce.ThemeConfig {
+	cfg.ThemeUUID = themeUUID
+	var ret bigcommerce.ThemeConfig
+	decodeObject(s.seed("theme_configurations", cfg), &ret)
+	return ret
+}
+
+// AddChannel adds a channel to the store
+func (s *Server) AddChannel(ch bigcommerce.Channel) bigcommerce.Channel {
+	var ret bigcommerce.Channel
+	decodeObject(s.seed("channels", ch), &ret)
+	return ret
+}
+
+// AddPost adds a blog post to the store
+func (s *Server) AddPost(p bigcommerce.Post) bigcommerce.Post {
+	var ret bigcommerce.Post
+	decodeObject(s.seed("posts", p), &ret)
+	return ret
+}
+
+// AddCurrency adds a currency to the store
+func (s *Server) AddCurrency(c bigcommerce.Currency) bigcommerce.Currency {
+	var ret bigcommerce.Currency
+	decodeObject(s.seed("currencies", c), &ret)
+	return ret
+}
+
+// AddTaxZone adds a tax zone to the store
+func (s *Server) AddTaxZone(z bigcommerce.TaxZone) bigcommerce.TaxZone {
+	var ret bigcommerce.TaxZone
+	decodeObject(s.seed("tax_zones", z), &ret)
+	return ret
+}
+
+// AddTaxRate adds a tax rate to the store
+func (s *Server) AddTaxRate(r bigcommerce.TaxClassRate) bigcommerce.TaxClassRate {
+	var ret bigcommerce.TaxClassRate
+	decodeObject(s.seed("tax_rates", r), &ret)
+	return ret
+}
+
+// Webhooks returns the webhooks currently in the store
+func (s *Server) Webhooks() []bigcommerce.Webhook {
+	var ret []bigcommerce.Webhook
+	s.snapshot("webhooks", &ret)
+	return ret
+}
+
+// Coupons returns the coupons currently in the store
+func (s *Server) Coupons() []bigcommerce.Coupon {
+	var ret []bigcommerce.Coupon
+	s.snapshot("coupons", &ret)
+	return ret
+}
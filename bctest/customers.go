@@ -0,0 +1,209 @@
+package bctest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+func (s *Server) customerRoutes() {
+	addresses := &resource{
+		coll:        newCollection(),
+		batchCreate: true,
+		batchUpdate: true,
+		required:    []string{"customer_id", "first_name", "last_name", "address1", "city", "country_code"},
+	}
+	customers := &resource{
+		coll:        newCollection().withDates(time.RFC3339),
+		batchCreate: true,
+		batchUpdate: true,
+		required:    []string{"email", "first_name", "last_name"},
+		defaults:    object{"customer_group_id": float64(0), "accepts_marketing": false},
+	}
+	customers.created = func(o object) {
+		s.customerChanged(o)
+		nested, _ := o["addresses"].([]interface{})
+		delete(o, "addresses")
+		for _, n := range nested {
+			a, ok := n.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			a["customer_id"] = o["id"]
+			addresses.coll.insert(a)
+		}
+	}
+	customers.updated = func(o object) {
+		s.customerChanged(o)
+		delete(o, "addresses")
+	}
+	customers.removed = func(o object) {
+		id := idString(o["id"])
+		for _, a := range addresses.coll.find(url.Values{"customer_id": {id}}) {
+			addresses.coll.remove(idString(a["id"]))
+		}
+		delete(s.passwords, intValue(o["id"]))
+		delete(s.formFields, intValue(o["id"]))
+	}
+	customers.render = func(o object, q url.Values) object {
+		c := copyObject(o)
+		include := q.Get("include")
+		if strings.Contains(include, "addresses") {
+			c["addresses"] = addresses.coll.find(url.Values{"customer_id": {idString(o["id"])}})
+		}
+		if strings.Contains(include, "formfields") {
+			c["form_fields"] = s.customerFormFields(intValue(o["id"]))
+		}
+		return c
+	}
+
+	s.resources["customers"] = customers
+	s.resources["addresses"] = addresses
+	s.resources["customer_groups"] = &resource{coll: newCollection(), v2: true, required: []string{"name"}}
+
+	s.handle(http.MethodPost, "/v3/customers/validate-credentials", s.validateCredentials)
+	s.handle(http.MethodGet, "/v3/customers/form-field-values", s.getFormFields)
+	s.handle(http.MethodPut, "/v3/customers/form-field-values", s.setFormFields)
+	s.rest("/v3/customers/addresses", addresses)
+	s.rest("/v3/customers", customers)
+	s.rest("/v2/customer_groups", s.resources["customer_groups"])
+}
+
+// customerChanged stores the password sent in the authentication object, it is never returned
+func (s *Server) customerChanged(o object) {
+	auth, ok := o["authentication"].(map[string]interface{})
+	delete(o, "authentication")
+	if !ok {
+		return
+	}
+	if pw, ok := auth["new_password"].(string); ok && pw != "" {
+		s.passwords[intValue(o["id"])] = pw
+	}
+}
+
+func (s *Server) validateCredentials(c *call) {
+	var in struct {
+		Email     string `json:"email"`
+		Password  string `json:"password"`
+		ChannelID int    `json:"channel_id"`
+	}
+	if !c.decode(&in) {
+		return
+	}
+	if in.Email == "" || in.Password == "" {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid",
+			map[string]string{"email": "email and password are required"})
+		return
+	}
+	for _, o := range s.resources["customers"].coll.find(url.Values{"email": {in.Email}}) {
+		id := intValue(o["id"])
+		if !customerInChannel(o, in.ChannelID) {
+			continue
+		}
+		if pw, ok := s.passwords[id]; ok && pw == in.Password {
+			writeJSON(c.w, http.StatusOK, object{"is_valid": true, "customer_id": id})
+			return
+		}
+	}
+	writeJSON(c.w, http.StatusOK, object{"is_valid": false, "customer_id": nil})
+}
+
+// customerInChannel checks that a customer can sign in to a channel, customers without
+// channel_ids can sign in to all channels
+func customerInChannel(o object, channelID int) bool {
+	list, _ := o["channel_ids"].([]interface{})
+	if len(list) == 0 {
+		return true
+	}
+	for _, ch := range list {
+		if intValue(ch) == int64(channelID) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) customerFormFields(customerID int64) []object {
+	ret := []object{}
+	for name, value := range s.formFields[customerID] {
+		ret = append(ret, object{"customer_id": customerID, "name": name, "value": value})
+	}
+	sortObjects(ret, url.Values{"sort": {"name"}}, "name")
+	return ret
+}
+
+func (s *Server) getFormFields(c *call) {
+	ret := []object{}
+	ids := c.query.Get("customer_id")
+	if ids == "" {
+		ids = c.query.Get("customer_id:in")
+	}
+	for _, id := range strings.Split(ids, ",") {
+		cid, _ := strconv.ParseInt(strings.TrimSpace(id), 10, 64)
+		ret = append(ret, s.customerFormFields(cid)...)
+	}
+	p := paginate(ret, c.query)
+	writeData(c.w, http.StatusOK, p.items, p.meta)
+}
+
+func (s *Server) setFormFields(c *call) {
+	var in []object
+	if !c.decode(&in) {
+		return
+	}
+	for _, f := range in {
+		cid := intValue(f["customer_id"])
+		if _, ok := s.resources["customers"].coll.get(strconv.FormatInt(cid, 10)); !ok {
+			writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid",
+				map[string]string{"customer_id": "customer " + strconv.FormatInt(cid, 10) + " not found"})
+			return
+		}
+	}
+	for _, f := range in {
+		cid := intValue(f["customer_id"])
+		if s.formFields[cid] == nil {
+			s.formFields[cid] = map[string]interface{}{}
+		}
+		s.formFields[cid][idString(f["name"])] = f["value"]
+	}
+	writeData(c.w, http.StatusOK, in, nil)
+}
+
+// AddCustomer adds a customer to the store, password is used by validate-credentials
+func (s *Server) AddCustomer(cust bigcommerce.Customer, password string) bigcommerce.Customer {
+	o := s.seed("customers", cust)
+	s.mu.Lock()
+	if password != "" {
+		s.passwords[intValue(o["id"])] = password
+	}
+	s.mu.Unlock()
+	var ret bigcommerce.Customer
+	decodeObject(o, &ret)
+	return ret
+}
+
+// AddAddress adds an address to a customer
+func (s *Server) AddAddress(customerID int64, a bigcommerce.Address) bigcommerce.Address {
+	a.CustomerID = customerID
+	var ret bigcommerce.Address
+	decodeObject(s.seed("addresses", a), &ret)
+	return ret
+}
+
+// AddCustomerGroup adds a customer group to the store
+func (s *Server) AddCustomerGroup(g bigcommerce.CustomerGroup) bigcommerce.CustomerGroup {
+	var ret bigcommerce.CustomerGroup
+	decodeObject(s.seed("customer_groups", g), &ret)
+	return ret
+}
+
+// Customers returns the customers currently in the store
+func (s *Server) Customers() []bigcommerce.Customer {
+	var ret []bigcommerce.Customer
+	s.snapshot("customers", &ret)
+	return ret
+}
@@ -0,0 +1,413 @@
+package bctest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+// inventoryRoutes registers the inventory API. The stock of a variant in a location is kept in
+// the inventory collection, created on first use from the inventory_level of the variant for the
+// default location. The inventory_level of variants is the sum of their stock in all locations.
+func (s *Server) inventoryRoutes() {
+	locations := &resource{coll: newCollection(), required: []string{"code", "label"}}
+	locations.coll.insertKeepingID(object{
+		"id":                         float64(bigcommerce.DefaultLocationID),
+		"code":                       "BC-LOCATION-1",
+		"label":                      "Default location",
+		"type_id":                    "PHYSICAL",
+		"enabled":                    true,
+		"storefront_visibility":      true,
+		"managed_by_external_source": false,
+	})
+	s.resources["locations"] = locations
+	s.resources["inventory"] = &resource{coll: newCollection()}
+
+	s.handle(http.MethodGet, "/v3/inventory/locations", s.listLocations)
+	s.handle(http.MethodPost, "/v3/inventory/locations", s.createLocations)
+	s.handle(http.MethodPut, "/v3/inventory/locations", s.updateLocations)
+	s.handle(http.MethodDelete, "/v3/inventory/locations", s.deleteLocations)
+	s.handle(http.MethodPut, "/v3/inventory/adjustments/absolute", func(c *call) { s.adjustInventory(c, true) })
+	s.handle(http.MethodPost, "/v3/inventory/adjustments/relative", func(c *call) { s.adjustInventory(c, false) })
+	s.handle(http.MethodGet, "/v3/inventory/items", s.listInventoryItems)
+	s.handle(http.MethodGet, "/v3/inventory/locations/{location_id}/items", s.listLocationItems)
+	s.handle(http.MethodPut, "/v3/inventory/locations/{location_id}/items", s.updateLocationItems)
+}
+
+// writeTransaction writes the response of the inventory writes, which only return a transaction ID
+func writeTransaction(w http.ResponseWriter) {
+	writeJSON(w, http.StatusOK, object{"transaction_id": newUUID()})
+}
+
+// listLocations handles GET /v3/inventory/locations, filters are named after the location fields
+func (s *Server) listLocations(c *call) {
+	q := url.Values{}
+	for k, v := range c.query {
+		switch k {
+		case "location_id:in":
+			q["id:in"] = v
+		case "location_code:in":
+			q["code:in"] = v
+		case "is_active":
+			q["enabled"] = v
+		default:
+			q[k] = v
+		}
+	}
+	p := paginate(s.resources["locations"].coll.find(q), q)
+	writeData(c.w, http.StatusOK, p.items, p.meta)
+}
+
+// locationCodeUsed checks whether a location other than id has the code
+func (s *Server) locationCodeUsed(code, id string) bool {
+	for _, l := range s.resources["locations"].coll.find(url.Values{"code": {code}}) {
+		if idString(l["id"]) != id {
+			return true
+		}
+	}
+	return false
+}
+
+// createLocations handles POST /v3/inventory/locations, codes are unique
+func (s *Server) createLocations(c *call) {
+	var in []object
+	if !c.decode(&in) {
+		return
+	}
+	errs := map[string]string{}
+	codes := []string{}
+	for i, l := range in {
+		code := idString(l["code"])
+		switch {
+		case code == "":
+			errs[strconv.Itoa(i)+".code"] = "code is a required field"
+		case idString(l["label"]) == "":
+			errs[strconv.Itoa(i)+".label"] = "label is a required field"
+		case s.locationCodeUsed(code, "") || contains(codes, code):
+			errs[strconv.Itoa(i)+".code"] = "location code " + code + " is already used"
+		}
+		codes = append(codes, code)
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	for _, l := range in {
+		delete(l, "id")
+		if idString(l["type_id"]) == "" {
+			l["type_id"] = "PHYSICAL"
+		}
+		s.resources["locations"].coll.insert(l)
+	}
+	writeTransaction(c.w)
+}
+
+// updateLocations handles PUT /v3/inventory/locations, locations are matched by id
+func (s *Server) updateLocations(c *call) {
+	var in []object
+	if !c.decode(&in) {
+		return
+	}
+	locations := s.resources["locations"].coll
+	errs := map[string]string{}
+	for i, l := range in {
+		id := idString(l["id"])
+		if _, ok := locations.get(id); !ok {
+			errs[strconv.Itoa(i)+".id"] = "location " + id + " not found"
+		} else if code := idString(l["code"]); code != "" && s.locationCodeUsed(code, id) {
+			errs[strconv.Itoa(i)+".code"] = "location code " + code + " is already used"
+		}
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	for _, l := range in {
+		locations.update(idString(l["id"]), l)
+	}
+	writeTransaction(c.w)
+}
+
+// deleteLocations handles DELETE /v3/inventory/locations, the default location can't be deleted
+func (s *Server) deleteLocations(c *call) {
+	ids := strings.Split(c.query.Get("location_id:in"), ",")
+	if ids[0] == "" {
+		writeError(c.w, http.StatusUnprocessableEntity, "At least one filter is required", nil)
+		return
+	}
+	if contains(ids, strconv.Itoa(bigcommerce.DefaultLocationID)) {
+		writeError(c.w, http.StatusUnprocessableEntity, "The default location can't be deleted", nil)
+		return
+	}
+	inventory := s.resources["inventory"].coll
+	for _, id := range ids {
+		id = strings.TrimSpace(id)
+		if !s.resources["locations"].coll.remove(id) {
+			continue
+		}
+		for _, row := range inventory.find(url.Values{"location_id": {id}}) {
+			inventory.remove(idString(row["id"]))
+			if v, ok := s.resources["variants"].coll.get(idString(row["variant_id"])); ok {
+				s.syncInventoryLevel(v)
+			}
+		}
+	}
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+// inventoryVariant returns the variant identified by variant_id, sku or product_id, the base
+// variant for product_id, and the error message if there is none
+func (s *Server) inventoryVariant(id object) (object, string, string) {
+	variants := s.resources["variants"].coll
+	switch {
+	case intValue(id["variant_id"]) != 0:
+		if v, ok := variants.get(idString(id["variant_id"])); ok {
+			return v, "", ""
+		}
+		return nil, "variant_id", "variant " + idString(id["variant_id"]) + " not found"
+	case idString(id["sku"]) != "":
+		if found := variants.find(url.Values{"sku": {idString(id["sku"])}}); len(found) > 0 {
+			return found[0], "", ""
+		}
+		return nil, "sku", "item with sku " + idString(id["sku"]) + " not found"
+	case intValue(id["product_id"]) != 0:
+		if p, ok := s.resources["products"].coll.get(idString(id["product_id"])); ok {
+			if v, ok := variants.get(idString(p["base_variant_id"])); ok {
+				return v, "", ""
+			}
+		}
+		return nil, "product_id", "product " + idString(id["product_id"]) + " not found"
+	}
+	return nil, "sku", "sku, variant_id or product_id is required"
+}
+
+// stock returns the inventory row of a variant in a location, creating it if needed
+func (s *Server) stock(locationID string, v object) object {
+	inventory := s.resources["inventory"].coll
+	found := inventory.find(url.Values{"location_id": {locationID}, "variant_id": {idString(v["id"])}})
+	if len(found) > 0 {
+		return found[0]
+	}
+	onhand := float64(0)
+	if locationID == strconv.Itoa(bigcommerce.DefaultLocationID) {
+		onhand = floatValue(v["inventory_level"])
+		// base variants created with their product have the inventory of the product
+		if _, ok := v["inventory_level"]; !ok {
+			p, _ := s.resources["products"].coll.get(idString(v["product_id"]))
+			onhand = floatValue(p["inventory_level"])
+		}
+	}
+	return inventory.insert(object{
+		"location_id":            toNumber(locationID),
+		"variant_id":             v["id"],
+		"total_inventory_onhand": onhand,
+		"safety_stock":           float64(0),
+		"is_in_stock":            true,
+		"warning_level":          floatValue(v["inventory_warning_level"]),
+		"bin_picking_number":     "",
+	})
+}
+
+func toNumber(id string) float64 {
+	f, _ := strconv.ParseFloat(id, 64)
+	return f
+}
+
+// syncInventoryLevel sets the inventory_level of a variant to its stock in all locations, and the
+// inventory_level of its product to the sum of its variants
+func (s *Server) syncInventoryLevel(v object) {
+	s.stock(strconv.Itoa(bigcommerce.DefaultLocationID), v)
+	total := float64(0)
+	for _, row := range s.resources["inventory"].coll.find(url.Values{"variant_id": {idString(v["id"])}}) {
+		total += floatValue(row["total_inventory_onhand"])
+	}
+	variants := s.resources["variants"].coll
+	variants.update(idString(v["id"]), object{"inventory_level": total})
+	productTotal := float64(0)
+	for _, pv := range variants.find(url.Values{"product_id": {idString(v["product_id"])}}) {
+		productTotal += floatValue(pv["inventory_level"])
+	}
+	s.resources["products"].coll.update(idString(v["product_id"]), object{"inventory_level": productTotal})
+}
+
+// adjustInventory handles the absolute and relative adjustments, nothing is changed when an item is invalid
+func (s *Server) adjustInventory(c *call, absolute bool) {
+	var in struct {
+		Reason string   `json:"reason"`
+		Items  []object `json:"items"`
+	}
+	if !c.decode(&in) {
+		return
+	}
+	if len(in.Items) > 2000 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid",
+			map[string]string{"items": "at most 2000 items can be adjusted at once"})
+		return
+	}
+	errs := map[string]string{}
+	resolved := make([]object, len(in.Items))
+	for i, item := range in.Items {
+		key := "items." + strconv.Itoa(i)
+		if _, ok := s.resources["locations"].coll.get(idString(item["location_id"])); !ok {
+			errs[key+".location_id"] = "location " + idString(item["location_id"]) + " not found"
+			continue
+		}
+		v, field, msg := s.inventoryVariant(item)
+		if v == nil {
+			errs[key+"."+field] = msg
+			continue
+		}
+		if absolute && floatValue(item["quantity"]) < 0 {
+			errs[key+".quantity"] = "quantity can't be negative"
+			continue
+		}
+		resolved[i] = v
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	inventory := s.resources["inventory"].coll
+	for i, item := range in.Items {
+		row := s.stock(idString(item["location_id"]), resolved[i])
+		quantity := floatValue(item["quantity"])
+		if !absolute {
+			quantity += floatValue(row["total_inventory_onhand"])
+		}
+		inventory.update(idString(row["id"]), object{"total_inventory_onhand": quantity})
+		s.syncInventoryLevel(resolved[i])
+	}
+	writeTransaction(c.w)
+}
+
+// inventoryIdentity returns the identity of a variant as returned by the inventory API
+func inventoryIdentity(v object) object {
+	return object{"sku": v["sku"], "variant_id": v["id"], "product_id": v["product_id"]}
+}
+
+// inventoryVariants returns the variants matching the sku:in, variant_id:in and product_id:in filters
+func (s *Server) inventoryVariants(query url.Values) []object {
+	q := url.Values{}
+	for _, f := range []string{"sku:in", "product_id:in"} {
+		if v := query.Get(f); v != "" {
+			q.Set(f, v)
+		}
+	}
+	if v := query.Get("variant_id:in"); v != "" {
+		q.Set("id:in", v)
+	}
+	return s.resources["variants"].coll.find(q)
+}
+
+// locationInventory returns the stock and settings of a variant in a location
+func (s *Server) locationInventory(locationID string, v object) object {
+	row := s.stock(locationID, v)
+	onhand := floatValue(row["total_inventory_onhand"])
+	available := onhand - floatValue(row["safety_stock"])
+	if available < 0 {
+		available = 0
+	}
+	return object{
+		"available_to_sell":      available,
+		"total_inventory_onhand": onhand,
+		"settings": object{
+			"safety_stock":       row["safety_stock"],
+			"is_in_stock":        row["is_in_stock"],
+			"warning_level":      row["warning_level"],
+			"bin_picking_number": row["bin_picking_number"],
+		},
+	}
+}
+
+// listInventoryItems handles GET /v3/inventory/items, with the stock in every location
+func (s *Server) listInventoryItems(c *call) {
+	lq := url.Values{}
+	if ids := c.query.Get("location_id:in"); ids != "" {
+		lq.Set("id:in", ids)
+	}
+	locations := s.resources["locations"].coll.find(lq)
+	p := paginate(s.inventoryVariants(c.query), c.query)
+	data := []object{}
+	for _, v := range p.items {
+		ls := []object{}
+		for _, l := range locations {
+			li := s.locationInventory(idString(l["id"]), v)
+			li["location_id"] = l["id"]
+			li["location_code"] = l["code"]
+			li["location_name"] = l["label"]
+			ls = append(ls, li)
+		}
+		data = append(data, object{"identity": inventoryIdentity(v), "locations": ls})
+	}
+	writeData(c.w, http.StatusOK, data, p.meta)
+}
+
+// listLocationItems handles GET /v3/inventory/locations/{location_id}/items
+func (s *Server) listLocationItems(c *call) {
+	locationID := c.params["location_id"]
+	if _, ok := s.resources["locations"].coll.get(locationID); !ok {
+		writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+		return
+	}
+	p := paginate(s.inventoryVariants(c.query), c.query)
+	data := []object{}
+	for _, v := range p.items {
+		li := s.locationInventory(locationID, v)
+		li["identity"] = inventoryIdentity(v)
+		data = append(data, li)
+	}
+	writeData(c.w, http.StatusOK, data, p.meta)
+}
+
+// updateLocationItems handles PUT /v3/inventory/locations/{location_id}/items, which sets the
+// settings of items in a location. Nothing is changed when an item is invalid.
+func (s *Server) updateLocationItems(c *call) {
+	locationID := c.params["location_id"]
+	if _, ok := s.resources["locations"].coll.get(locationID); !ok {
+		writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+		return
+	}
+	var in struct {
+		Settings []object `json:"settings"`
+	}
+	if !c.decode(&in) {
+		return
+	}
+	errs := map[string]string{}
+	resolved := make([]object, len(in.Settings))
+	for i, st := range in.Settings {
+		id, _ := st["identity"].(map[string]interface{})
+		v, field, msg := s.inventoryVariant(object(id))
+		if v == nil {
+			errs["settings."+strconv.Itoa(i)+".identity."+field] = msg
+			continue
+		}
+		resolved[i] = v
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	inventory := s.resources["inventory"].coll
+	for i, st := range in.Settings {
+		row := s.stock(locationID, resolved[i])
+		patch := object{}
+		for _, f := range []string{"safety_stock", "is_in_stock", "warning_level", "bin_picking_number"} {
+			if v, ok := st[f]; ok {
+				patch[f] = v
+			}
+		}
+		inventory.update(idString(row["id"]), patch)
+	}
+	writeTransaction(c.w)
+}
+
+// AddLocation adds an inventory location to the store
+func (s *Server) AddLocation(l bigcommerce.InventoryLocation) bigcommerce.InventoryLocation {
+	var ret bigcommerce.InventoryLocation
+	decodeObject(s.seed("locations", l), &ret)
+	return ret
+}
@@ -0,0 +1,168 @@
+package bctest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+// metafieldOwners are the resources with metafields: the single resource path, its parent
+// parameter and the batch path
+var metafieldOwners = []struct {
+	resourceType string
+	path         string
+	param        string
+	batchPath    string
+}{
+	{bigcommerce.MetafieldProduct, "/v3/catalog/products/{product_id}/metafields", "product_id", "/v3/catalog/products/metafields"},
+	{bigcommerce.MetafieldVariant, "/v3/catalog/products/{product_id}/variants/{variant_id}/metafields", "variant_id", "/v3/catalog/variants/metafields"},
+	{bigcommerce.MetafieldCategory, "/v3/catalog/categories/{category_id}/metafields", "category_id", "/v3/catalog/categories/metafields"},
+	{bigcommerce.MetafieldBrand, "/v3/catalog/brands/{brand_id}/metafields", "brand_id", "/v3/catalog/brands/metafields"},
+	{bigcommerce.MetafieldCustomer, "/v3/customers/{customer_id}/metafields", "customer_id", "/v3/customers/metafields"},
+	{bigcommerce.MetafieldOrder, "/v3/orders/{order_id}/metafields", "order_id", "/v3/orders/metafields"},
+	{bigcommerce.MetafieldCart, "/v3/carts/{cart_id}/metafields", "cart_id", "/v3/carts/metafields"},
+	{bigcommerce.MetafieldChannel, "/v3/channels/{channel_id}/metafields", "channel_id", "/v3/channels/metafields"},
+}
+
+// metafieldRoutes registers the metafields of every resource type, stored as <type>_metafields.
+// They are registered first, the batch paths would otherwise match the {id} routes.
+func (s *Server) metafieldRoutes() {
+	for _, owner := range metafieldOwners {
+		res := &resource{
+			coll:         newCollection().withDates(time.RFC3339),
+			parentParam:  owner.param,
+			parentKey:    "resource_id",
+			stringParent: owner.resourceType == bigcommerce.MetafieldCart,
+			defaults:     object{"resource_type": owner.resourceType, "permission_set": "app_only"},
+			required:     []string{"key", "value", "namespace"},
+		}
+		s.resources[owner.resourceType+"_metafields"] = res
+		s.handle(http.MethodPost, owner.batchPath, func(c *call) { s.createMetafields(c, res) })
+		s.handle(http.MethodPut, owner.batchPath, func(c *call) { s.updateMetafields(c, res) })
+		s.handle(http.MethodDelete, owner.batchPath, func(c *call) { s.deleteMetafields(c, res) })
+		s.handle(http.MethodPost, owner.path, func(c *call) {
+			var o object
+			if !c.decode(&o) {
+				return
+			}
+			if metafieldExists(res, c.params[res.parentParam], o) {
+				writeError(c.w, http.StatusConflict, "A metafield with this namespace and key already exists", nil)
+				return
+			}
+			s.restCreate(c, res)
+		})
+		s.rest(owner.path, res)
+	}
+}
+
+// metafieldExists checks whether a resource has a metafield with the namespace and key of o
+func metafieldExists(res *resource, resourceID string, o object) bool {
+	q := url.Values{
+		"resource_id": {resourceID},
+		"namespace":   {idString(o["namespace"])},
+		"key":         {idString(o["key"])},
+	}
+	return len(res.coll.find(q)) > 0
+}
+
+// createMetafields handles the batch POST, items carry their resource_id
+func (s *Server) createMetafields(c *call, res *resource) {
+	var in []object
+	if !c.decode(&in) {
+		return
+	}
+	errs := map[string]string{}
+	for i, o := range in {
+		prefix := strconv.Itoa(i) + "."
+		for _, f := range append(res.required, "resource_id") {
+			if v, ok := o[f]; !ok || v == nil || v == "" {
+				errs[prefix+f] = f + " is a required field"
+			}
+		}
+		if metafieldExists(res, idString(o["resource_id"]), o) {
+			errs[prefix+"key"] = "a metafield with this namespace and key already exists"
+		}
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	data := []object{}
+	for _, o := range in {
+		for k, v := range res.defaults {
+			if _, ok := o[k]; !ok {
+				o[k] = v
+			}
+		}
+		if !res.stringParent {
+			o["resource_id"] = floatValue(o["resource_id"])
+		}
+		data = append(data, res.output(res.coll.insert(o), c.query))
+	}
+	writeData(c.w, http.StatusOK, data, object{"total": len(data), "success": len(data), "failed": 0})
+}
+
+// updateMetafields handles the batch PUT, items are matched by id
+func (s *Server) updateMetafields(c *call, res *resource) {
+	var in []object
+	if !c.decode(&in) {
+		return
+	}
+	errs := map[string]string{}
+	for i, o := range in {
+		if _, ok := res.coll.get(idString(o["id"])); !ok {
+			errs[strconv.Itoa(i)+".id"] = "metafield " + idString(o["id"]) + " not found"
+		}
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	data := []object{}
+	for _, o := range in {
+		delete(o, "resource_id")
+		delete(o, "resource_type")
+		updated, _ := res.coll.update(idString(o["id"]), o)
+		data = append(data, res.output(updated, c.query))
+	}
+	writeData(c.w, http.StatusOK, data, object{"total": len(data), "success": len(data), "failed": 0})
+}
+
+// deleteMetafields handles the batch DELETE, the body is the list of IDs
+func (s *Server) deleteMetafields(c *call, res *resource) {
+	var ids []interface{}
+	if !c.decode(&ids) {
+		return
+	}
+	data := []interface{}{}
+	for _, id := range ids {
+		if res.coll.remove(idString(id)) {
+			data = append(data, id)
+		}
+	}
+	writeData(c.w, http.StatusOK, data, object{"total": len(ids), "success": len(data), "failed": len(ids) - len(data)})
+}
+
+// AddMetafield adds a metafield to a resource, resourceID is the cart ID for carts
+func (s *Server) AddMetafield(resourceType, resourceID string, m bigcommerce.Metafield) bigcommerce.Metafield {
+	m.ResourceType = resourceType
+	o := toObject(m)
+	if resourceType == bigcommerce.MetafieldCart {
+		o["resource_id"] = resourceID
+	} else {
+		o["resource_id"] = float64(intValue(resourceID))
+	}
+	var ret bigcommerce.Metafield
+	decodeObject(s.seed(resourceType+"_metafields", o), &ret)
+	return ret
+}
+
+// Metafields returns the metafields of a resource type
+func (s *Server) Metafields(resourceType string) []bigcommerce.Metafield {
+	var ret []bigcommerce.Metafield
+	s.snapshot(resourceType+"_metafields", &ret)
+	return ret
+}
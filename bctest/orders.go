@@ -0,0 +1,158 @@
+package bctest
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+func (s *Server) orderRoutes() {
+	nested := func(required ...string) *resource {
+		return &resource{
+			coll:        newCollection(),
+			v2:          true,
+			parentParam: "order_id",
+			parentKey:   "order_id",
+			required:    required,
+		}
+	}
+	products := nested("product_id", "quantity")
+	addresses := nested()
+	coupons := nested("code")
+	shipments := nested("order_address_id", "items")
+	transactions := &resource{
+		coll:        newCollection(),
+		parentParam: "order_id",
+		parentKey:   "order_id",
+		required:    []string{"event", "method", "amount"},
+		// v3 transactions return the order ID as a string
+		render: func(o object, q url.Values) object {
+			t := copyObject(o)
+			t["order_id"] = idString(o["order_id"])
+			return t
+		},
+	}
+	orders := &resource{
+		coll:     newCollection().withDates(time.RFC1123Z),
+		v2:       true,
+		defaults: object{"status_id": float64(1), "status": "Pending", "channel_id": float64(1), "is_deleted": false},
+	}
+	orders.created = func(o object) {
+		id := o["id"]
+		for key, res := range map[string]*resource{"products": products, "shipping_addresses": addresses, "coupons": coupons} {
+			items, _ := o[key].([]interface{})
+			for _, item := range items {
+				if m, ok := item.(map[string]interface{}); ok {
+					m["order_id"] = id
+					res.coll.insert(m)
+				}
+			}
+			o[key] = s.orderResource(id, key)
+		}
+		if _, ok := o["items_total"]; !ok {
+			total := 0.0
+			for _, p := range products.coll.find(url.Values{"order_id": {idString(id)}}) {
+				total += floatValue(p["quantity"])
+			}
+			o["items_total"] = total
+		}
+	}
+	orders.updated = func(o object) {
+		for _, key := range []string{"products", "shipping_addresses", "coupons"} {
+			o[key] = s.orderResource(o["id"], key)
+		}
+	}
+	orders.removed = func(o object) {
+		q := url.Values{"order_id": {idString(o["id"])}}
+		for _, res := range []*resource{products, addresses, coupons, shipments, transactions} {
+			for _, item := range res.coll.find(q) {
+				res.coll.remove(idString(item["id"]))
+			}
+		}
+	}
+	shipments.created = func(o object) {
+		o["date_created"] = time.Now().UTC().Format(time.RFC1123Z)
+		if order, ok := orders.coll.get(idString(o["order_id"])); ok {
+			order["status_id"] = float64(2)
+			order["status"] = "Shipped"
+			order["date_shipped"] = o["date_created"]
+		}
+	}
+
+	s.resources["orders"] = orders
+	s.resources["order_products"] = products
+	s.resources["order_shipping_addresses"] = addresses
+	s.resources["order_coupons"] = coupons
+	s.resources["order_shipments"] = shipments
+	s.resources["order_transactions"] = transactions
+
+	s.rest("/v2/orders", orders)
+	s.rest("/v2/orders/{order_id}/products", products)
+	s.rest("/v2/orders/{order_id}/shipping_addresses", addresses)
+	s.rest("/v2/orders/{order_id}/coupons", coupons)
+	s.rest("/v2/orders/{order_id}/shipments", shipments)
+	s.rest("/v3/orders/{order_id}/transactions", transactions)
+}
+
+// orderResource returns the v2 subresource reference of an order, e.g. its products URL
+func (s *Server) orderResource(orderID interface{}, key string) object {
+	path := "/v2/orders/" + idString(orderID) + "/" + key
+	return object{
+		"url":      s.URL + "/stores/" + s.StoreHash + path,
+		"resource": path,
+	}
+}
+
+// AddOrder adds an order and its products to the store
+func (s *Server) AddOrder(o bigcommerce.Order, products []bigcommerce.OrderProduct) bigcommerce.Order {
+	obj := toObject(o)
+	ps := []interface{}{}
+	for _, p := range products {
+		ps = append(ps, map[string]interface{}(toObject(p)))
+	}
+	obj["products"] = ps
+	if _, ok := obj["shipping_addresses"].([]interface{}); !ok {
+		delete(obj, "shipping_addresses")
+	}
+	if _, ok := obj["coupons"].([]interface{}); !ok {
+		delete(obj, "coupons")
+	}
+	var ret bigcommerce.Order
+	decodeObject(s.seed("orders", obj), &ret)
+	return ret
+}
+
+// AddOrderShippingAddress adds a shipping address to an order
+func (s *Server) AddOrderShippingAddress(orderID int64, a bigcommerce.OrderShippingAddress) bigcommerce.OrderShippingAddress {
+	a.OrderID = orderID
+	var ret bigcommerce.OrderShippingAddress
+	decodeObject(s.seed("order_shipping_addresses", a), &ret)
+	return ret
+}
+
+// AddOrderCoupon adds a coupon to an order
+func (s *Server) AddOrderCoupon(orderID int64, c bigcommerce.OrderCoupon) bigcommerce.OrderCoupon {
+	c.OrderID = orderID
+	var ret bigcommerce.OrderCoupon
+	decodeObject(s.seed("order_coupons", c), &ret)
+	return ret
+}
+
+// AddOrderTransaction adds a transaction to an order
+func (s *Server) AddOrderTransaction(orderID int64, t bigcommerce.OrderTransaction) bigcommerce.OrderTransaction {
+	o := toObject(t)
+	o["order_id"] = float64(orderID)
+	var ret bigcommerce.OrderTransaction
+	decodeObject(s.seed("order_transactions", o), &ret)
+	ret.OrderID = strconv.FormatInt(orderID, 10)
+	return ret
+}
+
+// Orders returns the orders currently in the store
+func (s *Server) Orders() []bigcommerce.Order {
+	var ret []bigcommerce.Order
+	s.snapshot("orders", &ret)
+	return ret
+}
@@ -0,0 +1,188 @@
+package bctest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+// priceListRoutes registers the price lists with their records and assignments
+func (s *Server) priceListRoutes() {
+	records := &resource{
+		coll:        newCollection().withDates(time.RFC3339),
+		parentParam: "price_list_id",
+		parentKey:   "price_list_id",
+	}
+	assignments := &resource{coll: newCollection()}
+	priceLists := &resource{
+		coll:     newCollection().withDates(time.RFC3339),
+		required: []string{"name"},
+		defaults: object{"active": true},
+	}
+	priceLists.removed = func(o object) {
+		q := url.Values{"price_list_id": {idString(o["id"])}}
+		for _, r := range records.coll.find(q) {
+			records.coll.remove(idString(r["id"]))
+		}
+		for _, a := range assignments.coll.find(q) {
+			assignments.coll.remove(idString(a["id"]))
+		}
+	}
+	records.render = func(o object, q url.Values) object {
+		ret := copyObject(o)
+		delete(ret, "id")
+		return ret
+	}
+	s.resources["price_lists"] = priceLists
+	s.resources["price_records"] = records
+	s.resources["price_list_assignments"] = assignments
+
+	s.handle(http.MethodGet, "/v3/pricelists/assignments", func(c *call) { s.restList(c, assignments) })
+	s.handle(http.MethodPost, "/v3/pricelists/assignments", func(c *call) { s.createPriceListAssignments(c, priceLists, assignments) })
+	s.handle(http.MethodDelete, "/v3/pricelists/assignments", func(c *call) { s.restBatchDelete(c, assignments) })
+	s.handle(http.MethodGet, "/v3/pricelists/{price_list_id}/records", func(c *call) {
+		if _, ok := priceLists.coll.get(c.params["price_list_id"]); !ok {
+			writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+			return
+		}
+		s.restList(c, records)
+	})
+	s.handle(http.MethodPut, "/v3/pricelists/{price_list_id}/records", func(c *call) { s.upsertPriceRecords(c, priceLists, records) })
+	s.handle(http.MethodDelete, "/v3/pricelists/{price_list_id}/records", func(c *call) { s.restBatchDelete(c, records) })
+	s.handle(http.MethodGet, "/v3/pricelists/{price_list_id}/records/{variant_id}/{currency}", func(c *call) {
+		found := records.coll.find(url.Values{
+			"price_list_id": {c.params["price_list_id"]},
+			"variant_id":    {c.params["variant_id"]},
+			"currency":      {strings.ToLower(c.params["currency"])},
+		})
+		if len(found) == 0 {
+			writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+			return
+		}
+		writeData(c.w, http.StatusOK, records.output(found[0], c.query), nil)
+	})
+	s.rest("/v3/pricelists", priceLists)
+}
+
+// upsertPriceRecords handles PUT /v3/pricelists/{price_list_id}/records, records are matched by
+// variant and currency, variants by variant_id or sku. Nothing is saved when a record is invalid.
+func (s *Server) upsertPriceRecords(c *call, priceLists, records *resource) {
+	listID := c.params["price_list_id"]
+	if _, ok := priceLists.coll.get(listID); !ok {
+		writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+		return
+	}
+	var in []object
+	if !c.decode(&in) {
+		return
+	}
+	variants := s.resources["variants"].coll
+	errs := map[string]string{}
+	resolved := make([]object, len(in))
+	for i, o := range in {
+		key := strconv.Itoa(i)
+		var v object
+		var ok bool
+		if id := idString(o["variant_id"]); id != "" && id != "0" {
+			v, ok = variants.get(id)
+			if !ok {
+				errs[key+".variant_id"] = "variant " + id + " not found"
+				continue
+			}
+		} else {
+			found := variants.find(url.Values{"sku": {idString(o["sku"])}})
+			if idString(o["sku"]) == "" || len(found) == 0 {
+				errs[key+".sku"] = "variant with sku " + idString(o["sku"]) + " not found"
+				continue
+			}
+			v = found[0]
+		}
+		if idString(o["currency"]) == "" {
+			errs[key+".currency"] = "currency is a required field"
+		}
+		if _, ok := o["price"]; !ok {
+			errs[key+".price"] = "price is a required field"
+		}
+		resolved[i] = v
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	for i, o := range in {
+		v := resolved[i]
+		o["variant_id"] = v["id"]
+		o["product_id"] = v["product_id"]
+		o["sku"] = v["sku"]
+		o["currency"] = strings.ToLower(idString(o["currency"]))
+		o["price_list_id"] = float64(intValue(listID))
+		o["calculated_price"] = o["price"]
+		if floatValue(o["sale_price"]) != 0 {
+			o["calculated_price"] = o["sale_price"]
+		}
+		found := records.coll.find(url.Values{
+			"price_list_id": {listID},
+			"variant_id":    {idString(v["id"])},
+			"currency":      {idString(o["currency"])},
+		})
+		if len(found) > 0 {
+			records.coll.update(idString(found[0]["id"]), o)
+			continue
+		}
+		records.coll.insert(o)
+	}
+	writeData(c.w, http.StatusOK, object{}, nil)
+}
+
+// createPriceListAssignments handles POST /v3/pricelists/assignments, a customer group has at most
+// one price list per channel
+func (s *Server) createPriceListAssignments(c *call, priceLists, assignments *resource) {
+	var in []object
+	if !c.decode(&in) {
+		return
+	}
+	errs := map[string]string{}
+	seen := map[string]bool{}
+	for i, a := range in {
+		key := strconv.Itoa(i)
+		if _, ok := priceLists.coll.get(idString(a["price_list_id"])); !ok {
+			errs[key+".price_list_id"] = "price list " + idString(a["price_list_id"]) + " not found"
+			continue
+		}
+		group, channel := strconv.FormatInt(intValue(a["customer_group_id"]), 10), strconv.FormatInt(intValue(a["channel_id"]), 10)
+		if group == "0" && channel == "0" {
+			errs[key+".customer_group_id"] = "customer_group_id or channel_id is required"
+			continue
+		}
+		taken := false
+		for _, old := range assignments.coll.find(url.Values{}) {
+			if strconv.FormatInt(intValue(old["customer_group_id"]), 10) == group && strconv.FormatInt(intValue(old["channel_id"]), 10) == channel {
+				taken = true
+			}
+		}
+		if taken || seen[group+"/"+channel] {
+			errs[key+".customer_group_id"] = "customer group " + group + " already has a price list in channel " + channel
+		}
+		seen[group+"/"+channel] = true
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	for _, a := range in {
+		delete(a, "id")
+		assignments.coll.insert(a)
+	}
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+// AddPriceList adds a price list to the store
+func (s *Server) AddPriceList(pl bigcommerce.PriceList) bigcommerce.PriceList {
+	var ret bigcommerce.PriceList
+	decodeObject(s.seed("price_lists", pl), &ret)
+	return ret
+}
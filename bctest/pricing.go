@@ -0,0 +1,239 @@
+package bctest
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// pricingRoutes registers the pricing API. Prices come from the variants, falling back to their
+// product, overridden by the price list assigned to the customer group and channel. There is no tax.
+func (s *Server) pricingRoutes() {
+	s.handle(http.MethodPost, "/v3/pricing/products", s.priceProducts)
+}
+
+type pricingRequest struct {
+	ChannelID       int64  `json:"channel_id"`
+	CurrencyCode    string `json:"currency_code"`
+	CustomerGroupID int64  `json:"customer_group_id"`
+	Items           []struct {
+		ProductID int64 `json:"product_id"`
+		VariantID int64 `json:"variant_id"`
+		Options   []struct {
+			OptionID int64 `json:"option_id"`
+			ValueID  int64 `json:"value_id"`
+		} `json:"options"`
+	} `json:"items"`
+}
+
+func (s *Server) priceProducts(c *call) {
+	var in pricingRequest
+	if !c.decode(&in) {
+		return
+	}
+	if len(in.Items) > 50 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid",
+			map[string]string{"items": "at most 50 items can be priced at once"})
+		return
+	}
+	currency := strings.ToLower(in.CurrencyCode)
+	if currency == "" {
+		currency = "usd"
+	}
+	priceList := s.assignedPriceList(in.CustomerGroupID, in.ChannelID)
+	products, variants := s.resources["products"].coll, s.resources["variants"].coll
+	errs := map[string]string{}
+	data := []object{}
+	for i, item := range in.Items {
+		key := "items." + strconv.Itoa(i)
+		p, ok := products.get(strconv.FormatInt(item.ProductID, 10))
+		if !ok {
+			errs[key+".product_id"] = "product " + strconv.FormatInt(item.ProductID, 10) + " not found"
+			continue
+		}
+		all := variants.find(url.Values{"product_id": {idString(p["id"])}})
+		var v object
+		switch {
+		case item.VariantID != 0:
+			v, ok = variants.get(strconv.FormatInt(item.VariantID, 10))
+			if !ok || idString(v["product_id"]) != idString(p["id"]) {
+				errs[key+".variant_id"] = "variant " + strconv.FormatInt(item.VariantID, 10) + " not found"
+				continue
+			}
+		case len(item.Options) > 0:
+			for _, candidate := range all {
+				matched := true
+				for _, sel := range item.Options {
+					matched = matched && variantHasValue(candidate, sel.OptionID, sel.ValueID)
+				}
+				if matched {
+					v = candidate
+					break
+				}
+			}
+			if v == nil {
+				errs[key+".options"] = "no variant matches the options"
+				continue
+			}
+		}
+		base := v
+		if base == nil {
+			base, _ = variants.get(idString(p["base_variant_id"]))
+		}
+		prices := s.variantPrices(p, base, priceList, currency)
+		out := object{
+			"product_id":               p["id"],
+			"variant_id":               float64(0),
+			"options":                  item.Options,
+			"reference_request":        item,
+			"price":                    priceValue(prices["price"]),
+			"calculated_price":         priceValue(prices["calculated_price"]),
+			"sale_price":               optionalPrice(prices["sale_price"]),
+			"retail_price":             optionalPrice(prices["retail_price"]),
+			"minimum_advertised_price": optionalPrice(prices["map_price"]),
+			"bulk_pricing":             s.bulkTiers(p, base, priceList, currency),
+		}
+		if v != nil {
+			out["variant_id"] = v["id"]
+			all = []object{v}
+		}
+		out["price_range"] = s.priceRange(p, all, priceList, currency, "calculated_price")
+		out["retail_price_range"] = s.priceRange(p, all, priceList, currency, "retail_price")
+		data = append(data, out)
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	writeData(c.w, http.StatusOK, data, nil)
+}
+
+// variantHasValue checks that a variant has the value of an option
+func variantHasValue(v object, optionID, valueID int64) bool {
+	values, _ := v["option_values"].([]interface{})
+	for _, ov := range values {
+		m, _ := ov.(map[string]interface{})
+		if intValue(m["option_id"]) == optionID && intValue(m["id"]) == valueID {
+			return true
+		}
+	}
+	return false
+}
+
+// assignedPriceList returns the ID of the active price list of a customer group in a channel,
+// assignments to the group or channel only apply when there is no exact match
+func (s *Server) assignedPriceList(groupID, channelID int64) string {
+	lists := s.resources["price_lists"].coll
+	best, bestScore := "", 0
+	for _, a := range s.resources["price_list_assignments"].coll.find(url.Values{}) {
+		g, ch := intValue(a["customer_group_id"]), intValue(a["channel_id"])
+		if (g != 0 && g != groupID) || (ch != 0 && ch != channelID) {
+			continue
+		}
+		pl, ok := lists.get(idString(a["price_list_id"]))
+		if !ok || pl["active"] == false {
+			continue
+		}
+		score := 1
+		if g != 0 {
+			score += 2
+		}
+		if ch != 0 {
+			score++
+		}
+		if score > bestScore {
+			best, bestScore = idString(a["price_list_id"]), score
+		}
+	}
+	return best
+}
+
+// priceRecord returns the record of a variant in a price list, nil if there is none
+func (s *Server) priceRecord(priceList string, v object, currency string) object {
+	if priceList == "" || v == nil {
+		return nil
+	}
+	found := s.resources["price_records"].coll.find(url.Values{
+		"price_list_id": {priceList},
+		"variant_id":    {idString(v["id"])},
+		"currency":      {currency},
+	})
+	if len(found) == 0 {
+		return nil
+	}
+	return found[0]
+}
+
+// variantPrices returns the price, sale_price, retail_price, map_price and calculated_price of a variant
+func (s *Server) variantPrices(p, v object, priceList, currency string) map[string]float64 {
+	ret := map[string]float64{}
+	for _, f := range []string{"price", "sale_price", "retail_price", "map_price"} {
+		ret[f] = floatValue(p[f])
+		if v != nil && floatValue(v[f]) != 0 {
+			ret[f] = floatValue(v[f])
+		}
+	}
+	if r := s.priceRecord(priceList, v, currency); r != nil {
+		for _, f := range []string{"price", "sale_price", "retail_price", "map_price"} {
+			ret[f] = floatValue(r[f])
+		}
+	}
+	ret["calculated_price"] = ret["price"]
+	if ret["sale_price"] != 0 {
+		ret["calculated_price"] = ret["sale_price"]
+	}
+	return ret
+}
+
+// bulkTiers returns the bulk pricing tiers of the price list record, or of the product
+func (s *Server) bulkTiers(p, v object, priceList, currency string) []object {
+	var rules []interface{}
+	if r := s.priceRecord(priceList, v, currency); r != nil {
+		rules, _ = r["bulk_pricing_tiers"].([]interface{})
+	}
+	if len(rules) == 0 {
+		for _, o := range s.resources["bulk-pricing-rules"].coll.find(url.Values{"product_id": {idString(p["id"])}}) {
+			rules = append(rules, map[string]interface{}(o))
+		}
+	}
+	ret := []object{}
+	for _, r := range rules {
+		m, _ := r.(map[string]interface{})
+		ret = append(ret, object{
+			"minimum":             m["quantity_min"],
+			"maximum":             m["quantity_max"],
+			"discount_amount":     m["amount"],
+			"discount_type":       m["type"],
+			"tax_discount_amount": []interface{}{},
+		})
+	}
+	return ret
+}
+
+// priceRange returns the lowest and highest value of a price field over variants
+func (s *Server) priceRange(p object, variants []object, priceList, currency, field string) object {
+	var min, max float64
+	for i, v := range variants {
+		price := s.variantPrices(p, v, priceList, currency)[field]
+		if i == 0 || price < min {
+			min = price
+		}
+		if i == 0 || price > max {
+			max = price
+		}
+	}
+	return object{"minimum": priceValue(min), "maximum": priceValue(max)}
+}
+
+func priceValue(price float64) object {
+	return object{"as_entered": price, "entered_inclusive": false, "tax_exclusive": price, "tax_inclusive": price}
+}
+
+// optionalPrice returns nil for prices that are not set
+func optionalPrice(price float64) interface{} {
+	if price == 0 {
+		return nil
+	}
+	return priceValue(price)
+}
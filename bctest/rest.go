@@ -0,0 +1,307 @@
+package bctest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// resource describes how a collection is exposed as a REST endpoint
+type resource struct {
+	coll *collection
+	// v2 resources are returned bare, v3 resources in a data/meta envelope
+	v2 bool
+	// parent path parameter and the field holding its value, for nested resources
+	parentParam string
+	parentKey   string
+	// stringParent keeps the parent ID a string, for parents with UUIDs like carts
+	stringParent bool
+	// defaults are set on new objects
+	defaults object
+	// POST and PUT on the collection path accept arrays
+	batchCreate bool
+	batchUpdate bool
+	// required fields on create
+	required []string
+	// created, updated and removed are called after an object is changed
+	created func(o object)
+	updated func(o object)
+	removed func(o object)
+	// render returns the object as sent to the client
+	render func(o object, q url.Values) object
+}
+
+// rest registers list, create, batch update, batch delete, get, update and delete
+// handlers for a resource under path
+func (s *Server) rest(path string, res *resource) {
+	s.handle(http.MethodGet, path, func(c *call) { s.restList(c, res) })
+	s.handle(http.MethodPost, path, func(c *call) { s.restCreate(c, res) })
+	s.handle(http.MethodPut, path, func(c *call) { s.restBatchUpdate(c, res) })
+	s.handle(http.MethodDelete, path, func(c *call) { s.restBatchDelete(c, res) })
+	item := path + "/{" + res.coll.idKey + "}"
+	s.handle(http.MethodGet, item, func(c *call) { s.restGet(c, res) })
+	s.handle(http.MethodPut, item, func(c *call) { s.restUpdate(c, res) })
+	s.handle(http.MethodDelete, item, func(c *call) { s.restDelete(c, res) })
+}
+
+func (res *resource) output(o object, q url.Values) object {
+	if res.render != nil {
+		o = res.render(o, q)
+	}
+	return project(o, q, res.coll.idKey)
+}
+
+// scope adds the parent filter to a query
+func (s *Server) scope(c *call, res *resource, q url.Values) {
+	if res.parentParam != "" {
+		q.Set(res.parentKey, c.params[res.parentParam])
+	}
+}
+
+// owned checks that an object belongs to the parent in the path
+func (res *resource) owned(c *call, o object) bool {
+	if res.parentParam == "" {
+		return true
+	}
+	return idString(o[res.parentKey]) == c.params[res.parentParam]
+}
+
+func (s *Server) restList(c *call, res *resource) {
+	q := url.Values{}
+	for k, v := range c.query {
+		q[k] = v
+	}
+	s.scope(c, res, q)
+	p := paginate(res.coll.find(q), q)
+	data := []object{}
+	for _, o := range p.items {
+		data = append(data, res.output(o, q))
+	}
+	if res.v2 {
+		if len(data) == 0 {
+			c.w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(c.w, http.StatusOK, data)
+		return
+	}
+	writeData(c.w, http.StatusOK, data, p.meta)
+}
+
+// validate checks the required fields, writing a 422 response if any is missing
+func (res *resource) validate(c *call, o object) bool {
+	errs := map[string]string{}
+	for _, f := range res.required {
+		if v, ok := o[f]; !ok || v == nil || v == "" {
+			errs[f] = f + " is a required field"
+		}
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return false
+	}
+	return true
+}
+
+func (s *Server) insert(c *call, res *resource, o object) object {
+	for k, v := range res.defaults {
+		if _, ok := o[k]; !ok {
+			o[k] = v
+		}
+	}
+	if res.parentParam != "" {
+		parent := c.params[res.parentParam]
+		if res.coll.uuids || res.stringParent {
+			o[res.parentKey] = parent
+		} else {
+			o[res.parentKey] = float64(intValue(parent))
+		}
+	}
+	o = res.coll.insert(o)
+	if res.created != nil {
+		res.created(o)
+	}
+	return o
+}
+
+func (s *Server) restCreate(c *call, res *resource) {
+	if res.batchCreate && strings.HasPrefix(strings.TrimSpace(string(c.body)), "[") {
+		var in []object
+		if !c.decode(&in) {
+			return
+		}
+		for _, o := range in {
+			if !res.validate(c, o) {
+				return
+			}
+		}
+		data := []object{}
+		for _, o := range in {
+			data = append(data, res.output(s.insert(c, res, o), c.query))
+		}
+		writeData(c.w, http.StatusOK, data, nil)
+		return
+	}
+	var o object
+	if !c.decode(&o) {
+		return
+	}
+	if !res.validate(c, o) {
+		return
+	}
+	o = s.insert(c, res, o)
+	if res.v2 {
+		writeJSON(c.w, http.StatusCreated, res.output(o, c.query))
+		return
+	}
+	writeData(c.w, http.StatusOK, res.output(o, c.query), nil)
+}
+
+func (s *Server) restBatchUpdate(c *call, res *resource) {
+	if !res.batchUpdate {
+		writeError(c.w, http.StatusMethodNotAllowed, "Method not allowed", nil)
+		return
+	}
+	var in []object
+	if !c.decode(&in) {
+		return
+	}
+	for i, o := range in {
+		stored, ok := res.coll.get(idString(o[res.coll.idKey]))
+		if !ok || !res.owned(c, stored) {
+			writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid",
+				map[string]string{res.coll.idKey: "item " + strconv.Itoa(i) + ": " + res.coll.idKey + " " + idString(o[res.coll.idKey]) + " not found"})
+			return
+		}
+	}
+	data := []object{}
+	for _, o := range in {
+		delete(o, res.parentKey)
+		updated, _ := res.coll.update(idString(o[res.coll.idKey]), o)
+		if res.updated != nil {
+			res.updated(updated)
+		}
+		data = append(data, res.output(updated, c.query))
+	}
+	writeData(c.w, http.StatusOK, data, nil)
+}
+
+func (s *Server) restBatchDelete(c *call, res *resource) {
+	q := url.Values{}
+	for k, v := range c.query {
+		q[k] = v
+	}
+	filtered := false
+	for k := range q {
+		if !reservedParams[k] {
+			filtered = true
+		}
+	}
+	if !filtered {
+		writeError(c.w, http.StatusUnprocessableEntity, "At least one filter is required", nil)
+		return
+	}
+	s.scope(c, res, q)
+	for _, o := range res.coll.find(q) {
+		res.coll.remove(idString(o[res.coll.idKey]))
+		if res.removed != nil {
+			res.removed(o)
+		}
+	}
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) restGet(c *call, res *resource) {
+	o, ok := res.coll.get(c.params[res.coll.idKey])
+	if !ok || !res.owned(c, o) {
+		writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+		return
+	}
+	if res.v2 {
+		writeJSON(c.w, http.StatusOK, res.output(o, c.query))
+		return
+	}
+	writeData(c.w, http.StatusOK, res.output(o, c.query), nil)
+}
+
+func (s *Server) restUpdate(c *call, res *resource) {
+	id := c.params[res.coll.idKey]
+	o, ok := res.coll.get(id)
+	if !ok || !res.owned(c, o) {
+		writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+		return
+	}
+	var patch object
+	if !c.decode(&patch) {
+		return
+	}
+	delete(patch, res.parentKey)
+	o, _ = res.coll.update(id, patch)
+	if res.updated != nil {
+		res.updated(o)
+	}
+	if res.v2 {
+		writeJSON(c.w, http.StatusOK, res.output(o, c.query))
+		return
+	}
+	writeData(c.w, http.StatusOK, res.output(o, c.query), nil)
+}
+
+func (s *Server) restDelete(c *call, res *resource) {
+	id := c.params[res.coll.idKey]
+	o, ok := res.coll.get(id)
+	if !ok || !res.owned(c, o) {
+		writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+		return
+	}
+	res.coll.remove(id)
+	if res.removed != nil {
+		res.removed(o)
+	}
+	c.w.WriteHeader(http.StatusNoContent)
+}
+
+// seed inserts an object into a resource without going through HTTP
+func (s *Server) seed(name string, v interface{}) object {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := s.resources[name]
+	o := res.coll.insertKeepingID(toObject(v))
+	if res.created != nil {
+		res.created(o)
+	}
+	return o
+}
+
+// insertKeepingID stores an object, keeping its ID if it has one
+func (c *collection) insertKeepingID(o object) object {
+	id := idString(o[c.idKey])
+	if id == "" || id == "0" {
+		return c.insert(o)
+	}
+	if c.index(id) >= 0 {
+		c.remove(id)
+	}
+	if c.dateFormat != "" {
+		now := time.Now().UTC().Format(c.dateFormat)
+		for _, f := range []string{"date_created", "date_modified"} {
+			if d := idString(o[f]); d == "" || strings.HasPrefix(d, "0001-01-01") {
+				o[f] = now
+			}
+		}
+	}
+	if !c.uuids && int64(floatValue(o[c.idKey])) >= c.nextID {
+		c.nextID = int64(floatValue(o[c.idKey])) + 1
+	}
+	c.items = append(c.items, o)
+	return o
+}
+
+// decodeObject converts an object back to a typed value
+func decodeObject(o object, v interface{}) {
+	b, _ := json.Marshal(o)
+	json.Unmarshal(b, v)
+}
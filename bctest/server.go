@@ -0,0 +1,376 @@
+// Package bctest provides an in-process fake BigCommerce API server for integration tests.
+//
+// The server implements the v2 and v3 endpoints used by the bigcommerce package with
+// in-memory state, pagination and filters, and can inject errors and simulate rate limits.
+// Clients returned by Server.Client send their requests to the fake server, so tests
+// exercise the real HTTP code of bigcommerce.Client.
+//
+// Use:
+//
+//	srv := bctest.NewServer()
+//	defer srv.Close()
+//	srv.AddProduct(bigcommerce.Product{Name: "Shirt", Type: "physical", Sku: "SHIRT"})
+//	client := srv.Client()
+//	products, err := client.GetAllProducts(nil)
+package bctest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+// Default credentials of the fake store
+const (
+	StoreHash = "bctest"
+	AuthToken = "bctest-token"
+)
+
+// Fault is an error the server returns instead of handling a matching request
+type Fault struct {
+	Method string // HTTP method to match, empty matches all methods
+	Path   string // API path prefix to match, e.g. /v3/catalog/products
+	Status int    // HTTP status code to return
+	Body   string // response body, a BigCommerce style error is generated when empty
+	Times  int    // how many requests fail, 0 means every matching request
+}
+
+// Request is a request received by the server
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Server is a fake BigCommerce API server
+type Server struct {
+	*httptest.Server
+	StoreHash string
+	AuthToken string
+
+	mu        sync.Mutex
+	routes    []route
+	resources map[string]*resource
+	faults    []*Fault
+	requests  []Request
+
+	// customer passwords by customer ID
+	passwords map[int64]string
+	// customer form field values by customer ID and field name
+	formFields map[int64]map[string]interface{}
+	// store information returned by /v2/store
+	storeInfo object
+
+	rateQuota   int
+	rateWindow  time.Duration
+	windowStart time.Time
+	windowCount int
+}
+
+// NewServer starts a fake BigCommerce API server with an empty store
+func NewServer() *Server {
+	s := &Server{
+		StoreHash:  StoreHash,
+		AuthToken:  AuthToken,
+		resources:  map[string]*resource{},
+		passwords:  map[int64]string{},
+		formFields: map[int64]map[string]interface{}{},
+		storeInfo: object{
+			"id":       StoreHash,
+			"domain":   "bctest.example.com",
+			"name":     "BigCommerce Test Store",
+			"currency": "USD",
+		},
+	}
+	s.registerRoutes()
+	s.Server = httptest.NewServer(s)
+	return s
+}
+
+func (s *Server) registerRoutes() {
+	s.metafieldRoutes()
+	s.catalogRoutes()
+	s.customerRoutes()
+	s.cartRoutes()
+	s.orderRoutes()
+	s.channelRoutes()
+	s.priceListRoutes()
+	s.pricingRoutes()
+	s.inventoryRoutes()
+	s.contentRoutes()
+	s.channelListingRoutes()
+}
+
+// snapshot decodes the current objects of a resource into v, a pointer to a slice
+func (s *Server) snapshot(name string, v interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := s.resources[name]
+	items := []object{}
+	for _, o := range res.coll.find(url.Values{}) {
+		items = append(items, res.output(o, url.Values{}))
+	}
+	b, _ := json.Marshal(items)
+	json.Unmarshal(b, v)
+}
+
+// Client returns a BigCommerce API client talking to the fake server
+func (s *Server) Client() *bigcommerce.Client {
+	c := bigcommerce.NewClient(s.StoreHash, s.AuthToken)
+	c.HTTPClient = s.HTTPClient()
+	return c
+}
+
+// HTTPClient returns an HTTP client that sends every request to the fake server,
+// whatever host it was addressed to
+func (s *Server) HTTPClient() *http.Client {
+	target, _ := url.Parse(s.URL)
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &rewriteTransport{target: target},
+	}
+}
+
+type rewriteTransport struct {
+	target *url.URL
+}
+
+func (t *rewriteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	r.URL.Scheme = t.target.Scheme
+	r.URL.Host = t.target.Host
+	r.Host = t.target.Host
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// InjectFault makes the server fail matching requests, see Fault
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// SetRateLimit limits the server to quota requests per window, requests over the quota get
+// a 429 response with BigCommerce rate limit headers. A zero quota disables the limit.
+func (s *Server) SetRateLimit(quota int, window time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateQuota = quota
+	s.rateWindow = window
+	s.windowStart = time.Now()
+	s.windowCount = 0
+}
+
+// Requests returns every request the server received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request{}, s.requests...)
+}
+
+// SetStoreInfo sets the store information returned by /v2/store
+func (s *Server) SetStoreInfo(info bigcommerce.StoreInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.storeInfo = toObject(info)
+}
+
+// ServeHTTP handles an API request
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prefix := "/stores/" + s.StoreHash
+	if !strings.HasPrefix(r.URL.Path, prefix+"/") {
+		writeError(w, http.StatusNotFound, "store not found", nil)
+		return
+	}
+	path := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, prefix), "/")
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+
+	if r.Header.Get("X-Auth-Token") != s.AuthToken {
+		writeError(w, http.StatusUnauthorized, "You are not authorized to access this resource", nil)
+		return
+	}
+	if !s.rateLimit(w) {
+		return
+	}
+	if s.fault(w, r.Method, path) {
+		return
+	}
+
+	for _, rt := range s.routes {
+		params, ok := rt.match(r.Method, path)
+		if !ok {
+			continue
+		}
+		rt.handler(&call{w: w, r: r, body: body, params: params, query: r.URL.Query()})
+		return
+	}
+	writeError(w, http.StatusNotFound, "The requested resource was not found", nil)
+}
+
+// rateLimit writes the rate limit headers, returns false when the request is over quota
+func (s *Server) rateLimit(w http.ResponseWriter) bool {
+	if s.rateQuota <= 0 {
+		return true
+	}
+	now := time.Now()
+	if now.Sub(s.windowStart) >= s.rateWindow {
+		s.windowStart = now
+		s.windowCount = 0
+	}
+	s.windowCount++
+	reset := s.rateWindow - now.Sub(s.windowStart)
+	left := s.rateQuota - s.windowCount
+	if left < 0 {
+		left = 0
+	}
+	h := w.Header()
+	h.Set("X-Rate-Limit-Requests-Quota", strconv.Itoa(s.rateQuota))
+	h.Set("X-Rate-Limit-Time-Window-Ms", strconv.FormatInt(s.rateWindow.Milliseconds(), 10))
+	h.Set("X-Rate-Limit-Time-Reset-Ms", strconv.FormatInt(reset.Milliseconds(), 10))
+	h.Set("X-Rate-Limit-Requests-Left", strconv.Itoa(left))
+	if s.windowCount > s.rateQuota {
+		h.Set("Retry-After", strconv.Itoa(int(reset.Seconds())+1))
+		writeError(w, http.StatusTooManyRequests, "Too many requests", nil)
+		return false
+	}
+	return true
+}
+
+// fault writes an injected fault response, returns true if the request matched one
+func (s *Server) fault(w http.ResponseWriter, method, path string) bool {
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != method {
+			continue
+		}
+		if !strings.HasPrefix(path, f.Path) {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		if f.Body == "" {
+			writeError(w, f.Status, http.StatusText(f.Status), nil)
+			return true
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(f.Status)
+		fmt.Fprint(w, f.Body)
+		return true
+	}
+	return false
+}
+
+// route is an API endpoint, pattern segments like {id} are path parameters
+type route struct {
+	method   string
+	segments []string
+	handler  func(c *call)
+}
+
+func (rt route) match(method, path string) (map[string]string, bool) {
+	if rt.method != method {
+		return nil, false
+	}
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(parts) != len(rt.segments) {
+		return nil, false
+	}
+	params := map[string]string{}
+	for i, seg := range rt.segments {
+		if strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}") {
+			params[seg[1:len(seg)-1]] = parts[i]
+			continue
+		}
+		if seg != parts[i] {
+			return nil, false
+		}
+	}
+	return params, true
+}
+
+func (s *Server) handle(method, pattern string, handler func(c *call)) {
+	s.routes = append(s.routes, route{
+		method:   method,
+		segments: strings.Split(strings.Trim(pattern, "/"), "/"),
+		handler:  handler,
+	})
+}
+
+// call is the request being handled
+type call struct {
+	w      http.ResponseWriter
+	r      *http.Request
+	body   []byte
+	params map[string]string
+	query  url.Values
+}
+
+// decode unmarshals the request body, writing a 400 response on failure
+func (c *call) decode(v interface{}) bool {
+	err := json.Unmarshal(c.body, v)
+	if err != nil {
+		writeError(c.w, http.StatusBadRequest, "Input is invalid: "+err.Error(), nil)
+		return false
+	}
+	return true
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeData writes a v3 style {"data": ..., "meta": ...} response
+func writeData(w http.ResponseWriter, status int, data interface{}, meta interface{}) {
+	if meta == nil {
+		meta = object{}
+	}
+	writeJSON(w, status, object{"data": data, "meta": meta})
+}
+
+// writeError writes a BigCommerce style error response
+func writeError(w http.ResponseWriter, status int, title string, errs map[string]string) {
+	e := object{
+		"status": status,
+		"title":  title,
+		"type":   "https://developer.bigcommerce.com/api-docs/getting-started/api-status-codes",
+	}
+	if errs != nil {
+		e["errors"] = errs
+	}
+	writeJSON(w, status, e)
+}
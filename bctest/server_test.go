@@ -0,0 +1,271 @@
+package bctest_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+	"github.com/mvalenziano/bigcommerce-api-go/bctest"
+)
+
+// response is a decoded API response
+type response struct {
+	status int
+	header http.Header
+	body   map[string]interface{}
+}
+
+// send sends a request to the fake server like the API client does, path is the part after the store
+func send(t *testing.T, s *bctest.Server, method, path, body string) response {
+	t.Helper()
+	req, err := http.NewRequest(method, "https://api.bigcommerce.com"+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-Auth-Token", s.AuthToken)
+	req.Header.Set("Content-Type", "application/json")
+	res, err := s.HTTPClient().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	ret := response{status: res.StatusCode, header: res.Header, body: map[string]interface{}{}}
+	json.NewDecoder(res.Body).Decode(&ret.body)
+	return ret
+}
+
+func TestRouting(t *testing.T) {
+	s := bctest.NewServer()
+	defer s.Close()
+	p := s.AddProduct(bigcommerce.Product{Name: "Shirt", Type: "physical", Sku: "SHIRT", Price: 10})
+	store := "/stores/" + bctest.StoreHash
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		status int
+		wantID int64 // ID of the returned object, 0 to skip the check
+	}{
+		{"list", http.MethodGet, store + "/v3/catalog/products", "", http.StatusOK, 0},
+		{"path parameter", http.MethodGet, fmt.Sprintf("%s/v3/catalog/products/%d", store, p.ID), "", http.StatusOK, p.ID},
+		{"trailing slash", http.MethodGet, fmt.Sprintf("%s/v3/catalog/products/%d/", store, p.ID), "", http.StatusOK, p.ID},
+		{"unknown object", http.MethodGet, store + "/v3/catalog/products/999", "", http.StatusNotFound, 0},
+		{"unknown endpoint", http.MethodGet, store + "/v3/unknown", "", http.StatusNotFound, 0},
+		{"method without route", http.MethodPatch, store + "/v3/catalog/products", "", http.StatusNotFound, 0},
+		{"other store", http.MethodGet, "/stores/other/v3/catalog/products", "", http.StatusNotFound, 0},
+		{"create", http.MethodPost, store + "/v3/catalog/products", `{"name":"Hat","type":"physical","weight":1,"price":5}`, http.StatusOK, p.ID + 1},
+		{"missing required field", http.MethodPost, store + "/v3/catalog/products", `{"name":"Cap"}`, http.StatusUnprocessableEntity, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := send(t, s, tt.method, tt.path, tt.body)
+			if res.status != tt.status {
+				t.Fatalf("status %d, want %d: %v", res.status, tt.status, res.body)
+			}
+			if tt.wantID == 0 {
+				return
+			}
+			data, _ := res.body["data"].(map[string]interface{})
+			if id, _ := data["id"].(float64); int64(id) != tt.wantID {
+				t.Errorf("object %v, want ID %d", data, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestAuthToken(t *testing.T) {
+	s := bctest.NewServer()
+	defer s.Close()
+	bc := bigcommerce.NewClient(s.StoreHash, "wrong-token")
+	bc.HTTPClient = s.HTTPClient()
+	if _, err := bc.GetAllProducts(nil); err == nil {
+		t.Error("no error with a wrong auth token")
+	}
+	if _, err := s.Client().GetAllProducts(nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	productsPath := "/stores/" + bctest.StoreHash + "/v3/catalog/products"
+	tests := []struct {
+		name  string
+		fault bctest.Fault
+		// requests are sent in order as "METHOD path", want holds the expected statuses
+		requests []string
+		want     []int
+		// title is the error title of the first response
+		title string
+	}{
+		{
+			name:     "every matching request",
+			fault:    bctest.Fault{Path: "/v3/catalog", Status: http.StatusInternalServerError},
+			requests: []string{"GET /v3/catalog/products", "GET /v3/catalog/categories", "GET /v3/customers"},
+			want:     []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK},
+			title:    "Internal Server Error",
+		},
+		{
+			name:     "method",
+			fault:    bctest.Fault{Method: http.MethodPost, Path: "/v3/catalog/products", Status: http.StatusConflict},
+			requests: []string{"GET /v3/catalog/products", "POST /v3/catalog/products"},
+			want:     []int{http.StatusOK, http.StatusConflict},
+		},
+		{
+			name:     "times",
+			fault:    bctest.Fault{Path: "/v3/catalog/products", Status: http.StatusServiceUnavailable, Times: 2},
+			requests: []string{"GET /v3/catalog/products", "GET /v3/catalog/products", "GET /v3/catalog/products"},
+			want:     []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			title:    "Service Unavailable",
+		},
+		{
+			name:     "body",
+			fault:    bctest.Fault{Path: "/v3/catalog/products", Status: http.StatusUnprocessableEntity, Body: `{"status":422,"title":"Duplicate SKU"}`},
+			requests: []string{"GET /v3/catalog/products"},
+			want:     []int{http.StatusUnprocessableEntity},
+			title:    "Duplicate SKU",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := bctest.NewServer()
+			defer s.Close()
+			s.InjectFault(tt.fault)
+			for i, r := range tt.requests {
+				method, path, _ := strings.Cut(r, " ")
+				body := ""
+				if method == http.MethodPost {
+					body = `{"name":"Hat","type":"physical","weight":1,"price":5}`
+				}
+				res := send(t, s, method, "/stores/"+bctest.StoreHash+path, body)
+				if res.status != tt.want[i] {
+					t.Errorf("%s: status %d, want %d", r, res.status, tt.want[i])
+				}
+				if i == 0 && tt.title != "" && res.body["title"] != tt.title {
+					t.Errorf("%s: title %v, want %s", r, res.body["title"], tt.title)
+				}
+			}
+			s.ClearFaults()
+			if res := send(t, s, http.MethodGet, productsPath, ""); res.status != http.StatusOK {
+				t.Errorf("status %d after clearing the faults", res.status)
+			}
+		})
+	}
+}
+
+func TestPagination(t *testing.T) {
+	s := bctest.NewServer()
+	defer s.Close()
+	for i := 1; i <= 7; i++ {
+		s.AddProduct(bigcommerce.Product{Name: fmt.Sprintf("Product %d", i), Type: "physical", Sku: fmt.Sprintf("P%d", i)})
+	}
+	tests := []struct {
+		query      string
+		ids        []int64
+		current    int
+		totalPages int
+		next       bool
+		previous   bool
+	}{
+		{query: "", ids: []int64{1, 2, 3, 4, 5, 6, 7}, current: 1, totalPages: 1},
+		{query: "?limit=3", ids: []int64{1, 2, 3}, current: 1, totalPages: 3, next: true},
+		{query: "?limit=3&page=2", ids: []int64{4, 5, 6}, current: 2, totalPages: 3, next: true, previous: true},
+		{query: "?limit=3&page=3", ids: []int64{7}, current: 3, totalPages: 3, previous: true},
+		{query: "?limit=3&page=4", ids: []int64{}, current: 4, totalPages: 3, previous: true},
+		{query: "?limit=5&page=2&sku:in=P1,P2,P6,P7", ids: []int64{}, current: 2, totalPages: 1, previous: true},
+		{query: "?limit=2&page=2&sku:in=P1,P2,P6,P7", ids: []int64{6, 7}, current: 2, totalPages: 2, previous: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			res := send(t, s, http.MethodGet, "/stores/"+bctest.StoreHash+"/v3/catalog/products"+tt.query, "")
+			if res.status != http.StatusOK {
+				t.Fatalf("status %d", res.status)
+			}
+			ids := []int64{}
+			data, _ := res.body["data"].([]interface{})
+			for _, d := range data {
+				id, _ := d.(map[string]interface{})["id"].(float64)
+				ids = append(ids, int64(id))
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(tt.ids) {
+				t.Errorf("IDs %v, want %v", ids, tt.ids)
+			}
+			meta, _ := res.body["meta"].(map[string]interface{})
+			pagination, _ := meta["pagination"].(map[string]interface{})
+			if pagination["current_page"] != float64(tt.current) || pagination["total_pages"] != float64(tt.totalPages) {
+				t.Errorf("page %v of %v, want %d of %d", pagination["current_page"], pagination["total_pages"], tt.current, tt.totalPages)
+			}
+			links, _ := pagination["links"].(map[string]interface{})
+			if _, ok := links["next"]; ok != tt.next {
+				t.Errorf("next link %v, want %v", links["next"], tt.next)
+			}
+			if _, ok := links["previous"]; ok != tt.previous {
+				t.Errorf("previous link %v, want %v", links["previous"], tt.previous)
+			}
+		})
+	}
+
+	// the client follows the pages to the end
+	for i := 8; i <= 260; i++ {
+		s.AddProduct(bigcommerce.Product{Name: fmt.Sprintf("Product %d", i), Type: "physical", Sku: fmt.Sprintf("P%d", i)})
+	}
+	ps, err := s.Client().GetAllProducts(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ps) != 260 {
+		t.Errorf("got %d products, want 260", len(ps))
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	s := bctest.NewServer()
+	defer s.Close()
+	s.SetRateLimit(2, time.Minute)
+	path := "/stores/" + bctest.StoreHash + "/v3/catalog/products"
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusTooManyRequests} {
+		res := send(t, s, http.MethodGet, path, "")
+		if res.status != want {
+			t.Errorf("request %d: status %d, want %d", i+1, res.status, want)
+		}
+		if res.header.Get("X-Rate-Limit-Requests-Quota") != "2" {
+			t.Errorf("request %d: quota header %q", i+1, res.header.Get("X-Rate-Limit-Requests-Quota"))
+		}
+		if want == http.StatusTooManyRequests && res.header.Get("Retry-After") == "" {
+			t.Errorf("request %d: no Retry-After header", i+1)
+		}
+	}
+	s.SetRateLimit(0, 0)
+	if res := send(t, s, http.MethodGet, path, ""); res.status != http.StatusOK {
+		t.Errorf("status %d without a rate limit", res.status)
+	}
+}
+
+func TestRequests(t *testing.T) {
+	s := bctest.NewServer()
+	defer s.Close()
+	bc := s.Client()
+	if _, err := bc.GetAllProducts(map[string]string{"sku": "SHIRT"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bc.CreateProduct(&bigcommerce.Product{Name: "Shirt", Type: "physical", Sku: "SHIRT", Weight: 1}); err != nil {
+		t.Fatal(err)
+	}
+	reqs := s.Requests()
+	if len(reqs) != 2 {
+		t.Fatalf("got %d requests, want 2", len(reqs))
+	}
+	if reqs[0].Method != http.MethodGet || reqs[0].Path != "/v3/catalog/products" || reqs[0].Query.Get("sku") != "SHIRT" {
+		t.Errorf("first request %s %s?%s", reqs[0].Method, reqs[0].Path, reqs[0].Query.Encode())
+	}
+	if reqs[1].Method != http.MethodPost || !strings.Contains(string(reqs[1].Body), `"sku":"SHIRT"`) {
+		t.Errorf("second request %s %s", reqs[1].Method, reqs[1].Body)
+	}
+	if reqs[1].Header.Get("X-Auth-Token") != bctest.AuthToken {
+		t.Errorf("auth token %q", reqs[1].Header.Get("X-Auth-Token"))
+	}
+}
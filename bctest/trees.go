@@ -0,0 +1,251 @@
+package bctest
+
+import (
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+// defaultTreeID is the tree of the default channel, categories created without tree belong to it
+const defaultTreeID = 1
+
+// treeRoutes registers the category trees API, categories are shared with /v3/catalog/categories.
+// It has to be called before the categories routes.
+func (s *Server) treeRoutes(categories *resource) {
+	trees := &resource{coll: newCollection(), required: []string{"name"}}
+	trees.coll.insertKeepingID(object{"id": float64(defaultTreeID), "name": "Default", "channels": []interface{}{float64(1)}})
+	s.resources["trees"] = trees
+
+	categories.created = func(o object) {
+		if intValue(o["tree_id"]) == 0 {
+			o["tree_id"] = float64(defaultTreeID)
+		}
+	}
+	categories.removed = func(o object) {
+		s.removeSubcategories(categories, idString(o["id"]))
+	}
+	treeCategories := &resource{coll: categories.coll, render: renderTreeCategory}
+
+	s.handle(http.MethodGet, "/v3/catalog/trees/categories", func(c *call) { s.restList(c, treeCategories) })
+	s.handle(http.MethodPost, "/v3/catalog/trees/categories", func(c *call) { s.createTreeCategories(c, categories) })
+	s.handle(http.MethodPut, "/v3/catalog/trees/categories", func(c *call) { s.updateTreeCategories(c, categories) })
+	s.handle(http.MethodDelete, "/v3/catalog/trees/categories", func(c *call) {
+		if ids := c.query.Get("category_id:in"); ids != "" {
+			c.query.Del("category_id:in")
+			c.query.Set("id:in", ids)
+		}
+		s.restBatchDelete(c, categories)
+	})
+	s.handle(http.MethodGet, "/v3/catalog/trees/{tree_id}/categories", func(c *call) { s.getTreeCategories(c, categories) })
+	s.handle(http.MethodGet, "/v3/catalog/trees", func(c *call) { s.listTrees(c, trees) })
+	s.handle(http.MethodPut, "/v3/catalog/trees", func(c *call) { s.upsertTrees(c, trees) })
+	s.handle(http.MethodDelete, "/v3/catalog/trees", func(c *call) {
+		for _, id := range strings.Split(c.query.Get("id:in"), ",") {
+			for _, o := range categories.coll.find(url.Values{"tree_id": {id}}) {
+				categories.coll.remove(idString(o["id"]))
+			}
+		}
+		s.restBatchDelete(c, trees)
+	})
+}
+
+// removeSubcategories deletes the subcategories of a category, like BigCommerce does
+func (s *Server) removeSubcategories(categories *resource, parentID string) {
+	for _, child := range categories.coll.find(url.Values{"parent_id": {parentID}}) {
+		categories.coll.remove(idString(child["id"]))
+		s.removeSubcategories(categories, idString(child["id"]))
+	}
+}
+
+// renderTreeCategory names the ID category_id like the trees API
+func renderTreeCategory(o object, q url.Values) object {
+	ret := copyObject(o)
+	ret["category_id"] = o["id"]
+	delete(ret, "id")
+	return ret
+}
+
+// validateTreeCategory checks the tree and parent of a category in a batch, returning the error message
+func (s *Server) validateTreeCategory(categories *resource, o object) string {
+	if _, ok := s.resources["trees"].coll.get(idString(o["tree_id"])); !ok {
+		return "tree " + idString(o["tree_id"]) + " not found"
+	}
+	if p := intValue(o["parent_id"]); p != 0 {
+		if _, ok := categories.coll.get(idString(o["parent_id"])); !ok {
+			return "parent category " + idString(o["parent_id"]) + " not found"
+		}
+	}
+	return ""
+}
+
+// createTreeCategories handles POST /v3/catalog/trees/categories
+func (s *Server) createTreeCategories(c *call, categories *resource) {
+	var in []object
+	if !c.decode(&in) {
+		return
+	}
+	errs := map[string]string{}
+	for i, o := range in {
+		if idString(o["name"]) == "" {
+			errs[strconv.Itoa(i)+".name"] = "name is a required field"
+		} else if msg := s.validateTreeCategory(categories, o); msg != "" {
+			errs[strconv.Itoa(i)+".tree_id"] = msg
+		}
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	data := []object{}
+	for _, o := range in {
+		data = append(data, renderTreeCategory(s.insert(c, categories, o), c.query))
+	}
+	writeData(c.w, http.StatusOK, data, nil)
+}
+
+// updateTreeCategories handles PUT /v3/catalog/trees/categories, categories are matched by category_id
+func (s *Server) updateTreeCategories(c *call, categories *resource) {
+	var in []object
+	if !c.decode(&in) {
+		return
+	}
+	errs := map[string]string{}
+	for i, o := range in {
+		if _, ok := categories.coll.get(idString(o["category_id"])); !ok {
+			errs[strconv.Itoa(i)+".category_id"] = "category " + idString(o["category_id"]) + " not found"
+		}
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	data := []object{}
+	for _, o := range in {
+		id := idString(o["category_id"])
+		delete(o, "category_id")
+		updated, _ := categories.coll.update(id, o)
+		data = append(data, renderTreeCategory(updated, c.query))
+	}
+	writeData(c.w, http.StatusOK, data, nil)
+}
+
+// getTreeCategories returns the categories of a tree nested by parent
+func (s *Server) getTreeCategories(c *call, categories *resource) {
+	if _, ok := s.resources["trees"].coll.get(c.params["tree_id"]); !ok {
+		writeError(c.w, http.StatusNotFound, "The requested resource was not found", nil)
+		return
+	}
+	items := categories.coll.find(url.Values{"tree_id": {c.params["tree_id"]}})
+	sort.SliceStable(items, func(i, j int) bool {
+		return intValue(items[i]["sort_order"]) < intValue(items[j]["sort_order"])
+	})
+	var nest func(parent string, depth int, path []interface{}) []object
+	nest = func(parent string, depth int, path []interface{}) []object {
+		ret := []object{}
+		for _, o := range items {
+			if idString(o["parent_id"]) != parent {
+				continue
+			}
+			u := ""
+			if cu, ok := o["custom_url"].(map[string]interface{}); ok {
+				u = idString(cu["url"])
+			}
+			ret = append(ret, object{
+				"id":         o["id"],
+				"parent_id":  o["parent_id"],
+				"depth":      depth,
+				"path":       path,
+				"name":       o["name"],
+				"is_visible": o["is_visible"],
+				"url":        u,
+				"children":   nest(idString(o["id"]), depth+1, append(append([]interface{}{}, path...), o["id"])),
+			})
+		}
+		return ret
+	}
+	writeData(c.w, http.StatusOK, nest("0", 1, []interface{}{}), nil)
+}
+
+// listTrees handles GET /v3/catalog/trees, channel_id:in matches any of the channels of a tree
+func (s *Server) listTrees(c *call, trees *resource) {
+	q := url.Values{}
+	for k, v := range c.query {
+		q[k] = v
+	}
+	channels := strings.Split(q.Get("channel_id:in"), ",")
+	q.Del("channel_id:in")
+	data := []object{}
+	for _, o := range trees.coll.find(q) {
+		if len(channels) > 0 && channels[0] != "" && !treeHasChannel(o, channels) {
+			continue
+		}
+		data = append(data, o)
+	}
+	p := paginate(data, q)
+	writeData(c.w, http.StatusOK, p.items, p.meta)
+}
+
+func treeHasChannel(o object, channels []string) bool {
+	list, _ := o["channels"].([]interface{})
+	for _, ch := range list {
+		if contains(channels, idString(ch)) {
+			return true
+		}
+	}
+	return false
+}
+
+// upsertTrees handles PUT /v3/catalog/trees, a channel can only be in one tree
+func (s *Server) upsertTrees(c *call, trees *resource) {
+	var in []object
+	if !c.decode(&in) {
+		return
+	}
+	errs := map[string]string{}
+	for i, t := range in {
+		id := idString(t["id"])
+		if id != "" && id != "0" {
+			if _, ok := trees.coll.get(id); !ok {
+				errs[strconv.Itoa(i)+".id"] = "tree " + id + " not found"
+				continue
+			}
+		}
+		channels := []string{}
+		list, _ := t["channels"].([]interface{})
+		for _, ch := range list {
+			channels = append(channels, idString(ch))
+		}
+		for _, other := range trees.coll.find(url.Values{}) {
+			if idString(other["id"]) != id && treeHasChannel(other, channels) {
+				errs[strconv.Itoa(i)+".channels"] = "a channel is already assigned to tree " + idString(other["id"])
+			}
+		}
+	}
+	if len(errs) > 0 {
+		writeError(c.w, http.StatusUnprocessableEntity, "JSON data is missing or invalid", errs)
+		return
+	}
+	data := []object{}
+	for _, t := range in {
+		id := idString(t["id"])
+		if id == "" || id == "0" {
+			delete(t, "id")
+			data = append(data, trees.coll.insert(t))
+			continue
+		}
+		updated, _ := trees.coll.update(id, t)
+		data = append(data, updated)
+	}
+	writeData(c.w, http.StatusOK, data, nil)
+}
+
+// AddTree adds a category tree to the store
+func (s *Server) AddTree(t bigcommerce.CatalogTree) bigcommerce.CatalogTree {
+	var ret bigcommerce.CatalogTree
+	decodeObject(s.seed("trees", t), &ret)
+	return ret
+}
@@ -2,9 +2,15 @@ package bigcommerce
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	neturl "net/url"
+	"os"
 	"strconv"
+	"strings"
+	"sync"
 )
 
 // Brand is BigCommerce brand object
@@ -88,3 +94,202 @@ func (bc *Client) GetBrands(args map[string]string, page int) ([]Brand, bool, er
 	}
 	return pp.Data, pp.Meta.Pagination.CurrentPage < pp.Meta.Pagination.TotalPages, nil
 }
+
+// GetBrand returns a brand by ID
+func (bc *Client) GetBrand(brandID int64) (*Brand, error) {
+	var ret Brand
+	err := bc.sendJSON(http.MethodGet, fmt.Sprintf("/v3/catalog/brands/%d", brandID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	ret.URL = ret.CustomURL.URL
+	return &ret, nil
+}
+
+// GetBrandByName returns the brand with a name, compared ignoring case, ErrNotFound if there is none.
+// The exact name is looked up first, then every brand containing the name, to find it in another case.
+func (bc *Client) GetBrandByName(name string) (*Brand, error) {
+	name = strings.TrimSpace(name)
+	for _, filter := range []string{"name", "name:like"} {
+		args := map[string]string{filter: neturl.QueryEscape(name), "limit": "250"}
+		more := true
+		for page := 1; more; page++ {
+			var bs []Brand
+			var err error
+			bs, more, err = bc.GetBrands(args, page)
+			if err != nil {
+				return nil, err
+			}
+			for _, b := range bs {
+				if strings.EqualFold(strings.TrimSpace(b.Name), name) {
+					b.URL = b.CustomURL.URL
+					return &b, nil
+				}
+			}
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// brandPayload returns the fields of a brand sent on create and update
+func brandPayload(b *Brand) Patch {
+	p, _ := PatchFrom(b, "name", "page_title", "meta_keywords", "meta_description", "image_url", "search_keywords")
+	if len(b.MetaKeywords) == 0 {
+		delete(p, "meta_keywords")
+	}
+	// the image is removed with DeleteBrandImage
+	if b.ImageURL == "" {
+		delete(p, "image_url")
+	}
+	if b.CustomURL.URL != "" {
+		p["custom_url"] = b.CustomURL
+	}
+	return p
+}
+
+// CreateBrand creates a brand, ImageURL is downloaded by BigCommerce
+func (bc *Client) CreateBrand(brand *Brand) (*Brand, error) {
+	var ret Brand
+	err := bc.sendJSON(http.MethodPost, "/v3/catalog/brands", brandPayload(brand), &ret)
+	if err != nil {
+		return nil, err
+	}
+	ret.URL = ret.CustomURL.URL
+	return &ret, nil
+}
+
+// UpdateBrand updates all the fields of a brand by ID, see PatchBrand to update some fields only
+func (bc *Client) UpdateBrand(brand *Brand) (*Brand, error) {
+	if brand.ID == 0 {
+		return nil, errors.New("brand has no ID")
+	}
+	var ret Brand
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/catalog/brands/%d", brand.ID), brandPayload(brand), &ret)
+	if err != nil {
+		return nil, err
+	}
+	ret.URL = ret.CustomURL.URL
+	return &ret, nil
+}
+
+// DeleteBrand deletes a brand, its products are left without brand
+func (bc *Client) DeleteBrand(brandID int64) error {
+	err := bc.sendJSON(http.MethodDelete, fmt.Sprintf("/v3/catalog/brands/%d", brandID), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// UploadBrandImage uploads the content of r as the image of a brand, returns the new image URL
+func (bc *Client) UploadBrandImage(brandID int64, filename string, r io.Reader) (string, error) {
+	var ret struct {
+		ImageURL string `json:"image_url"`
+	}
+	err := bc.postFile(fmt.Sprintf("/v3/catalog/brands/%d/image", brandID), filename, r, nil, &ret)
+	if err != nil {
+		return "", err
+	}
+	return ret.ImageURL, nil
+}
+
+// UploadBrandImageFile uploads a local file as the image of a brand, returns the new image URL
+func (bc *Client) UploadBrandImageFile(brandID int64, filename string) (string, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return bc.UploadBrandImage(brandID, filename, f)
+}
+
+// SetBrandImageURL sets the image of a brand from a URL, BigCommerce downloads the image
+func (bc *Client) SetBrandImageURL(brandID int64, imageURL string) (*Brand, error) {
+	return bc.PatchBrand(brandID, Patch{"image_url": imageURL})
+}
+
+// DeleteBrandImage removes the image of a brand
+func (bc *Client) DeleteBrandImage(brandID int64) error {
+	err := bc.sendJSON(http.MethodDelete, fmt.Sprintf("/v3/catalog/brands/%d/image", brandID), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// GetBrandMetafields gets metafields values for a brand by key
+func (bc *Client) GetBrandMetafields(brandID int64) (map[string]Metafield, error) {
+	ms, err := bc.GetAllMetafields(BrandMetafieldOwner(brandID), nil)
+	if err != nil {
+		return nil, err
+	}
+	ret := map[string]Metafield{}
+	for _, mf := range ms {
+		ret[mf.Key] = mf
+	}
+	return ret, nil
+}
+
+// brandNameLock is the lock of a brand name, refs counts the calls holding or waiting for it
+type brandNameLock struct {
+	sync.Mutex
+	refs int
+}
+
+// brandNameLocks holds a lock per store and brand name in use, so concurrent GetOrCreateBrand
+// calls for the same name create one brand only
+var brandNameLocks = struct {
+	sync.Mutex
+	locks map[string]*brandNameLock
+}{locks: map[string]*brandNameLock{}}
+
+// lockBrandName locks a brand name of a store and returns the unlock function, the lock is
+// dropped when the last call using it unlocks
+func lockBrandName(storeHash, name string) func() {
+	key := storeHash + "/" + strings.ToLower(name)
+	brandNameLocks.Lock()
+	l, ok := brandNameLocks.locks[key]
+	if !ok {
+		l = &brandNameLock{}
+		brandNameLocks.locks[key] = l
+	}
+	l.refs++
+	brandNameLocks.Unlock()
+	l.Lock()
+	return func() {
+		l.Unlock()
+		brandNameLocks.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(brandNameLocks.locks, key)
+		}
+		brandNameLocks.Unlock()
+	}
+}
+
+// GetOrCreateBrand returns the brand with a name, compared ignoring case, creating it if there is none.
+// It is safe for concurrent use: calls for the same name wait for each other, so the brand is created
+// once. If the brand is created by another process meanwhile, the create fails and the existing brand
+// is returned. Every call looks the brand up, importers should keep the IDs they get.
+func (bc *Client) GetOrCreateBrand(name string) (*Brand, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, errors.New("brand name is empty")
+	}
+	unlock := lockBrandName(bc.StoreHash, name)
+	defer unlock()
+
+	b, err := bc.GetBrandByName(name)
+	if err != ErrNotFound {
+		return b, err
+	}
+	b, err = bc.CreateBrand(&Brand{Name: name})
+	if err != nil {
+		existing, getErr := bc.GetBrandByName(name)
+		if getErr != nil {
+			return nil, err
+		}
+		return existing, nil
+	}
+	return b, nil
+}
@@ -0,0 +1,79 @@
+package bigcommerce
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// BulkPricingRule is a quantity discount of a product.
+// Type is price (amount off each item), percent (percentage off) or fixed (fixed item price).
+type BulkPricingRule struct {
+	ID          int64   `json:"id,omitempty"`
+	QuantityMin int     `json:"quantity_min"`
+	QuantityMax int     `json:"quantity_max"` // 0 for no maximum
+	Type        string  `json:"type"`
+	Amount      float64 `json:"amount"`
+}
+
+// GetBulkPricingRules returns all the bulk pricing rules of a product
+func (bc *Client) GetBulkPricingRules(productID int64) ([]BulkPricingRule, error) {
+	ret := []BulkPricingRule{}
+	page := 1
+	more := true
+	for more {
+		var rs []BulkPricingRule
+		var err error
+		more, err = bc.getPage(fmt.Sprintf("/v3/catalog/products/%d/bulk-pricing-rules?limit=250", productID), page, &rs)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, rs...)
+		page++
+	}
+	return ret, nil
+}
+
+// GetBulkPricingRule gets a bulk pricing rule of a product by ID
+func (bc *Client) GetBulkPricingRule(productID, ruleID int64) (*BulkPricingRule, error) {
+	var ret BulkPricingRule
+	err := bc.sendJSON(http.MethodGet, fmt.Sprintf("/v3/catalog/products/%d/bulk-pricing-rules/%d", productID, ruleID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// CreateBulkPricingRule adds a bulk pricing rule to a product, rule quantity ranges can't overlap
+func (bc *Client) CreateBulkPricingRule(productID int64, rule *BulkPricingRule) (*BulkPricingRule, error) {
+	payload := *rule
+	payload.ID = 0
+	var ret BulkPricingRule
+	err := bc.sendJSON(http.MethodPost, fmt.Sprintf("/v3/catalog/products/%d/bulk-pricing-rules", productID), payload, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// UpdateBulkPricingRule updates a bulk pricing rule of a product by ID
+func (bc *Client) UpdateBulkPricingRule(productID int64, rule *BulkPricingRule) (*BulkPricingRule, error) {
+	if rule.ID == 0 {
+		return nil, errors.New("bulk pricing rule has no ID")
+	}
+	var ret BulkPricingRule
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/catalog/products/%d/bulk-pricing-rules/%d", productID, rule.ID), rule, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// DeleteBulkPricingRule deletes a bulk pricing rule of a product
+func (bc *Client) DeleteBulkPricingRule(productID, ruleID int64) error {
+	err := bc.sendJSON(http.MethodDelete, fmt.Sprintf("/v3/catalog/products/%d/bulk-pricing-rules/%d", productID, ruleID), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
@@ -73,9 +73,14 @@ type CartURLs struct {
 
 // CreateCart creates a new cart in BigCommerce and returns it
 func (bc *Client) CreateCart(items []LineItem) (*Cart, error) {
+	return bc.CreateCartInChannel(bc.ChannelID, items)
+}
+
+// CreateCartInChannel creates a new cart in a channel and returns it
+func (bc *Client) CreateCartInChannel(channelID int, items []LineItem) (*Cart, error) {
 	var body []byte
 	body, _ = json.Marshal(map[string]interface{}{
-		"channel_id": bc.ChannelID,
+		"channel_id": channelID,
 		"line_items": items,
 	})
 	req := bc.getAPIRequest(http.MethodPost, "/v3/carts?include=redirect_urls", bytes.NewReader(body))
@@ -2,10 +2,12 @@ package bigcommerce
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"sort"
 	"strconv"
+	"strings"
 )
 
 // Category is a BC category object
@@ -19,7 +21,18 @@ type Category struct {
 		URL        string `json:"url"`
 		Customized bool   `json:"is_customized"`
 	} `json:"custom_url"`
-	URL string `json:"-"`
+	URL                string   `json:"-"`
+	Description        string   `json:"description,omitempty"`
+	SortOrder          int64    `json:"sort_order,omitempty"`
+	PageTitle          string   `json:"page_title,omitempty"`
+	MetaKeywords       []string `json:"meta_keywords,omitempty"`
+	MetaDescription    string   `json:"meta_description,omitempty"`
+	SearchKeywords     string   `json:"search_keywords,omitempty"`
+	ImageURL           string   `json:"image_url,omitempty"`
+	DefaultProductSort string   `json:"default_product_sort,omitempty"`
+	LayoutFile         string   `json:"layout_file,omitempty"`
+	// TreeID is the category tree of the category, required by CreateCategories
+	TreeID int64 `json:"tree_id,omitempty"`
 }
 
 // GetAllCategories returns a list of categories, handling pagination
@@ -43,23 +56,19 @@ func (bc *Client) GetAllCategories(args map[string]string) ([]Category, error) {
 		cs = append(cs, csp...)
 		page++
 	}
-	cats := map[int64]Category{}
+	// get A > B > C fancy names, sorted by ID
+	tree := NewCategoryTree(cs)
 	ids := []int64{}
 	for _, c := range cs {
-		cats[c.ID] = c
-		//		log.Printf("%d: %s", c.ID, c.Name)
 		ids = append(ids, c.ID)
 	}
-	// sort ids
 	sort.Slice(ids, func(i, j int) bool {
 		return ids[i] < ids[j]
 	})
 	cs = []Category{}
 	for _, i := range ids {
-		c := cats[i]
+		c, _ := tree.Get(i)
 		c.URL = c.CustomURL.URL
-		// get A > B > C fancy name
-		c.FullName = bc.getFullCategoryName(cats, i)
 		cs = append(cs, c)
 	}
 	return cs, err
@@ -100,16 +109,138 @@ func (bc *Client) GetCategories(args map[string]string, page int) ([]Category, b
 	return pp.Data, pp.Meta.Pagination.CurrentPage < pp.Meta.Pagination.TotalPages, nil
 }
 
-func (bc *Client) getFullCategoryName(cats map[int64]Category, i int64) string {
-	c := cats[i]
-	if c.ParentID == 0 {
-		return c.Name
+// GetCategory returns a category by ID
+func (bc *Client) GetCategory(categoryID int64) (*Category, error) {
+	var ret Category
+	err := bc.sendJSON(http.MethodGet, fmt.Sprintf("/v3/catalog/categories/%d", categoryID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	ret.URL = ret.CustomURL.URL
+	return &ret, nil
+}
+
+// categoryPayload returns the fields of a category sent on create and update
+func categoryPayload(c *Category) Patch {
+	b, _ := json.Marshal(c)
+	p := Patch{}
+	json.Unmarshal(b, &p)
+	delete(p, "id")
+	if c.CustomURL.URL == "" {
+		delete(p, "custom_url")
+	}
+	return p
+}
+
+// CreateCategory creates a category, ParentID 0 creates a top level category
+func (bc *Client) CreateCategory(category *Category) (*Category, error) {
+	var ret Category
+	err := bc.sendJSON(http.MethodPost, "/v3/catalog/categories", categoryPayload(category), &ret)
+	if err != nil {
+		return nil, err
+	}
+	ret.URL = ret.CustomURL.URL
+	return &ret, nil
+}
+
+// UpdateCategory updates all the fields of a category by ID, see PatchCategory to update some fields only
+func (bc *Client) UpdateCategory(category *Category) (*Category, error) {
+	if category.ID == 0 {
+		return nil, errors.New("category has no ID")
+	}
+	var ret Category
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/catalog/categories/%d", category.ID), categoryPayload(category), &ret)
+	if err != nil {
+		return nil, err
+	}
+	ret.URL = ret.CustomURL.URL
+	return &ret, nil
+}
+
+// DeleteCategory deletes a category, its subcategories are deleted too
+func (bc *Client) DeleteCategory(categoryID int64) error {
+	err := bc.sendJSON(http.MethodDelete, fmt.Sprintf("/v3/catalog/categories/%d", categoryID), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
 	}
-	if c.FullName != "" {
-		return c.FullName
+	return nil
+}
+
+// categoryBatchSize is the number of categories sent per batch request
+const categoryBatchSize = 50
+
+// CreateCategories creates categories with the batch endpoint of the category trees API,
+// TreeID and Name of every category are required
+func (bc *Client) CreateCategories(categories []Category) ([]Category, error) {
+	payload := []Patch{}
+	for i := range categories {
+		if categories[i].TreeID == 0 {
+			return nil, fmt.Errorf("category %s has no tree ID", categories[i].Name)
+		}
+		payload = append(payload, categoryPayload(&categories[i]))
+	}
+	return bc.categoryBatch(http.MethodPost, payload)
+}
+
+// UpdateCategories updates categories by ID with the batch endpoint of the category trees API
+func (bc *Client) UpdateCategories(categories []Category) ([]Category, error) {
+	payload := []Patch{}
+	for i := range categories {
+		if categories[i].ID == 0 {
+			return nil, fmt.Errorf("category %s has no ID", categories[i].Name)
+		}
+		p := categoryPayload(&categories[i])
+		p["category_id"] = categories[i].ID
+		payload = append(payload, p)
+	}
+	return bc.categoryBatch(http.MethodPut, payload)
+}
+
+// categoryBatch sends the categories in chunks, the trees API names the category ID category_id
+func (bc *Client) categoryBatch(method string, payload []Patch) ([]Category, error) {
+	ret := []Category{}
+	for start := 0; start < len(payload); start += categoryBatchSize {
+		end := start + categoryBatchSize
+		if end > len(payload) {
+			end = len(payload)
+		}
+		var data []Patch
+		err := bc.sendJSON(method, "/v3/catalog/trees/categories", payload[start:end], &data)
+		if err != nil {
+			return ret, err
+		}
+		for _, p := range data {
+			if id, ok := p["category_id"]; ok {
+				p["id"] = id
+			}
+			var c Category
+			b, _ := json.Marshal(p)
+			err = json.Unmarshal(b, &c)
+			if err != nil {
+				return ret, err
+			}
+			c.URL = c.CustomURL.URL
+			ret = append(ret, c)
+		}
+	}
+	return ret, nil
+}
+
+// DeleteCategories deletes categories by ID, with their subcategories
+func (bc *Client) DeleteCategories(categoryIDs []int64) error {
+	for start := 0; start < len(categoryIDs); start += 250 {
+		end := start + 250
+		if end > len(categoryIDs) {
+			end = len(categoryIDs)
+		}
+		ids := []string{}
+		for _, id := range categoryIDs[start:end] {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+		err := bc.sendJSON(http.MethodDelete, "/v3/catalog/trees/categories?category_id:in="+strings.Join(ids, ","), nil, nil)
+		if err != nil && err != ErrNoContent {
+			return err
+		}
 	}
-	pn := bc.getFullCategoryName(cats, cats[i].ParentID)
-	c.FullName = pn + " > " + c.Name
-	cats[i] = c
-	return c.FullName
+	return nil
 }
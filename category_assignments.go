@@ -0,0 +1,181 @@
+package bigcommerce
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// categoryAssignmentBatchSize is the number of assignments sent per request
+const categoryAssignmentBatchSize = 1000
+
+// assignmentDeleteSize is the number of IDs of each list sent per delete request
+const assignmentDeleteSize = 250
+
+// CategoryAssignment assigns a product to a category
+type CategoryAssignment struct {
+	ProductID  int64 `json:"product_id"`
+	CategoryID int64 `json:"category_id"`
+}
+
+// ProductSortOrder is the position of a product in a category
+type ProductSortOrder struct {
+	ProductID int64 `json:"product_id"`
+	SortOrder int64 `json:"sort_order"`
+}
+
+// joinIDs returns the IDs as a comma separated list
+func joinIDs(ids []int64) string {
+	s := []string{}
+	for _, id := range ids {
+		s = append(s, strconv.FormatInt(id, 10))
+	}
+	return strings.Join(s, ",")
+}
+
+// assignmentFilter returns the query of the assignments of the products and of the categories
+// or channels named by key, empty lists match all
+func assignmentFilter(productIDs []int64, key string, ids []int64) string {
+	q := []string{}
+	if len(productIDs) > 0 {
+		q = append(q, "product_id:in="+joinIDs(productIDs))
+	}
+	if len(ids) > 0 {
+		q = append(q, key+":in="+joinIDs(ids))
+	}
+	return strings.Join(q, "&")
+}
+
+// GetCategoryAssignments returns the category assignments of the products and categories,
+// an empty list of IDs matches all
+func (bc *Client) GetCategoryAssignments(productIDs, categoryIDs []int64) ([]CategoryAssignment, error) {
+	path := "/v3/catalog/products/category-assignments?limit=250"
+	if filter := assignmentFilter(productIDs, "category_id", categoryIDs); filter != "" {
+		path += "&" + filter
+	}
+	ret := []CategoryAssignment{}
+	page := 1
+	more := true
+	for more {
+		var as []CategoryAssignment
+		var err error
+		more, err = bc.getPage(path, page, &as)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, as...)
+		page++
+	}
+	return ret, nil
+}
+
+// CreateCategoryAssignments assigns products to categories, existing assignments are kept
+func (bc *Client) CreateCategoryAssignments(assignments []CategoryAssignment) error {
+	for start := 0; start < len(assignments); start += categoryAssignmentBatchSize {
+		end := start + categoryAssignmentBatchSize
+		if end > len(assignments) {
+			end = len(assignments)
+		}
+		err := bc.sendJSON(http.MethodPut, "/v3/catalog/products/category-assignments", assignments[start:end], nil)
+		if err != nil && err != ErrNoContent {
+			return err
+		}
+	}
+	return nil
+}
+
+// DeleteCategoryAssignments removes the products from the categories: all the categories of the
+// products if categoryIDs is empty, all the products of the categories if productIDs is empty.
+// IDs are sent 250 at a time.
+func (bc *Client) DeleteCategoryAssignments(productIDs, categoryIDs []int64) error {
+	if len(productIDs) == 0 && len(categoryIDs) == 0 {
+		return errors.New("no products or categories to unassign")
+	}
+	return bc.deleteAssignments("/v3/catalog/products/category-assignments", productIDs, "category_id", categoryIDs)
+}
+
+// deleteAssignments deletes the assignments of the products and of the IDs named by key, with at
+// most 250 IDs per list in a request
+func (bc *Client) deleteAssignments(path string, productIDs []int64, key string, ids []int64) error {
+	for _, ps := range chunkIDs(productIDs, assignmentDeleteSize) {
+		for _, cs := range chunkIDs(ids, assignmentDeleteSize) {
+			err := bc.sendJSON(http.MethodDelete, path+"?"+assignmentFilter(ps, key, cs), nil, nil)
+			if err != nil && err != ErrNoContent {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// chunkIDs splits IDs in lists of size, an empty list gives one empty list
+func chunkIDs(ids []int64, size int) [][]int64 {
+	if len(ids) == 0 {
+		return [][]int64{nil}
+	}
+	ret := [][]int64{}
+	for start := 0; start < len(ids); start += size {
+		end := start + size
+		if end > len(ids) {
+			end = len(ids)
+		}
+		ret = append(ret, ids[start:end])
+	}
+	return ret
+}
+
+// GetCategorySortOrder returns the sort order of the products of a category
+func (bc *Client) GetCategorySortOrder(categoryID int64) ([]ProductSortOrder, error) {
+	path := fmt.Sprintf("/v3/catalog/categories/%d/products/sort-order?limit=250", categoryID)
+	ret := []ProductSortOrder{}
+	page := 1
+	more := true
+	for more {
+		var ps []ProductSortOrder
+		var err error
+		more, err = bc.getPage(path, page, &ps)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, ps...)
+		page++
+	}
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].SortOrder < ret[j].SortOrder })
+	return ret, nil
+}
+
+// SetCategorySortOrder sets the sort order of products in a category, other products keep theirs
+func (bc *Client) SetCategorySortOrder(categoryID int64, orders []ProductSortOrder) error {
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/catalog/categories/%d/products/sort-order", categoryID), orders, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// SortCategoryProducts puts the products first in a category, in the given order. The other
+// products of the category follow in their current order.
+func (bc *Client) SortCategoryProducts(categoryID int64, productIDs []int64) error {
+	current, err := bc.GetCategorySortOrder(categoryID)
+	if err != nil {
+		return err
+	}
+	orders := []ProductSortOrder{}
+	placed := map[int64]bool{}
+	for _, id := range productIDs {
+		if !placed[id] {
+			placed[id] = true
+			orders = append(orders, ProductSortOrder{ProductID: id, SortOrder: int64(len(orders))})
+		}
+	}
+	for _, o := range current {
+		if !placed[o.ProductID] {
+			placed[o.ProductID] = true
+			orders = append(orders, ProductSortOrder{ProductID: o.ProductID, SortOrder: int64(len(orders))})
+		}
+	}
+	return bc.SetCategorySortOrder(categoryID, orders)
+}
@@ -0,0 +1,60 @@
+package bigcommerce_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/mvalenziano/bigcommerce-api-go/bctest"
+)
+
+func TestDeleteCategoryAssignmentsChunksIDs(t *testing.T) {
+	tests := []struct {
+		name        string
+		products    int
+		categories  int
+		wantDeletes int
+	}{
+		{"products only", 600, 0, 3},
+		{"categories only", 0, 251, 2},
+		{"products and categories", 251, 251, 4},
+		{"within the limit", 250, 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := bctest.NewServer()
+			productIDs := make([]int64, tt.products)
+			for i := range productIDs {
+				productIDs[i] = int64(i + 1)
+			}
+			categoryIDs := make([]int64, tt.categories)
+			for i := range categoryIDs {
+				categoryIDs[i] = int64(i + 1)
+			}
+			if err := s.Client().DeleteCategoryAssignments(productIDs, categoryIDs); err != nil {
+				t.Fatal(err)
+			}
+			deletes := 0
+			for _, r := range s.Requests() {
+				if r.Method != http.MethodDelete {
+					continue
+				}
+				deletes++
+				for _, key := range []string{"product_id:in", "category_id:in"} {
+					if n := len(strings.Split(r.Query.Get(key), ",")); n > 250 {
+						t.Errorf("%s has %d IDs", key, n)
+					}
+				}
+			}
+			if deletes != tt.wantDeletes {
+				t.Errorf("%d delete requests, want %d", deletes, tt.wantDeletes)
+			}
+		})
+	}
+}
+
+func TestDeleteCategoryAssignmentsNeedsIDs(t *testing.T) {
+	if err := bctest.NewServer().Client().DeleteCategoryAssignments(nil, nil); err == nil {
+		t.Error("no error deleting the assignments of no products and no categories")
+	}
+}
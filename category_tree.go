@@ -0,0 +1,204 @@
+package bigcommerce
+
+import (
+	"sort"
+	"strings"
+)
+
+// CategoryTree is an in-memory tree of categories for navigation, see NewCategoryTree.
+// Categories are returned as copies, with FullName set to their path like "Men > Shoes > Running".
+type CategoryTree struct {
+	byID     map[int64]*Category
+	parent   map[int64]int64
+	children map[int64][]int64
+	detached []int64
+}
+
+// NewCategoryTree builds a tree from a flat list of categories. Categories whose parent is missing,
+// and one category of every parent cycle, are placed at the top level and listed by Detached.
+func NewCategoryTree(categories []Category) *CategoryTree {
+	t := &CategoryTree{
+		byID:     map[int64]*Category{},
+		parent:   map[int64]int64{},
+		children: map[int64][]int64{},
+	}
+	ids := []int64{}
+	for _, c := range categories {
+		c := c
+		if _, ok := t.byID[c.ID]; !ok {
+			ids = append(ids, c.ID)
+		}
+		t.byID[c.ID] = &c
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		p := t.byID[id].ParentID
+		if _, ok := t.byID[p]; p != 0 && (!ok || p == id) {
+			t.detached = append(t.detached, id)
+			p = 0
+		}
+		t.parent[id] = p
+	}
+	// walk up from every category, a category seen twice on the way up is in a cycle:
+	// the cycle is broken at its lowest ID
+	for _, id := range ids {
+		seen := map[int64]int{}
+		path := []int64{}
+		cur := id
+		for cur != 0 {
+			if _, ok := seen[cur]; ok {
+				break
+			}
+			seen[cur] = len(path)
+			path = append(path, cur)
+			cur = t.parent[cur]
+		}
+		if cur == 0 {
+			continue
+		}
+		lowest := cur
+		for _, c := range path[seen[cur]:] {
+			if c < lowest {
+				lowest = c
+			}
+		}
+		t.parent[lowest] = 0
+		t.detached = append(t.detached, lowest)
+	}
+	sort.Slice(t.detached, func(i, j int) bool { return t.detached[i] < t.detached[j] })
+
+	for _, id := range ids {
+		t.children[t.parent[id]] = append(t.children[t.parent[id]], id)
+	}
+	for _, kids := range t.children {
+		sort.SliceStable(kids, func(i, j int) bool {
+			return t.byID[kids[i]].SortOrder < t.byID[kids[j]].SortOrder
+		})
+	}
+	for _, id := range ids {
+		names := []string{}
+		for _, c := range t.Breadcrumbs(id) {
+			names = append(names, c.Name)
+		}
+		t.byID[id].FullName = strings.Join(names, " > ")
+	}
+	return t
+}
+
+// list returns copies of the categories with the given IDs
+func (t *CategoryTree) list(ids []int64) []Category {
+	ret := []Category{}
+	for _, id := range ids {
+		ret = append(ret, *t.byID[id])
+	}
+	return ret
+}
+
+// Get returns a category by ID
+func (t *CategoryTree) Get(categoryID int64) (Category, bool) {
+	c, ok := t.byID[categoryID]
+	if !ok {
+		return Category{}, false
+	}
+	return *c, true
+}
+
+// Roots returns the top level categories, by sort order
+func (t *CategoryTree) Roots() []Category {
+	return t.list(t.children[0])
+}
+
+// Children returns the subcategories of a category, by sort order
+func (t *CategoryTree) Children(categoryID int64) []Category {
+	if categoryID == 0 {
+		return []Category{}
+	}
+	return t.list(t.children[categoryID])
+}
+
+// Parent returns the parent of a category, false for top level and unknown categories
+func (t *CategoryTree) Parent(categoryID int64) (Category, bool) {
+	return t.Get(t.parent[categoryID])
+}
+
+// Ancestors returns the parents of a category, top level category first
+func (t *CategoryTree) Ancestors(categoryID int64) []Category {
+	ids := []int64{}
+	if _, ok := t.byID[categoryID]; ok {
+		for p := t.parent[categoryID]; p != 0; p = t.parent[p] {
+			ids = append([]int64{p}, ids...)
+		}
+	}
+	return t.list(ids)
+}
+
+// Breadcrumbs returns the ancestors of a category followed by the category itself
+func (t *CategoryTree) Breadcrumbs(categoryID int64) []Category {
+	c, ok := t.Get(categoryID)
+	if !ok {
+		return []Category{}
+	}
+	return append(t.Ancestors(categoryID), c)
+}
+
+// Depth returns the depth of a category, 0 for top level categories
+func (t *CategoryTree) Depth(categoryID int64) int {
+	return len(t.Ancestors(categoryID))
+}
+
+// Descendants returns all the subcategories of a category, depth first
+func (t *CategoryTree) Descendants(categoryID int64) []Category {
+	ret := []Category{}
+	if categoryID == 0 {
+		return ret
+	}
+	var walk func(id int64)
+	walk = func(id int64) {
+		for _, child := range t.children[id] {
+			ret = append(ret, *t.byID[child])
+			walk(child)
+		}
+	}
+	walk(categoryID)
+	return ret
+}
+
+// Walk calls fn for every category depth first, with its depth
+func (t *CategoryTree) Walk(fn func(c Category, depth int)) {
+	var walk func(id int64, depth int)
+	walk = func(id int64, depth int) {
+		for _, child := range t.children[id] {
+			fn(*t.byID[child], depth)
+			walk(child, depth+1)
+		}
+	}
+	walk(0, 0)
+}
+
+// Find returns the category at a path of names like "Men > Shoes > Running", names are matched
+// ignoring case and surrounding spaces
+func (t *CategoryTree) Find(path string) (Category, bool) {
+	var parent int64
+	found := false
+	for _, name := range strings.Split(path, ">") {
+		name = strings.TrimSpace(name)
+		found = false
+		for _, id := range t.children[parent] {
+			if strings.EqualFold(strings.TrimSpace(t.byID[id].Name), name) {
+				parent, found = id, true
+				break
+			}
+		}
+		if !found {
+			return Category{}, false
+		}
+	}
+	return t.Get(parent)
+}
+
+// Detached returns the IDs of the categories placed at the top level because their parent is
+// missing or they were part of a parent cycle
+func (t *CategoryTree) Detached() []int64 {
+	return append([]int64{}, t.detached...)
+}
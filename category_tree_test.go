@@ -0,0 +1,111 @@
+package bigcommerce_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+	"github.com/mvalenziano/bigcommerce-api-go/bctest"
+)
+
+func TestCategoryTreeDetached(t *testing.T) {
+	tests := []struct {
+		name      string
+		parents   map[int64]int64 // parent ID by category ID, categories are named C<ID>
+		detached  []int64
+		roots     []int64
+		fullNames map[int64]string
+		depthOf   int64
+		wantDepth int
+	}{
+		{
+			name:      "plain tree",
+			parents:   map[int64]int64{1: 0, 2: 1, 3: 2},
+			detached:  []int64{},
+			roots:     []int64{1},
+			fullNames: map[int64]string{3: "C1 > C2 > C3"},
+			depthOf:   3,
+			wantDepth: 2,
+		},
+		{
+			name:      "missing parent",
+			parents:   map[int64]int64{1: 0, 2: 1, 3: 99, 4: 3},
+			detached:  []int64{3},
+			roots:     []int64{1, 3},
+			fullNames: map[int64]string{2: "C1 > C2", 4: "C3 > C4"},
+			depthOf:   4,
+			wantDepth: 1,
+		},
+		{
+			name:      "own parent",
+			parents:   map[int64]int64{1: 1, 2: 1},
+			detached:  []int64{1},
+			roots:     []int64{1},
+			fullNames: map[int64]string{2: "C1 > C2"},
+			depthOf:   2,
+			wantDepth: 1,
+		},
+		{
+			name:      "cycle broken at lowest ID",
+			parents:   map[int64]int64{1: 3, 2: 1, 3: 2},
+			detached:  []int64{1},
+			roots:     []int64{1},
+			fullNames: map[int64]string{3: "C1 > C2 > C3"},
+			depthOf:   3,
+			wantDepth: 2,
+		},
+		{
+			name:      "cycle under a root",
+			parents:   map[int64]int64{1: 0, 2: 3, 3: 2, 4: 3, 5: 1},
+			detached:  []int64{2},
+			roots:     []int64{1, 2},
+			fullNames: map[int64]string{4: "C2 > C3 > C4", 5: "C1 > C5"},
+			depthOf:   4,
+			wantDepth: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := bctest.NewServer()
+			for id, parent := range tt.parents {
+				s.AddCategory(bigcommerce.Category{ID: id, ParentID: parent, Name: fmt.Sprintf("C%d", id), Visible: true})
+			}
+			categories, err := s.Client().GetAllCategories(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(categories) != len(tt.parents) {
+				t.Fatalf("got %d categories, want %d", len(categories), len(tt.parents))
+			}
+			for _, c := range categories {
+				if want, ok := tt.fullNames[c.ID]; ok && c.FullName != want {
+					t.Errorf("full name of %d is %q, want %q", c.ID, c.FullName, want)
+				}
+			}
+
+			tree := bigcommerce.NewCategoryTree(categories)
+			if got := tree.Detached(); !reflect.DeepEqual(got, tt.detached) {
+				t.Errorf("detached %v, want %v", got, tt.detached)
+			}
+			roots := []int64{}
+			for _, c := range tree.Roots() {
+				roots = append(roots, c.ID)
+			}
+			if !reflect.DeepEqual(roots, tt.roots) {
+				t.Errorf("roots %v, want %v", roots, tt.roots)
+			}
+			if got := tree.Depth(tt.depthOf); got != tt.wantDepth {
+				t.Errorf("depth of %d is %d, want %d", tt.depthOf, got, tt.wantDepth)
+			}
+			// every category is reached once by a walk from the roots
+			walked := map[int64]int{}
+			tree.Walk(func(c bigcommerce.Category, depth int) { walked[c.ID]++ })
+			for id := range tt.parents {
+				if walked[id] != 1 {
+					t.Errorf("category %d walked %d times", id, walked[id])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package bigcommerce
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// channelAssignmentBatchSize is the number of assignments sent per request
+const channelAssignmentBatchSize = 1000
+
+// ChannelAssignment assigns a product to a channel
+type ChannelAssignment struct {
+	ProductID int64 `json:"product_id,omitempty"`
+	ChannelID int64 `json:"channel_id,omitempty"`
+}
+
+// ChannelReconcileResult lists the channels changed by ReconcileProductChannels
+type ChannelReconcileResult struct {
+	Added     []int64
+	Removed   []int64
+	Unchanged []int64
+}
+
+// GetChannelAssignments returns the channel assignments of the products and channels,
+// an empty list of IDs matches all
+func (bc *Client) GetChannelAssignments(productIDs, channelIDs []int64) ([]ChannelAssignment, error) {
+	path := "/v3/catalog/products/channel-assignments?limit=250"
+	if filter := assignmentFilter(productIDs, "channel_id", channelIDs); filter != "" {
+		path += "&" + filter
+	}
+	ret := []ChannelAssignment{}
+	page := 1
+	more := true
+	for more {
+		var as []ChannelAssignment
+		var err error
+		more, err = bc.getPage(path, page, &as)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, as...)
+		page++
+	}
+	return ret, nil
+}
+
+// CreateChannelAssignments assigns products to channels in batches of 1000, existing assignments
+// are kept. It returns the errors of the assignments that were not saved by position in
+// assignments, and an error if any assignment failed. The API rejects a whole batch when one of
+// its assignments is invalid, like one of a deleted product.
+func (bc *Client) CreateChannelAssignments(assignments []ChannelAssignment) (map[int]error, error) {
+	failed := map[int]error{}
+	for start := 0; start < len(assignments); start += channelAssignmentBatchSize {
+		end := start + channelAssignmentBatchSize
+		if end > len(assignments) {
+			end = len(assignments)
+		}
+		itemErrs, err := bc.sendBatch(http.MethodPut, "/v3/catalog/products/channel-assignments", assignments[start:end], end-start, nil)
+		for i := start; i < end; i++ {
+			if e := itemErrs[i-start]; e != nil {
+				failed[i] = e
+			} else if err != nil {
+				failed[i] = err
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return failed, fmt.Errorf("%d of %d channel assignments failed", len(failed), len(assignments))
+	}
+	return failed, nil
+}
+
+// AssignProductsToChannels assigns every product to every channel
+func (bc *Client) AssignProductsToChannels(productIDs, channelIDs []int64) error {
+	assignments := []ChannelAssignment{}
+	for _, p := range productIDs {
+		for _, c := range channelIDs {
+			assignments = append(assignments, ChannelAssignment{ProductID: p, ChannelID: c})
+		}
+	}
+	_, err := bc.CreateChannelAssignments(assignments)
+	return err
+}
+
+// DeleteChannelAssignments removes the products from the channels: all the channels of the
+// products if channelIDs is empty, all the products of the channels if productIDs is empty.
+// IDs are sent 250 at a time.
+func (bc *Client) DeleteChannelAssignments(productIDs, channelIDs []int64) error {
+	if len(productIDs) == 0 && len(channelIDs) == 0 {
+		return errors.New("no products or channels to unassign")
+	}
+	return bc.deleteAssignments("/v3/catalog/products/channel-assignments", productIDs, "channel_id", channelIDs)
+}
+
+// ReconcileProductChannels makes the channels of a product match channelIDs,
+// assigning the missing channels and removing the others
+func (bc *Client) ReconcileProductChannels(productID int64, channelIDs []int64) (*ChannelReconcileResult, error) {
+	current, err := bc.GetChannelAssignments([]int64{productID}, nil)
+	if err != nil {
+		return nil, err
+	}
+	want := map[int64]bool{}
+	for _, id := range channelIDs {
+		want[id] = true
+	}
+	has := map[int64]bool{}
+	ret := &ChannelReconcileResult{}
+	for _, a := range current {
+		has[a.ChannelID] = true
+		if want[a.ChannelID] {
+			ret.Unchanged = append(ret.Unchanged, a.ChannelID)
+		} else {
+			ret.Removed = append(ret.Removed, a.ChannelID)
+		}
+	}
+	for id := range want {
+		if !has[id] {
+			ret.Added = append(ret.Added, id)
+		}
+	}
+	for _, ids := range [][]int64{ret.Added, ret.Removed, ret.Unchanged} {
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	}
+	if len(ret.Added) > 0 {
+		err = bc.AssignProductsToChannels([]int64{productID}, ret.Added)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if len(ret.Removed) > 0 {
+		err = bc.DeleteChannelAssignments([]int64{productID}, ret.Removed)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
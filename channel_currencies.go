@@ -0,0 +1,71 @@
+package bigcommerce
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// ChannelCurrencies are the currencies enabled on a channel, by currency code. DefaultCurrency
+// must be one of EnabledCurrencies.
+type ChannelCurrencies struct {
+	ChannelID         int64    `json:"channel_id"`
+	EnabledCurrencies []string `json:"enabled_currencies"`
+	DefaultCurrency   string   `json:"default_currency"`
+}
+
+// GetAllChannelCurrencies returns the currency assignments of all the channels that have one
+func (bc *Client) GetAllChannelCurrencies() ([]ChannelCurrencies, error) {
+	var ret []ChannelCurrencies
+	err := bc.sendJSON(http.MethodGet, "/v3/channels/currency-assignments", nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// GetChannelCurrencies returns the currency assignments of a channel
+func (bc *Client) GetChannelCurrencies(channelID int64) (*ChannelCurrencies, error) {
+	var ret ChannelCurrencies
+	err := bc.sendJSON(http.MethodGet, fmt.Sprintf("/v3/channels/%d/currency-assignments", channelID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// CreateChannelCurrencies assigns currencies to a channel that has none
+func (bc *Client) CreateChannelCurrencies(currencies *ChannelCurrencies) (*ChannelCurrencies, error) {
+	return bc.sendChannelCurrencies(http.MethodPost, currencies)
+}
+
+// UpdateChannelCurrencies replaces the currency assignments of a channel
+func (bc *Client) UpdateChannelCurrencies(currencies *ChannelCurrencies) (*ChannelCurrencies, error) {
+	return bc.sendChannelCurrencies(http.MethodPut, currencies)
+}
+
+func (bc *Client) sendChannelCurrencies(method string, currencies *ChannelCurrencies) (*ChannelCurrencies, error) {
+	if currencies.ChannelID == 0 {
+		return nil, errors.New("currency assignments have no channel ID")
+	}
+	payload := struct {
+		EnabledCurrencies []string `json:"enabled_currencies"`
+		DefaultCurrency   string   `json:"default_currency"`
+	}{currencies.EnabledCurrencies, currencies.DefaultCurrency}
+	var ret ChannelCurrencies
+	err := bc.sendJSON(method, fmt.Sprintf("/v3/channels/%d/currency-assignments", currencies.ChannelID), payload, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// DeleteChannelCurrencies removes the currency assignments of a channel, it falls back to the
+// currencies of the store
+func (bc *Client) DeleteChannelCurrencies(channelID int64) error {
+	err := bc.sendJSON(http.MethodDelete, fmt.Sprintf("/v3/channels/%d/currency-assignments", channelID), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+package bigcommerce
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// channelListingPageSize is the number of listings requested per page
+const channelListingPageSize = 250
+
+// channelListingBatchSize is the number of listings sent per batch request
+const channelListingBatchSize = 50
+
+// ChannelListing overrides the name and description of a product on a channel, empty values
+// fall back to the product. Prices per channel are set with price lists. The dates are
+// read-only and not sent.
+type ChannelListing struct {
+	ListingID    int64                   `json:"listing_id,omitempty"`
+	ChannelID    int64                   `json:"channel_id,omitempty"`
+	ProductID    int64                   `json:"product_id"`
+	ExternalID   string                  `json:"external_id,omitempty"`
+	State        string                  `json:"state"`
+	Name         string                  `json:"name,omitempty"`
+	Description  string                  `json:"description,omitempty"`
+	DateCreated  time.Time               `json:"date_created,omitempty"`
+	DateModified time.Time               `json:"date_modified,omitempty"`
+	Variants     []ChannelVariantListing `json:"variants"`
+}
+
+// ChannelVariantListing is the listing of a variant in a channel listing
+type ChannelVariantListing struct {
+	ProductID    int64     `json:"product_id"`
+	VariantID    int64     `json:"variant_id"`
+	ExternalID   string    `json:"external_id,omitempty"`
+	State        string    `json:"state"`
+	Name         string    `json:"name,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	DateCreated  time.Time `json:"date_created,omitempty"`
+	DateModified time.Time `json:"date_modified,omitempty"`
+}
+
+// channelListingPayload is a listing as sent on create and update, without the read-only fields
+type channelListingPayload struct {
+	ListingID   int64                          `json:"listing_id,omitempty"`
+	ProductID   int64                          `json:"product_id"`
+	ExternalID  string                         `json:"external_id,omitempty"`
+	State       string                         `json:"state"`
+	Name        string                         `json:"name,omitempty"`
+	Description string                         `json:"description,omitempty"`
+	Variants    []channelVariantListingPayload `json:"variants"`
+}
+
+type channelVariantListingPayload struct {
+	ProductID   int64  `json:"product_id"`
+	VariantID   int64  `json:"variant_id"`
+	ExternalID  string `json:"external_id,omitempty"`
+	State       string `json:"state"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// newChannelListingPayload returns the payload of a listing, variants is an empty list and not
+// null when the listing has no variants
+func newChannelListingPayload(l ChannelListing) channelListingPayload {
+	p := channelListingPayload{
+		ListingID:   l.ListingID,
+		ProductID:   l.ProductID,
+		ExternalID:  l.ExternalID,
+		State:       l.State,
+		Name:        l.Name,
+		Description: l.Description,
+		Variants:    []channelVariantListingPayload{},
+	}
+	for _, v := range l.Variants {
+		p.Variants = append(p.Variants, channelVariantListingPayload{
+			ProductID:   v.ProductID,
+			VariantID:   v.VariantID,
+			ExternalID:  v.ExternalID,
+			State:       v.State,
+			Name:        v.Name,
+			Description: v.Description,
+		})
+	}
+	return p
+}
+
+// ListingDifference is a field of a listing that overrides the product, VariantID is 0 for the product listing
+type ListingDifference struct {
+	VariantID int64
+	Field     string
+	Product   string
+	Listing   string
+}
+
+// ListingDiff is the result of DiffChannelListing
+type ListingDiff struct {
+	Differences []ListingDifference
+	// MissingVariants are variants of the product without variant listing
+	MissingVariants []int64
+	// UnknownVariants are variant listings of variants that are not in the product
+	UnknownVariants []int64
+}
+
+// Empty returns whether the listing matches the product
+func (d ListingDiff) Empty() bool {
+	return len(d.Differences) == 0 && len(d.MissingVariants) == 0 && len(d.UnknownVariants) == 0
+}
+
+// DiffChannelListing compares a listing to its product: names and descriptions set on the listing
+// that differ from the product, and variants listed on one side only. Variants are compared only
+// when the product was fetched with its variants.
+func DiffChannelListing(product *Product, listing *ChannelListing) ListingDiff {
+	ret := ListingDiff{Differences: []ListingDifference{}}
+	compare := func(variantID int64, name, description string) {
+		if name != "" && name != product.Name {
+			ret.Differences = append(ret.Differences, ListingDifference{variantID, "name", product.Name, name})
+		}
+		if description != "" && description != product.Description {
+			ret.Differences = append(ret.Differences, ListingDifference{variantID, "description", product.Description, description})
+		}
+	}
+	compare(0, listing.Name, listing.Description)
+	for _, v := range listing.Variants {
+		compare(v.VariantID, v.Name, v.Description)
+	}
+	if len(product.Variants) == 0 {
+		return ret
+	}
+	listed := map[int64]bool{}
+	for _, v := range listing.Variants {
+		listed[v.VariantID] = true
+	}
+	known := map[int64]bool{}
+	for _, v := range product.Variants {
+		known[v.ID] = true
+		if !listed[v.ID] {
+			ret.MissingVariants = append(ret.MissingVariants, v.ID)
+		}
+	}
+	for _, v := range listing.Variants {
+		if !known[v.VariantID] {
+			ret.UnknownVariants = append(ret.UnknownVariants, v.VariantID)
+		}
+	}
+	sort.Slice(ret.MissingVariants, func(i, j int) bool { return ret.MissingVariants[i] < ret.MissingVariants[j] })
+	sort.Slice(ret.UnknownVariants, func(i, j int) bool { return ret.UnknownVariants[i] < ret.UnknownVariants[j] })
+	return ret
+}
+
+// GetChannelListings returns the listings of a channel, only those of the given products if any.
+// The listings API pages with the ID of the last listing instead of page numbers.
+func (bc *Client) GetChannelListings(channelID int64, productIDs []int64) ([]ChannelListing, error) {
+	path := fmt.Sprintf("/v3/channels/%d/listings?limit=%d", channelID, channelListingPageSize)
+	if len(productIDs) > 0 {
+		path += "&product_id:in=" + joinIDs(productIDs)
+	}
+	ret := []ChannelListing{}
+	var after int64
+	for {
+		p := path
+		if after > 0 {
+			p += "&after=" + strconv.FormatInt(after, 10)
+		}
+		var ls []ChannelListing
+		err := bc.sendJSON(http.MethodGet, p, nil, &ls)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, ls...)
+		if len(ls) < channelListingPageSize {
+			return ret, nil
+		}
+		after = ls[len(ls)-1].ListingID
+	}
+}
+
+// GetChannelListing returns a listing of a channel by listing ID
+func (bc *Client) GetChannelListing(channelID, listingID int64) (*ChannelListing, error) {
+	var ret ChannelListing
+	err := bc.sendJSON(http.MethodGet, fmt.Sprintf("/v3/channels/%d/listings/%d", channelID, listingID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// CreateChannelListings creates listings in a channel, ProductID and State are required
+func (bc *Client) CreateChannelListings(channelID int64, listings []ChannelListing) ([]ChannelListing, error) {
+	for _, l := range listings {
+		if l.ProductID == 0 {
+			return nil, fmt.Errorf("listing %s has no product ID", l.Name)
+		}
+	}
+	return bc.channelListingBatch(http.MethodPost, channelID, listings)
+}
+
+// UpdateChannelListings updates listings of a channel by listing ID, the variants of a listing
+// are replaced by those sent
+func (bc *Client) UpdateChannelListings(channelID int64, listings []ChannelListing) ([]ChannelListing, error) {
+	for _, l := range listings {
+		if l.ListingID == 0 {
+			return nil, fmt.Errorf("listing of product %d has no listing ID", l.ProductID)
+		}
+	}
+	return bc.channelListingBatch(http.MethodPut, channelID, listings)
+}
+
+// channelListingBatch sends the listings in chunks
+func (bc *Client) channelListingBatch(method string, channelID int64, listings []ChannelListing) ([]ChannelListing, error) {
+	ret := []ChannelListing{}
+	for start := 0; start < len(listings); start += channelListingBatchSize {
+		end := start + channelListingBatchSize
+		if end > len(listings) {
+			end = len(listings)
+		}
+		payload := []channelListingPayload{}
+		for _, l := range listings[start:end] {
+			payload = append(payload, newChannelListingPayload(l))
+		}
+		var data []ChannelListing
+		err := bc.sendJSON(method, fmt.Sprintf("/v3/channels/%d/listings", channelID), payload, &data)
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, data...)
+	}
+	return ret, nil
+}
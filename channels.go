@@ -3,6 +3,7 @@ package bigcommerce
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io/ioutil"
 	"log"
 	"net/http"
@@ -21,8 +22,23 @@ type Channel struct {
 	IsEnabled        bool      `json:"is_enabled"`
 	DateModified     time.Time `json:"date_modified"`
 	Name             string    `json:"name"`
-	ID               int       `json:"id"`
+	ID               int64     `json:"id"`
 	Status           string    `json:"status"`
+	// ConfigMeta holds the app sections shown for the channel in the control panel
+	ConfigMeta map[string]interface{} `json:"config_meta,omitempty"`
+}
+
+// ChannelMenus are the sections of the control panel menu of a channel
+type ChannelMenus struct {
+	// BigCommerceProtectedAppSections are built-in sections like "storefront_settings" or "domains"
+	BigCommerceProtectedAppSections []string             `json:"bigcommerce_protected_app_sections"`
+	CustomAppSections               []ChannelMenuSection `json:"custom_app_sections"`
+}
+
+// ChannelMenuSection is a section of the channel menu served by the app, QueryPath is added to the app URL
+type ChannelMenuSection struct {
+	Title     string `json:"title"`
+	QueryPath string `json:"query_path"`
 }
 
 func (bc *Client) GetAllChannels() ([]Channel, error) {
@@ -82,3 +98,80 @@ func (bc *Client) GetChannels(page int) ([]Channel, bool, error) {
 	}
 	return pp.Data, pp.Meta.Pagination.CurrentPage < pp.Meta.Pagination.TotalPages, nil
 }
+
+// GetChannel returns a channel by ID
+func (bc *Client) GetChannel(channelID int64) (*Channel, error) {
+	var ret Channel
+	err := bc.sendJSON(http.MethodGet, fmt.Sprintf("/v3/channels/%d", channelID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// channelPayload returns the fields of a channel sent on create and update
+func channelPayload(ch *Channel) Patch {
+	p, _ := PatchFrom(ch, "name", "external_id", "is_listable_from_ui", "is_visible", "status", "config_meta")
+	if ch.ConfigMeta == nil {
+		delete(p, "config_meta")
+	}
+	if ch.Status == "" {
+		delete(p, "status")
+	}
+	return p
+}
+
+// CreateChannel creates a channel, Name, Type and Platform are required
+func (bc *Client) CreateChannel(channel *Channel) (*Channel, error) {
+	p := channelPayload(channel)
+	p["type"] = channel.Type
+	p["platform"] = channel.Platform
+	var ret Channel
+	err := bc.sendJSON(http.MethodPost, "/v3/channels", p, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// UpdateChannel updates a channel by ID, type and platform can't be changed
+func (bc *Client) UpdateChannel(channel *Channel) (*Channel, error) {
+	if channel.ID == 0 {
+		return nil, errors.New("channel has no ID")
+	}
+	var ret Channel
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/channels/%d", channel.ID), channelPayload(channel), &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// GetChannelMenus returns the control panel menu of a channel
+func (bc *Client) GetChannelMenus(channelID int64) (*ChannelMenus, error) {
+	var ret ChannelMenus
+	err := bc.sendJSON(http.MethodGet, fmt.Sprintf("/v3/channels/%d/channel-menus", channelID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// SetChannelMenus replaces the control panel menu of a channel
+func (bc *Client) SetChannelMenus(channelID int64, menus *ChannelMenus) (*ChannelMenus, error) {
+	var ret ChannelMenus
+	err := bc.sendJSON(http.MethodPost, fmt.Sprintf("/v3/channels/%d/channel-menus", channelID), menus, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// DeleteChannelMenus removes the custom sections of the control panel menu of a channel
+func (bc *Client) DeleteChannelMenus(channelID int64) error {
+	err := bc.sendJSON(http.MethodDelete, fmt.Sprintf("/v3/channels/%d/channel-menus", channelID), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
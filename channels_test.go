@@ -0,0 +1,48 @@
+package bigcommerce_test
+
+import (
+	"testing"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+	"github.com/mvalenziano/bigcommerce-api-go/bctest"
+)
+
+func TestChannelLifecycle(t *testing.T) {
+	bc := bctest.NewServer().Client()
+	created, err := bc.CreateChannel(&bigcommerce.Channel{Name: "Outlet", Type: "storefront", Platform: "custom"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the ID of a listed or created channel is used as is by the channel, site and menu calls
+	got, err := bc.GetChannel(created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Name != "Outlet" {
+		t.Errorf("channel %q, want Outlet", got.Name)
+	}
+	got.Name = "Outlet Store"
+	updated, err := bc.UpdateChannel(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.ID != created.ID || updated.Name != "Outlet Store" {
+		t.Errorf("updated channel %d %q, want %d Outlet Store", updated.ID, updated.Name, created.ID)
+	}
+	channels, err := bc.GetAllChannels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, ch := range channels {
+		if ch.ID == created.ID {
+			found = true
+			if _, err := bc.GetChannelMenus(ch.ID); err != nil {
+				t.Error(err)
+			}
+		}
+	}
+	if !found {
+		t.Errorf("channel %d not listed", created.ID)
+	}
+}
@@ -1,12 +1,17 @@
 package bigcommerce
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"log"
 	"net/http"
 	"net/url"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -25,7 +30,7 @@ var ErrNotFound = errors.New("404 not found")
 
 // AuthContexter interface for GetAuthContext
 type AuthContexter interface {
-	GetAuthContext(clientID, clientSecret string, q url.Values) (*AuthContext, error)
+	GetAuthContext(requestURLQuery url.Values) (*AuthContext, error)
 }
 
 func NewClient(storeHash, xAuthToken string) *Client {
@@ -40,6 +45,15 @@ func NewClient(storeHash, xAuthToken string) *Client {
 	}
 }
 
+// WithChannel returns a copy of the client scoped to a channel: ValidateCredentials, CreateAccount,
+// SaveAccount and CreateCart of the copy use channelID. The copy shares the HTTP client of bc,
+// with its transport and middleware, so it is cheap to make one per request.
+func (bc *Client) WithChannel(channelID int) *Client {
+	c := *bc
+	c.ChannelID = channelID
+	return &c
+}
+
 func (bc *Client) getAPIRequest(method, url string, body io.Reader) *http.Request {
 	if !strings.HasPrefix(url, "/") {
 		url = "/" + url
@@ -77,3 +91,144 @@ func processBody(res *http.Response) ([]byte, error) {
 	}
 	return body, nil
 }
+
+// sendJSON sends a request with payload as JSON body, nil for no body, and unmarshals the data
+// of the response into ret unless ret is nil. 422 errors are returned with the API messages.
+func (bc *Client) sendJSON(method, path string, payload interface{}, ret interface{}) error {
+	var reqBody io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewBuffer(b)
+	}
+	req := bc.getAPIRequest(method, path, reqBody)
+	res, err := bc.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	body, err := processBody(res)
+	if err != nil {
+		if res.StatusCode == http.StatusUnprocessableEntity || res.StatusCode == http.StatusConflict {
+			var errResp ErrorResult
+			err = json.Unmarshal(body, &errResp)
+			if err != nil {
+				log.Printf("Error: %s\nResult: %s", err, string(body))
+				return err
+			}
+			if len(errResp.Errors) > 0 {
+				errs := []string{}
+				for _, e := range errResp.Errors {
+					errs = append(errs, e)
+				}
+				sort.Strings(errs)
+				return fmt.Errorf("%s", strings.Join(errs, ", "))
+			}
+			if errResp.Title != "" {
+				return errors.New(errResp.Title)
+			}
+			return errors.New("unknown error")
+		}
+		return err
+	}
+	if ret == nil {
+		return nil
+	}
+	var response struct {
+		Data interface{} `json:"data"`
+	}
+	response.Data = ret
+	return json.Unmarshal(body, &response)
+}
+
+// sendBatch sends a batch of size items as JSON and unmarshals the data of the response into
+// ret unless ret is nil. It returns the errors the API reported on items by their position in the
+// batch, and an error if the batch was rejected or any error did not refer to an item.
+func (bc *Client) sendBatch(method, path string, payload interface{}, size int, ret interface{}) (map[int]error, error) {
+	b, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	req := bc.getAPIRequest(method, path, bytes.NewBuffer(b))
+	res, err := bc.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := processBody(res)
+	if err == ErrNoContent {
+		return nil, nil
+	}
+	if err != nil {
+		// the whole batch was rejected, errors are keyed by the position of the item
+		var errResp ErrorResult
+		if body == nil || json.Unmarshal(body, &errResp) != nil {
+			return nil, err
+		}
+		itemErrs, batchErr := batchErrors(errResp, size)
+		if batchErr != nil {
+			return itemErrs, batchErr
+		}
+		return itemErrs, fmt.Errorf("batch rejected: %s", errResp.Title)
+	}
+	// partial success (207), the errors are reported in the errors list
+	var response struct {
+		Data   interface{}   `json:"data"`
+		Errors []ErrorResult `json:"errors"`
+	}
+	response.Data = ret
+	if ret == nil {
+		response.Data = &json.RawMessage{}
+	}
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, err
+	}
+	itemErrs := map[int]error{}
+	msgs := []string{}
+	for _, e := range response.Errors {
+		ie, batchErr := batchErrors(e, size)
+		for i, err := range ie {
+			itemErrs[i] = err
+		}
+		if batchErr != nil {
+			msgs = append(msgs, batchErr.Error())
+		}
+	}
+	if len(msgs) > 0 {
+		return itemErrs, errors.New(strings.Join(msgs, ", "))
+	}
+	return itemErrs, nil
+}
+
+// getPage gets a page of a v3 list, unmarshals its data into ret and returns whether there are more pages
+func (bc *Client) getPage(path string, page int, ret interface{}) (bool, error) {
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	req := bc.getAPIRequest(http.MethodGet, path+sep+"page="+strconv.Itoa(page), nil)
+	res, err := bc.HTTPClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+	body, err := processBody(res)
+	if err != nil {
+		return false, err
+	}
+	var pp struct {
+		Data interface{} `json:"data"`
+		Meta struct {
+			Pagination Pagination `json:"pagination"`
+		} `json:"meta"`
+	}
+	pp.Data = ret
+	err = json.Unmarshal(body, &pp)
+	if err != nil {
+		return false, err
+	}
+	return pp.Meta.Pagination.CurrentPage < pp.Meta.Pagination.TotalPages, nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+// Capture is a single recorded webhook delivery as stored on disk
+type Capture struct {
+	ReceivedAt time.Time       `json:"received_at"`
+	Method     string          `json:"method"`
+	Path       string          `json:"path"`
+	Headers    http.Header     `json:"headers"`
+	Scope      string          `json:"scope"`
+	Payload    json.RawMessage `json:"payload,omitempty"`
+	// RawBody holds bodies that are not JSON, replayed unchanged
+	RawBody []byte `json:"raw_body,omitempty"`
+}
+
+// body returns the body to send when the capture is replayed
+func (c *Capture) body() []byte {
+	if c.RawBody != nil {
+		return c.RawBody
+	}
+	return c.Payload
+}
+
+// headers that belong to the original connection and must not be replayed
+var skipHeaders = map[string]bool{
+	"Content-Length":    true,
+	"Host":              true,
+	"Connection":        true,
+	"Accept-Encoding":   true,
+	"Transfer-Encoding": true,
+}
+
+func runCapture(args []string) error {
+	fs := flag.NewFlagSet("capture", flag.ExitOnError)
+	addr := fs.String("addr", ":8080", "address to listen on")
+	dir := fs.String("dir", "captures", "directory to write captured deliveries to")
+	forward := fs.String("forward", "", "optional URL to forward every delivery to after saving it")
+	fs.Parse(args)
+
+	err := os.MkdirAll(*dir, 0755)
+	if err != nil {
+		return err
+	}
+	h := &captureHandler{dir: *dir, forward: *forward}
+	log.Printf("capturing webhooks on %s into %s", *addr, *dir)
+	return http.ListenAndServe(*addr, h)
+}
+
+type captureHandler struct {
+	dir     string
+	forward string
+	mu      sync.Mutex
+	seq     int
+}
+
+func (h *captureHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	payload, raw, err := bigcommerce.GetWebhookPayload(r)
+	if err != nil && raw == nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	c := Capture{
+		ReceivedAt: time.Now().UTC(),
+		Method:     r.Method,
+		Path:       r.URL.RequestURI(),
+		Headers:    r.Header.Clone(),
+	}
+	if json.Valid(raw) {
+		c.Payload = raw
+	} else {
+		// keep non-JSON bodies too, they are usually what we are debugging
+		c.RawBody = raw
+	}
+	if payload != nil {
+		c.Scope = payload.Scope
+	} else {
+		// cart and store scopes have string IDs that WebhookPayload can't decode
+		var p struct {
+			Scope string `json:"scope"`
+		}
+		json.Unmarshal(raw, &p)
+		c.Scope = p.Scope
+	}
+	name, err := h.save(&c)
+	if err != nil {
+		log.Printf("error saving capture: %v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	log.Printf("%s %s -> %s", c.Method, c.Scope, name)
+
+	if h.forward != "" {
+		res, err := sendCapture(http.DefaultClient, h.forward, &c, raw)
+		if err != nil {
+			log.Printf("error forwarding %s: %v", name, err)
+		} else {
+			log.Printf("forwarded %s: %s", name, res.Status)
+		}
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// save writes the capture as <timestamp>-<seq>-<scope>.json so a directory listing replays in arrival order
+func (h *captureHandler) save(c *Capture) (string, error) {
+	h.mu.Lock()
+	h.seq++
+	seq := h.seq
+	h.mu.Unlock()
+
+	scope := strings.NewReplacer("/", "_", ".", "_").Replace(c.Scope)
+	if scope == "" {
+		scope = "unknown"
+	}
+	name := fmt.Sprintf("%s-%04d-%s.json", c.ReceivedAt.Format("20060102T150405"), seq, scope)
+	b, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return name, ioutil.WriteFile(filepath.Join(h.dir, name), b, 0644)
+}
+
+// sendCapture posts a payload to url with the capture's original headers
+func sendCapture(client *http.Client, url string, c *Capture, payload []byte) (*http.Response, error) {
+	method := c.Method
+	if method == "" {
+		method = http.MethodPost
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range c.Headers {
+		if skipHeaders[http.CanonicalHeaderKey(k)] {
+			continue
+		}
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	if req.Header.Get("Content-Type") == "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	return res, nil
+}
@@ -0,0 +1,46 @@
+// Command bcwebhook records BigCommerce webhook deliveries and replays them
+// against a local endpoint, so webhook handlers can be debugged without a live store.
+//
+// Usage:
+//
+//	bcwebhook capture -addr :8080 -dir ./captures [-forward http://localhost:3000/webhooks]
+//	bcwebhook replay -dir ./captures -url http://localhost:3000/webhooks [-id-offset 1000] [-id-map 101=5,102=6] [-now] [-delay 1s]
+//	bcwebhook samples -dir ./captures [-scope store/order/created]
+package main
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `usage: bcwebhook <command> [flags]
+
+commands:
+  capture   listen for webhook deliveries and save them as JSON files
+  replay    send saved deliveries to a URL with their original headers
+  samples   write canned sample deliveries for every webhook scope
+`)
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	var err error
+	switch os.Args[1] {
+	case "capture":
+		err = runCapture(os.Args[2:])
+	case "replay":
+		err = runReplay(os.Args[2:])
+	case "samples":
+		err = runSamples(os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// numeric ID fields found in webhook data objects, see bigcommerce.WebhookPayload
+var idFields = map[string]bool{
+	"id":               true,
+	"orderId":          true,
+	"customer_id":      true,
+	"product_id":       true,
+	"variant_id":       true,
+	"order_message_id": true,
+}
+
+// rewriter changes IDs and timestamps of a payload before it is replayed
+type rewriter struct {
+	idOffset   int64
+	idMap      map[int64]int64
+	timestamps bool
+}
+
+func (rw *rewriter) active() bool {
+	return rw.idOffset != 0 || len(rw.idMap) > 0 || rw.timestamps
+}
+
+func runReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	dir := fs.String("dir", "captures", "directory (or single file) of captured deliveries")
+	url := fs.String("url", "", "URL to replay the deliveries against (required)")
+	idOffset := fs.Int64("id-offset", 0, "add this value to every numeric ID in the payload data")
+	idMap := fs.String("id-map", "", "comma separated old=new ID replacements, e.g. 101=5,102=6")
+	timestamps := fs.Bool("now", false, "rewrite created_at to the current time")
+	delay := fs.Duration("delay", 0, "wait between deliveries")
+	fs.Parse(args)
+
+	if *url == "" {
+		return fmt.Errorf("replay: -url is required")
+	}
+	rw := &rewriter{idOffset: *idOffset, timestamps: *timestamps}
+	if *idMap != "" {
+		m, err := parseIDMap(*idMap)
+		if err != nil {
+			return err
+		}
+		rw.idMap = m
+	}
+	files, err := captureFiles(*dir)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("replay: no captures found in %s", *dir)
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	failed := 0
+	for i, f := range files {
+		if i > 0 && *delay > 0 {
+			time.Sleep(*delay)
+		}
+		c, err := readCapture(f)
+		if err != nil {
+			log.Printf("skipping %s: %v", f, err)
+			failed++
+			continue
+		}
+		payload := c.body()
+		// bodies that are not JSON are replayed as captured
+		if rw.active() && c.RawBody == nil {
+			payload, err = rw.rewrite(payload)
+			if err != nil {
+				log.Printf("skipping %s: can't rewrite payload: %v", f, err)
+				failed++
+				continue
+			}
+		}
+		res, err := sendCapture(client, *url, c, payload)
+		if err != nil {
+			log.Printf("%s: %v", filepath.Base(f), err)
+			failed++
+			continue
+		}
+		if res.StatusCode > 299 {
+			failed++
+		}
+		log.Printf("%s %s: %s", filepath.Base(f), c.Scope, res.Status)
+	}
+	if failed > 0 {
+		return fmt.Errorf("replay: %d of %d deliveries failed", failed, len(files))
+	}
+	return nil
+}
+
+// captureFiles returns the capture files under path in name (arrival) order
+func captureFiles(path string) ([]string, error) {
+	matches, err := filepath.Glob(filepath.Join(path, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 && strings.HasSuffix(path, ".json") {
+		matches = []string{path}
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+func readCapture(path string) (*Capture, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var c Capture
+	err = json.Unmarshal(b, &c)
+	if err != nil {
+		return nil, err
+	}
+	if len(c.body()) == 0 {
+		return nil, fmt.Errorf("empty payload")
+	}
+	return &c, nil
+}
+
+func parseIDMap(s string) (map[int64]int64, error) {
+	m := map[int64]int64{}
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.SplitN(strings.TrimSpace(pair), "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid id mapping %q", pair)
+		}
+		from, err := strconv.ParseInt(parts[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id mapping %q: %v", pair, err)
+		}
+		to, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid id mapping %q: %v", pair, err)
+		}
+		m[from] = to
+	}
+	return m, nil
+}
+
+func (rw *rewriter) rewrite(payload []byte) ([]byte, error) {
+	var p map[string]interface{}
+	d := json.NewDecoder(strings.NewReader(string(payload)))
+	d.UseNumber()
+	err := d.Decode(&p)
+	if err != nil {
+		return nil, err
+	}
+	if rw.timestamps {
+		p["created_at"] = time.Now().Unix()
+	}
+	if data, ok := p["data"].(map[string]interface{}); ok {
+		rw.rewriteIDs(data)
+	}
+	return json.Marshal(p)
+}
+
+func (rw *rewriter) rewriteIDs(m map[string]interface{}) {
+	for k, v := range m {
+		switch val := v.(type) {
+		case map[string]interface{}:
+			rw.rewriteIDs(val)
+		case json.Number:
+			if !idFields[k] {
+				continue
+			}
+			id, err := val.Int64()
+			if err != nil {
+				continue
+			}
+			m[k] = rw.newID(id)
+		}
+	}
+}
+
+func (rw *rewriter) newID(id int64) int64 {
+	if n, ok := rw.idMap[id]; ok {
+		return n
+	}
+	if id == 0 {
+		return 0
+	}
+	return id + rw.idOffset
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const sampleStoreID = "1025646"
+const sampleStoreHash = "abc123def"
+
+// sampleData holds a canned data object for every webhook scope BigCommerce sends
+var sampleData = []struct {
+	Scope string
+	Data  string
+}{
+	{"store/app/uninstalled", `{"type":"store","id":"` + sampleStoreID + `"}`},
+	{"store/information/updated", `{"type":"store"}`},
+
+	{"store/order/created", `{"type":"order","id":250}`},
+	{"store/order/updated", `{"type":"order","id":250}`},
+	{"store/order/archived", `{"type":"order","id":250}`},
+	{"store/order/statusUpdated", `{"type":"order","id":250,"status":{"previous_status_id":0,"new_status_id":11}}`},
+	{"store/order/message/created", `{"type":"order","id":250,"message":{"order_message_id":3}}`},
+	{"store/order/refund/created", `{"type":"order","id":250,"refund":{"refund_id":12}}`},
+
+	{"store/product/created", `{"type":"product","id":205}`},
+	{"store/product/updated", `{"type":"product","id":205}`},
+	{"store/product/deleted", `{"type":"product","id":205}`},
+	{"store/product/inventory/updated", `{"type":"product","id":189,"inventory":{"product_id":189,"method":"absolute","value":5}}`},
+	{"store/product/inventory/order/updated", `{"type":"product","id":189,"inventory":{"product_id":189,"method":"relative","value":-1}}`},
+
+	{"store/category/created", `{"type":"category","id":42}`},
+	{"store/category/updated", `{"type":"category","id":42}`},
+	{"store/category/deleted", `{"type":"category","id":42}`},
+
+	{"store/sku/created", `{"type":"sku","id":461,"sku":{"product_id":206,"variant_id":509}}`},
+	{"store/sku/updated", `{"type":"sku","id":461,"sku":{"product_id":206,"variant_id":509}}`},
+	{"store/sku/deleted", `{"type":"sku","id":461,"sku":{"product_id":206,"variant_id":509}}`},
+	{"store/sku/inventory/updated", `{"type":"sku","id":461,"inventory":{"product_id":206,"method":"absolute","value":5,"variant_id":509}}`},
+	{"store/sku/inventory/order/updated", `{"type":"sku","id":461,"inventory":{"product_id":206,"method":"relative","value":-1,"variant_id":509}}`},
+
+	{"store/customer/created", `{"type":"customer","id":32}`},
+	{"store/customer/updated", `{"type":"customer","id":32}`},
+	{"store/customer/deleted", `{"type":"customer","id":32}`},
+	{"store/customer/address/created", `{"type":"customer","id":60,"address":{"customer_id":32}}`},
+	{"store/customer/address/updated", `{"type":"customer","id":60,"address":{"customer_id":32}}`},
+	{"store/customer/address/deleted", `{"type":"customer","id":60,"address":{"customer_id":32}}`},
+	{"store/customer/payment/instrument/default/updated", `{"type":"customer","id":32}`},
+
+	{"store/cart/created", `{"type":"cart","id":"09346904-4175-44fd-be53-f7e598531b6c"}`},
+	{"store/cart/updated", `{"type":"cart","id":"09346904-4175-44fd-be53-f7e598531b6c"}`},
+	{"store/cart/deleted", `{"type":"cart","id":"09346904-4175-44fd-be53-f7e598531b6c"}`},
+	{"store/cart/couponApplied", `{"type":"cart","id":"09346904-4175-44fd-be53-f7e598531b6c","couponId":"1"}`},
+	{"store/cart/abandoned", `{"type":"cart","id":"09346904-4175-44fd-be53-f7e598531b6c"}`},
+	{"store/cart/converted", `{"type":"cart","id":"09346904-4175-44fd-be53-f7e598531b6c","orderId":252}`},
+	{"store/cart/lineItem/created", `{"type":"cart_line_item","id":"88e2f4b1-8b2d-4ab7-9b5a-1c8c2b1d3c44","cartId":"09346904-4175-44fd-be53-f7e598531b6c"}`},
+	{"store/cart/lineItem/updated", `{"type":"cart_line_item","id":"88e2f4b1-8b2d-4ab7-9b5a-1c8c2b1d3c44","cartId":"09346904-4175-44fd-be53-f7e598531b6c"}`},
+	{"store/cart/lineItem/deleted", `{"type":"cart_line_item","id":"88e2f4b1-8b2d-4ab7-9b5a-1c8c2b1d3c44","cartId":"09346904-4175-44fd-be53-f7e598531b6c"}`},
+
+	{"store/shipment/created", `{"type":"shipment","id":12,"orderId":251}`},
+	{"store/shipment/updated", `{"type":"shipment","id":12,"orderId":251}`},
+	{"store/shipment/deleted", `{"type":"shipment","id":12,"orderId":251}`},
+
+	{"store/subscriber/created", `{"type":"subscriber","id":5}`},
+	{"store/subscriber/updated", `{"type":"subscriber","id":5}`},
+	{"store/subscriber/deleted", `{"type":"subscriber","id":5}`},
+
+	{"store/channel/created", `{"type":"channel","id":2}`},
+	{"store/channel/updated", `{"type":"channel","id":2}`},
+}
+
+func runSamples(args []string) error {
+	fs := flag.NewFlagSet("samples", flag.ExitOnError)
+	dir := fs.String("dir", "samples", "directory to write the sample captures to")
+	scope := fs.String("scope", "", "only write samples whose scope starts with this prefix")
+	fs.Parse(args)
+
+	err := os.MkdirAll(*dir, 0755)
+	if err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	n := 0
+	for i, s := range sampleData {
+		if !strings.HasPrefix(s.Scope, *scope) {
+			continue
+		}
+		c, err := sampleCapture(s.Scope, s.Data, now)
+		if err != nil {
+			return fmt.Errorf("sample %s: %v", s.Scope, err)
+		}
+		b, err := json.MarshalIndent(c, "", "  ")
+		if err != nil {
+			return err
+		}
+		name := fmt.Sprintf("sample-%03d-%s.json", i, strings.ReplaceAll(s.Scope, "/", "_"))
+		err = ioutil.WriteFile(filepath.Join(*dir, name), b, 0644)
+		if err != nil {
+			return err
+		}
+		n++
+	}
+	if n == 0 {
+		return fmt.Errorf("samples: no scope matches %q", *scope)
+	}
+	log.Printf("wrote %d samples to %s", n, *dir)
+	return nil
+}
+
+// sampleCapture wraps a data object into a full delivery the way BigCommerce sends it
+func sampleCapture(scope, data string, now time.Time) (*Capture, error) {
+	if !json.Valid([]byte(data)) {
+		return nil, fmt.Errorf("invalid data JSON")
+	}
+	hash := sha1.Sum([]byte(scope + data))
+	payload, err := json.Marshal(map[string]interface{}{
+		"scope":      scope,
+		"store_id":   sampleStoreID,
+		"data":       json.RawMessage(data),
+		"hash":       hex.EncodeToString(hash[:]),
+		"created_at": now.Unix(),
+		"producer":   "stores/" + sampleStoreHash,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Capture{
+		ReceivedAt: now,
+		Method:     http.MethodPost,
+		Path:       "/webhooks",
+		Headers: http.Header{
+			"Content-Type": []string{"application/json"},
+			"User-Agent":   []string{"Go-http-client/1.1"},
+		},
+		Scope:   scope,
+		Payload: payload,
+	}, nil
+}
@@ -0,0 +1,135 @@
+package bigcommerce
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// CustomField is a name/value pair shown on the product page
+type CustomField struct {
+	ID    int64  `json:"id,omitempty"`
+	Name  string `json:"name,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// CustomFieldChanges lists the custom fields changed by SetCustomFields
+type CustomFieldChanges struct {
+	Created   []CustomField
+	Updated   []CustomField
+	Deleted   []CustomField
+	Unchanged []CustomField
+}
+
+// GetCustomFields returns all the custom fields of a product
+func (bc *Client) GetCustomFields(productID int64) ([]CustomField, error) {
+	ret := []CustomField{}
+	page := 1
+	more := true
+	for more {
+		var cfs []CustomField
+		var err error
+		more, err = bc.getPage(fmt.Sprintf("/v3/catalog/products/%d/custom-fields?limit=250", productID), page, &cfs)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, cfs...)
+		page++
+	}
+	return ret, nil
+}
+
+// GetCustomField gets a custom field of a product by ID
+func (bc *Client) GetCustomField(productID, customFieldID int64) (*CustomField, error) {
+	var ret CustomField
+	err := bc.sendJSON(http.MethodGet, fmt.Sprintf("/v3/catalog/products/%d/custom-fields/%d", productID, customFieldID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// CreateCustomField adds a custom field to a product
+func (bc *Client) CreateCustomField(productID int64, customField *CustomField) (*CustomField, error) {
+	payload := CustomField{Name: customField.Name, Value: customField.Value}
+	var ret CustomField
+	err := bc.sendJSON(http.MethodPost, fmt.Sprintf("/v3/catalog/products/%d/custom-fields", productID), payload, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// UpdateCustomField updates the name and value of a custom field of a product
+func (bc *Client) UpdateCustomField(productID int64, customField *CustomField) (*CustomField, error) {
+	if customField.ID == 0 {
+		return nil, errors.New("custom field has no ID")
+	}
+	payload := CustomField{Name: customField.Name, Value: customField.Value}
+	var ret CustomField
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/catalog/products/%d/custom-fields/%d", productID, customField.ID), payload, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// DeleteCustomField deletes a custom field of a product
+func (bc *Client) DeleteCustomField(productID, customFieldID int64) error {
+	err := bc.sendJSON(http.MethodDelete, fmt.Sprintf("/v3/catalog/products/%d/custom-fields/%d", productID, customFieldID), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// SetCustomFields makes the custom fields of a product match fields, a map of value by name:
+// missing fields are created, fields with another value are updated, fields not in the map
+// and duplicates of a name are deleted. Names are case sensitive like on the storefront.
+func (bc *Client) SetCustomFields(productID int64, fields map[string]string) (*CustomFieldChanges, error) {
+	current, err := bc.GetCustomFields(productID)
+	if err != nil {
+		return nil, err
+	}
+	ret := &CustomFieldChanges{}
+	seen := map[string]bool{}
+	for _, cf := range current {
+		value, wanted := fields[cf.Name]
+		if !wanted || seen[cf.Name] {
+			err = bc.DeleteCustomField(productID, cf.ID)
+			if err != nil {
+				return ret, fmt.Errorf("deleting custom field %s: %w", cf.Name, err)
+			}
+			ret.Deleted = append(ret.Deleted, cf)
+			continue
+		}
+		seen[cf.Name] = true
+		if cf.Value == value {
+			ret.Unchanged = append(ret.Unchanged, cf)
+			continue
+		}
+		cf.Value = value
+		updated, err := bc.UpdateCustomField(productID, &cf)
+		if err != nil {
+			return ret, fmt.Errorf("updating custom field %s: %w", cf.Name, err)
+		}
+		ret.Updated = append(ret.Updated, *updated)
+	}
+
+	names := []string{}
+	for name := range fields {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		created, err := bc.CreateCustomField(productID, &CustomField{Name: name, Value: fields[name]})
+		if err != nil {
+			return ret, fmt.Errorf("creating custom field %s: %w", name, err)
+		}
+		ret.Created = append(ret.Created, *created)
+	}
+	return ret, nil
+}
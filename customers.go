@@ -95,6 +95,11 @@ type FormField struct {
 
 // ValidateCredentials returns customer ID or error (i.e. ErrNotfound) if the provided credentials are valid in BigCommerce
 func (bc *Client) ValidateCredentials(email, password string) (int64, error) {
+	return bc.ValidateCredentialsInChannel(bc.ChannelID, email, password)
+}
+
+// ValidateCredentialsInChannel returns the customer ID if the credentials are valid in a channel, ErrNotFound if not
+func (bc *Client) ValidateCredentialsInChannel(channelID int, email, password string) (int64, error) {
 	var credReq struct {
 		Email     string `json:"email"`
 		Password  string `json:"password"`
@@ -102,7 +107,7 @@ func (bc *Client) ValidateCredentials(email, password string) (int64, error) {
 	}
 	credReq.Email = email
 	credReq.Password = password
-	credReq.ChannelID = bc.ChannelID
+	credReq.ChannelID = channelID
 	var b []byte
 	b, _ = json.Marshal(credReq)
 	req := bc.getAPIRequest(http.MethodPost, "/v3/customers/validate-credentials", bytes.NewBuffer(b))
@@ -332,3 +337,34 @@ func (bc *Client) GetCustomerByEmail(email string) (*Customer, error) {
 	}
 	return &ret.Data[0], nil // return the first customer
 }
+
+// GetAllCustomers returns all customers, handling pagination
+// args is a map of arguments to pass to the API, for example {"date_modified:min": "2023-01-01T00:00:00Z"}
+func (bc *Client) GetAllCustomers(args map[string]string) ([]Customer, error) {
+	ret := []Customer{}
+	page := 1
+	more := true
+	for more {
+		var cs []Customer
+		var err error
+		cs, more, err = bc.GetCustomers(args, page)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, cs...)
+		page++
+	}
+	return ret, nil
+}
+
+// GetCustomers returns a page of customers
+// args is a map of arguments to pass to the API
+// page: the page number to download
+func (bc *Client) GetCustomers(args map[string]string, page int) ([]Customer, bool, error) {
+	var cs []Customer
+	more, err := bc.getPage(withArgs("/v3/customers", args), page, &cs)
+	if err != nil {
+		return nil, false, err
+	}
+	return cs, more, nil
+}
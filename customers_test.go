@@ -0,0 +1,64 @@
+package bigcommerce_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+	"github.com/mvalenziano/bigcommerce-api-go/bctest"
+)
+
+// failPage fails the requests for a page of a list
+type failPage struct {
+	bigcommerce.HTTPClient
+	page string
+	err  error
+}
+
+func (f failPage) Do(req *http.Request) (*http.Response, error) {
+	if req.URL.Query().Get("page") == f.page {
+		return nil, f.err
+	}
+	return f.HTTPClient.Do(req)
+}
+
+func TestGetAllCustomers(t *testing.T) {
+	s := bctest.NewServer()
+	for i := 1; i <= 60; i++ {
+		s.AddCustomer(bigcommerce.Customer{Email: fmt.Sprintf("c%d@example.com", i), Firstname: "C", Lastname: fmt.Sprint(i)}, "")
+	}
+	unavailable := errors.New("store unavailable")
+	tests := []struct {
+		name       string
+		failPage   string
+		maxRetries int
+		want       int
+		wantErr    error
+	}{
+		{name: "all pages", want: 60},
+		{name: "first page fails", failPage: "1", maxRetries: 0, wantErr: unavailable},
+		{name: "second page fails", failPage: "2", maxRetries: 1, wantErr: unavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bc := s.Client()
+			bc.MaxRetries = tt.maxRetries
+			bc.HTTPClient = failPage{HTTPClient: bc.HTTPClient, page: tt.failPage, err: unavailable}
+			cs, err := bc.GetAllCustomers(nil)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || cs != nil {
+					t.Fatalf("got %d customers and error %v, want no customers and %v", len(cs), err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(cs) != tt.want {
+				t.Errorf("got %d customers, want %d", len(cs), tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,21 @@
 package bigcommerce
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log"
+	"mime/multipart"
 	"net/http"
+	neturl "net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
 	"strconv"
+	"strings"
 )
 
 // Image is entry for BC product images
@@ -20,6 +31,8 @@ type Image struct {
 	URLThumbnail string `json:"url_thumbnail"`
 	URLTiny      string `json:"url_tiny"`
 	DateModified string `json:"date_modified"`
+	// ImageURL is the source URL when creating an image from a URL
+	ImageURL string `json:"image_url,omitempty"`
 }
 
 // GetMainThumbnailURL returns the main thumbnail URL for a product
@@ -57,3 +70,269 @@ func (bc *Client) GetMainThumbnailURL(productID int64) (string, error) {
 	}
 	return "", ErrNoMainThumbnail
 }
+
+// GetProductImages returns all the images of a product
+func (bc *Client) GetProductImages(productID int64) ([]Image, error) {
+	ret := []Image{}
+	page := 1
+	more := true
+	for more {
+		var is []Image
+		var err error
+		more, err = bc.getPage(fmt.Sprintf("/v3/catalog/products/%d/images?limit=250", productID), page, &is)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, is...)
+		page++
+	}
+	return ret, nil
+}
+
+// CreateProductImage creates a product image from image.ImageURL, BigCommerce downloads the image.
+// Description, SortOrder and IsThumbnail of image are set on the new image.
+func (bc *Client) CreateProductImage(productID int64, image *Image) (*Image, error) {
+	if image.ImageURL == "" {
+		return nil, errors.New("image has no image_url")
+	}
+	payload := struct {
+		ImageURL    string `json:"image_url"`
+		Description string `json:"description,omitempty"`
+		SortOrder   int64  `json:"sort_order"`
+		IsThumbnail bool   `json:"is_thumbnail"`
+	}{image.ImageURL, image.Description, image.SortOrder, image.IsThumbnail}
+	var ret Image
+	err := bc.sendJSON(http.MethodPost, fmt.Sprintf("/v3/catalog/products/%d/images", productID), payload, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// UploadProductImage creates a product image uploading the content of r as filename.
+// Description, SortOrder and IsThumbnail of image are set on the new image, image can be nil.
+func (bc *Client) UploadProductImage(productID int64, filename string, r io.Reader, image *Image) (*Image, error) {
+	fields := map[string]string{}
+	if image != nil {
+		if image.Description != "" {
+			fields["description"] = image.Description
+		}
+		fields["sort_order"] = strconv.FormatInt(image.SortOrder, 10)
+		fields["is_thumbnail"] = strconv.FormatBool(image.IsThumbnail)
+	}
+	var ret Image
+	err := bc.postFile(fmt.Sprintf("/v3/catalog/products/%d/images", productID), filename, r, fields, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// postFile posts the content of r as the image_file of a multipart form with fields,
+// and unmarshals the data of the response into ret
+func (bc *Client) postFile(url, filename string, r io.Reader, fields map[string]string, ret interface{}) error {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	keys := []string{}
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		w.WriteField(k, fields[k])
+	}
+	part, err := w.CreateFormFile("image_file", filepath.Base(filename))
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, r)
+	if err != nil {
+		return err
+	}
+	err = w.Close()
+	if err != nil {
+		return err
+	}
+
+	req := bc.getAPIRequest(http.MethodPost, url, &buf)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	res, err := bc.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	body, err := processBody(res)
+	if err != nil {
+		if res.StatusCode == http.StatusUnprocessableEntity {
+			var errResp ErrorResult
+			if json.Unmarshal(body, &errResp) == nil && errResp.Title != "" {
+				return errors.New(errResp.Title)
+			}
+		}
+		return err
+	}
+	var fileResponse struct {
+		Data json.RawMessage `json:"data"`
+	}
+	err = json.Unmarshal(body, &fileResponse)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(fileResponse.Data, ret)
+}
+
+// UploadProductImageFile creates a product image uploading a local file, image can be nil
+func (bc *Client) UploadProductImageFile(productID int64, filename string, image *Image) (*Image, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return bc.UploadProductImage(productID, filename, f, image)
+}
+
+// UpdateProductImage updates the description, sort order and thumbnail flag of a product image.
+// Setting IsThumbnail makes BigCommerce unset it on the other images of the product.
+func (bc *Client) UpdateProductImage(productID int64, image *Image) (*Image, error) {
+	if image.ID == 0 {
+		return nil, errors.New("image has no ID")
+	}
+	payload := Patch{
+		"description":  image.Description,
+		"sort_order":   image.SortOrder,
+		"is_thumbnail": image.IsThumbnail,
+	}
+	var ret Image
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/catalog/products/%d/images/%d", productID, image.ID), payload, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// DeleteProductImage deletes a product image
+func (bc *Client) DeleteProductImage(productID, imageID int64) error {
+	err := bc.sendJSON(http.MethodDelete, fmt.Sprintf("/v3/catalog/products/%d/images/%d", productID, imageID), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// ImageSource is an image for ReconcileProductImages: a URL, a local file or a reader
+type ImageSource struct {
+	URL  string
+	Path string
+	// Reader is uploaded as Name when URL and Path are empty
+	Reader      io.Reader
+	Name        string
+	Description string
+}
+
+// fileName returns the file name of the source, without extension
+func (src ImageSource) fileName() string {
+	name := src.Name
+	switch {
+	case src.URL != "":
+		if u, err := neturl.Parse(src.URL); err == nil {
+			name = path.Base(u.Path)
+		} else {
+			name = path.Base(src.URL)
+		}
+	case src.Path != "":
+		name = filepath.Base(src.Path)
+	}
+	return strings.TrimSuffix(name, path.Ext(name))
+}
+
+// imageFileName returns the original file name of an image without extension:
+// BigCommerce stores images as e.g. "h/123/shirt-red__41234.jpg" for shirt-red.jpg
+func imageFileName(image Image) string {
+	name := path.Base(image.ImageFile)
+	name = strings.TrimSuffix(name, path.Ext(name))
+	if i := strings.LastIndex(name, "__"); i > 0 {
+		name = name[:i]
+	}
+	return name
+}
+
+// ImageReconcileResult lists the images changed by ReconcileProductImages
+type ImageReconcileResult struct {
+	Created   []Image
+	Updated   []Image
+	Deleted   []Image
+	Unchanged []Image
+}
+
+// ReconcileProductImages makes the images of a product match sources: the first source is the thumbnail,
+// sort orders follow the order of sources. Images are matched to sources by file name, so images
+// already on the product are not uploaded again. Images that match no source are deleted.
+func (bc *Client) ReconcileProductImages(productID int64, sources []ImageSource) (*ImageReconcileResult, error) {
+	images, err := bc.GetProductImages(productID)
+	if err != nil {
+		return nil, err
+	}
+	ret := &ImageReconcileResult{}
+	used := map[int64]bool{}
+	matched := make([]*Image, len(sources))
+	for i, src := range sources {
+		name := strings.ToLower(src.fileName())
+		for j := range images {
+			if !used[images[j].ID] && name != "" && strings.ToLower(imageFileName(images[j])) == name {
+				used[images[j].ID] = true
+				matched[i] = &images[j]
+				break
+			}
+		}
+	}
+
+	// delete first, so there is only one thumbnail at any time
+	for _, image := range images {
+		if used[image.ID] {
+			continue
+		}
+		err = bc.DeleteProductImage(productID, image.ID)
+		if err != nil {
+			return ret, fmt.Errorf("deleting image %d: %w", image.ID, err)
+		}
+		ret.Deleted = append(ret.Deleted, image)
+	}
+
+	for i, src := range sources {
+		want := Image{
+			Description: src.Description,
+			SortOrder:   int64(i),
+			IsThumbnail: i == 0,
+		}
+		if existing := matched[i]; existing != nil {
+			if existing.Description == want.Description && existing.SortOrder == want.SortOrder && existing.IsThumbnail == want.IsThumbnail {
+				ret.Unchanged = append(ret.Unchanged, *existing)
+				continue
+			}
+			want.ID = existing.ID
+			updated, err := bc.UpdateProductImage(productID, &want)
+			if err != nil {
+				return ret, fmt.Errorf("updating image %d: %w", existing.ID, err)
+			}
+			ret.Updated = append(ret.Updated, *updated)
+			continue
+		}
+		var created *Image
+		switch {
+		case src.URL != "":
+			want.ImageURL = src.URL
+			created, err = bc.CreateProductImage(productID, &want)
+		case src.Path != "":
+			created, err = bc.UploadProductImageFile(productID, src.Path, &want)
+		case src.Reader != nil:
+			created, err = bc.UploadProductImage(productID, src.Name, src.Reader, &want)
+		default:
+			err = errors.New("image source has no URL, path or reader")
+		}
+		if err != nil {
+			return ret, fmt.Errorf("creating image %d (%s): %w", i, src.fileName(), err)
+		}
+		ret.Created = append(ret.Created, *created)
+	}
+	return ret, nil
+}
@@ -1,41 +1,255 @@
 package bigcommerce
 
 import (
+	"io"
 	"net/url"
 )
 
+// compile-time checks that the clients implement the interfaces
+var (
+	_ AppClient       = (*App)(nil)
+	_ StoreClient     = (*Client)(nil)
+	_ ChannelClient   = (*Client)(nil)
+	_ PriceListClient = (*Client)(nil)
+	_ InventoryClient = (*Client)(nil)
+	_ CatalogClient   = (*Client)(nil)
+	_ OptionClient    = (*Client)(nil)
+	_ MetafieldClient = (*Client)(nil)
+	_ BlogClient      = (*Client)(nil)
+	_ CartClient      = (*Client)(nil)
+	_ CheckoutClient  = (*Client)(nil)
+	_ CustomerClient  = (*Client)(nil)
+	_ AddressClient   = (*Client)(nil)
+	_ OrderClient     = (*Client)(nil)
+	_ CouponClient    = (*Client)(nil)
+	_ WebhookClient   = (*Client)(nil)
+	_ ContentClient   = (*Client)(nil)
+)
+
+// AppClient interface handles app installation and load requests
+type AppClient interface {
+	AuthContexter
+	GetClientRequest(requestURLQuery url.Values) (*ClientRequest, error)
+}
+
 // StoreClient interface handles generic store requests
 type StoreClient interface {
 	GetAllChannels() ([]Channel, error)
 	GetChannels(page int) ([]Channel, bool, error)
-	GetClientRequest(requestURLQuery url.Values) (*ClientRequest, error)
 	GetStoreInfo() (StoreInfo, error)
+	GetCurrencies() ([]Currency, error)
+	GetTaxZones(zoneIds []int) (*[]TaxZone, error)
+	GetTaxRates(taxZoneIds []int) (*[]TaxClassRate, error)
+}
+
+// ChannelClient interface handles channel management, sites, routes, currencies and menus
+type ChannelClient interface {
+	GetChannel(channelID int64) (*Channel, error)
+	CreateChannel(channel *Channel) (*Channel, error)
+	UpdateChannel(channel *Channel) (*Channel, error)
+	GetChannelMenus(channelID int64) (*ChannelMenus, error)
+	SetChannelMenus(channelID int64, menus *ChannelMenus) (*ChannelMenus, error)
+	DeleteChannelMenus(channelID int64) error
+	GetAllChannelCurrencies() ([]ChannelCurrencies, error)
+	GetChannelCurrencies(channelID int64) (*ChannelCurrencies, error)
+	CreateChannelCurrencies(currencies *ChannelCurrencies) (*ChannelCurrencies, error)
+	UpdateChannelCurrencies(currencies *ChannelCurrencies) (*ChannelCurrencies, error)
+	DeleteChannelCurrencies(channelID int64) error
+	GetSites(channelIDs ...int64) ([]Site, error)
+	GetSite(siteID int64) (*Site, error)
+	GetChannelSite(channelID int64) (*Site, error)
+	CreateSite(site *Site) (*Site, error)
+	UpdateSite(site *Site) (*Site, error)
+	DeleteSite(siteID int64) error
+	GetSiteRoutes(siteID int64, routeType string) ([]SiteRoute, error)
+	CreateSiteRoute(siteID int64, route *SiteRoute) (*SiteRoute, error)
+	UpsertSiteRoutes(siteID int64, routes []SiteRoute) ([]SiteRoute, error)
+	DeleteSiteRoute(siteID, routeID int64) error
+}
+
+// PriceListClient interface handles price lists, their records and assignments, and computed prices
+type PriceListClient interface {
+	GetProductPrices(request PricingRequest) ([]ProductPrice, error)
+	GetAllPriceLists(args map[string]string) ([]PriceList, error)
+	GetPriceLists(args map[string]string, page int) ([]PriceList, bool, error)
+	GetPriceList(priceListID int64) (*PriceList, error)
+	CreatePriceList(priceList *PriceList) (*PriceList, error)
+	UpdatePriceList(priceList *PriceList) (*PriceList, error)
+	DeletePriceList(priceListID int64) error
+	GetAllPriceRecords(priceListID int64, args map[string]string) ([]PriceRecord, error)
+	GetPriceRecords(priceListID int64, args map[string]string, page int) ([]PriceRecord, bool, error)
+	GetPriceRecord(priceListID, variantID int64, currency string) (*PriceRecord, error)
+	UpsertPriceRecords(priceListID int64, records []PriceRecord) (map[int]error, error)
+	DeletePriceRecords(priceListID int64, variantIDs []int64) error
+	DeletePriceRecordsBySku(priceListID int64, skus []string) error
+	GetAllPriceListAssignments(args map[string]string) ([]PriceListAssignment, error)
+	GetPriceListAssignments(args map[string]string, page int) ([]PriceListAssignment, bool, error)
+	CreatePriceListAssignments(assignments []PriceListAssignment) error
+	DeletePriceListAssignments(args map[string]string) error
 }
 
 // CatalogClient interface handles catalog-related requests
 type CatalogClient interface {
-	GetAllBrands() ([]Brand, error)
-	GetBrands(page int) ([]Brand, bool, error)
-	GetAllCategories() ([]Category, error)
-	GetCategories(page int) ([]Category, bool, error)
-	GetClientRequest(requestURLQuery url.Values) (*ClientRequest, error)
+	GetAllBrands(args map[string]string) ([]Brand, error)
+	GetBrands(args map[string]string, page int) ([]Brand, bool, error)
+	GetBrand(brandID int64) (*Brand, error)
+	GetBrandByName(name string) (*Brand, error)
+	GetOrCreateBrand(name string) (*Brand, error)
+	CreateBrand(brand *Brand) (*Brand, error)
+	UpdateBrand(brand *Brand) (*Brand, error)
+	DeleteBrand(brandID int64) error
+	UploadBrandImage(brandID int64, filename string, r io.Reader) (string, error)
+	UploadBrandImageFile(brandID int64, filename string) (string, error)
+	SetBrandImageURL(brandID int64, imageURL string) (*Brand, error)
+	DeleteBrandImage(brandID int64) error
+	GetBrandMetafields(brandID int64) (map[string]Metafield, error)
+	GetAllCategories(args map[string]string) ([]Category, error)
+	GetCategories(args map[string]string, page int) ([]Category, bool, error)
+	GetCategory(categoryID int64) (*Category, error)
+	CreateCategory(category *Category) (*Category, error)
+	UpdateCategory(category *Category) (*Category, error)
+	DeleteCategory(categoryID int64) error
+	CreateCategories(categories []Category) ([]Category, error)
+	UpdateCategories(categories []Category) ([]Category, error)
+	DeleteCategories(categoryIDs []int64) error
+	GetCategoryAssignments(productIDs, categoryIDs []int64) ([]CategoryAssignment, error)
+	CreateCategoryAssignments(assignments []CategoryAssignment) error
+	DeleteCategoryAssignments(productIDs, categoryIDs []int64) error
+	GetCategorySortOrder(categoryID int64) ([]ProductSortOrder, error)
+	SetCategorySortOrder(categoryID int64, orders []ProductSortOrder) error
+	SortCategoryProducts(categoryID int64, productIDs []int64) error
+	GetCatalogTrees(channelIDs ...int64) ([]CatalogTree, error)
+	UpsertCatalogTrees(trees []CatalogTree) ([]CatalogTree, error)
+	DeleteCatalogTrees(treeIDs []int64) error
+	GetCategoryTree(treeID int64) (*CategoryTree, error)
+	GetChannelCategoryTree(channelID int64) (*CategoryTree, error)
 	GetMainThumbnailURL(productID int64) (string, error)
-	SetProductFields(fields []string)
-	SetProductInclude(subresources []string)
-	GetAllProducts(map[string]string) ([]Product, error)
-	GetProducts(page int) ([]Product, bool, error)
+	GetProductImages(productID int64) ([]Image, error)
+	CreateProductImage(productID int64, image *Image) (*Image, error)
+	UploadProductImage(productID int64, filename string, r io.Reader, image *Image) (*Image, error)
+	UpdateProductImage(productID int64, image *Image) (*Image, error)
+	DeleteProductImage(productID, imageID int64) error
+	GetProductVideos(productID int64) ([]Video, error)
+	CreateProductVideo(productID int64, video *Video) (*Video, error)
+	UpdateProductVideo(productID int64, video *Video) (*Video, error)
+	DeleteProductVideo(productID, videoID int64) error
+	GetCustomFields(productID int64) ([]CustomField, error)
+	CreateCustomField(productID int64, customField *CustomField) (*CustomField, error)
+	UpdateCustomField(productID int64, customField *CustomField) (*CustomField, error)
+	DeleteCustomField(productID, customFieldID int64) error
+	SetCustomFields(productID int64, fields map[string]string) (*CustomFieldChanges, error)
+	GetBulkPricingRules(productID int64) ([]BulkPricingRule, error)
+	CreateBulkPricingRule(productID int64, rule *BulkPricingRule) (*BulkPricingRule, error)
+	UpdateBulkPricingRule(productID int64, rule *BulkPricingRule) (*BulkPricingRule, error)
+	DeleteBulkPricingRule(productID, ruleID int64) error
+	GetAllProducts(args map[string]string) ([]Product, error)
+	GetProducts(args map[string]string, page int) ([]Product, bool, error)
 	GetProductByID(productID int64) (*Product, error)
+	GetProductBySku(sku string) (*Product, error)
+	GetProductMetafields(productID int64) (map[string]Metafield, error)
+	CreateProduct(payload *Product) (*Product, error)
+	UpdateProductBySku(payload *Product) (*Product, error)
+	UpdateProductInventory(prodInventoryPayload *ProductInventory) (*Product, error)
+	UpdateProductSalePrice(prodSalePricePayload *ProductSalePrice) (*Product, error)
+	UpdateProducts(products []Product) (ProductUpdateResults, error)
+	PatchProducts(patches []Patch) (ProductUpdateResults, error)
+	DeleteProduct(productID int64) error
+	DeleteProducts(productIDs []int64) error
+	PatchProduct(productID int64, patch Patch) (*Product, error)
+	PatchVariant(productID, variantID int64, patch Patch) (*Variant, error)
+	PatchCategory(categoryID int64, patch Patch) (*Category, error)
+	PatchBrand(brandID int64, patch Patch) (*Brand, error)
+	GetVariants(args map[string]string, page int) ([]Variant, bool, error)
+	GetAllProductVariants(productID int64, args map[string]string) ([]Variant, error)
+	GetProductVariants(productID int64, args map[string]string, page int) ([]Variant, bool, error)
+	GetVariant(productID, variantID int64) (*Variant, error)
+	GetVariantBySku(sku string) (*Variant, error)
+	CreateVariant(productID int64, variant *Variant) (*Variant, error)
+	UpdateVariant(productID int64, variant *Variant) (*Variant, error)
+	DeleteVariant(productID, variantID int64) error
+	UpdateVariants(variants []Variant) (VariantUpdateResults, error)
+	PatchVariants(patches []Patch) (VariantUpdateResults, error)
+	UpdateVariantBySku(payload *Variant) (*Variant, error)
+	UpdateVariantInventory(variantPayload *VariantInventory) (*Variant, error)
+	UpdateVariantSalePrice(variantSalePricePayload *VariantSalePrice) (*Variant, error)
+	AddProductToChannel(productId int64, channelId int64) (bool, error)
+	DeleteProductFromChannel(productId int64, channelId int64) (bool, error)
+	GetChannelAssignments(productIDs, channelIDs []int64) ([]ChannelAssignment, error)
+	CreateChannelAssignments(assignments []ChannelAssignment) (map[int]error, error)
+	AssignProductsToChannels(productIDs, channelIDs []int64) error
+	DeleteChannelAssignments(productIDs, channelIDs []int64) error
+	ReconcileProductChannels(productID int64, channelIDs []int64) (*ChannelReconcileResult, error)
+	GetChannelListings(channelID int64, productIDs []int64) ([]ChannelListing, error)
+	GetChannelListing(channelID, listingID int64) (*ChannelListing, error)
+	CreateChannelListings(channelID int64, listings []ChannelListing) ([]ChannelListing, error)
+	UpdateChannelListings(channelID int64, listings []ChannelListing) ([]ChannelListing, error)
+}
+
+// InventoryClient interface handles inventory locations, adjustments and per-location settings
+type InventoryClient interface {
+	GetAllInventoryLocations(args map[string]string) ([]InventoryLocation, error)
+	GetInventoryLocations(args map[string]string, page int) ([]InventoryLocation, bool, error)
+	CreateInventoryLocations(locations []InventoryLocation) ([]InventoryLocation, error)
+	UpdateInventoryLocations(locations []InventoryLocation) error
+	DeleteInventoryLocations(locationIDs []int64) error
+	SetInventory(reason string, adjustments []InventoryAdjustment) (map[int]error, error)
+	AdjustInventory(reason string, adjustments []InventoryAdjustment) (map[int]error, error)
+	GetAllInventoryItems(args map[string]string) ([]InventoryItem, error)
+	GetInventoryItems(args map[string]string, page int) ([]InventoryItem, bool, error)
+	GetAllLocationItems(locationID int64, args map[string]string) ([]LocationItem, error)
+	GetLocationItems(locationID int64, args map[string]string, page int) ([]LocationItem, bool, error)
+	UpdateLocationItemSettings(locationID int64, settings []InventoryItemSettings) (map[int]error, error)
+}
+
+// OptionClient interface handles product variant options, modifiers and their values
+type OptionClient interface {
+	GetVariantOptions(productID int64) ([]VariantOption, error)
+	GetVariantOption(productID, optionID int64) (*VariantOption, error)
+	CreateVariantOption(productID int64, option *VariantOption) (*VariantOption, error)
+	UpdateVariantOption(productID int64, option *VariantOption) (*VariantOption, error)
+	DeleteVariantOption(productID, optionID int64) error
+	GetOptionValues(productID, optionID int64) ([]OptionValue, error)
+	CreateOptionValue(productID, optionID int64, value *OptionValue) (*OptionValue, error)
+	UpdateOptionValue(productID, optionID int64, value *OptionValue) (*OptionValue, error)
+	DeleteOptionValue(productID, optionID, valueID int64) error
+	GetModifiers(productID int64) ([]Modifier, error)
+	GetModifier(productID, modifierID int64) (*Modifier, error)
+	CreateModifier(productID int64, modifier *Modifier) (*Modifier, error)
+	UpdateModifier(productID int64, modifier *Modifier) (*Modifier, error)
+	DeleteModifier(productID, modifierID int64) error
+	GetModifierValues(productID, modifierID int64) ([]OptionValue, error)
+	CreateModifierValue(productID, modifierID int64, value *OptionValue) (*OptionValue, error)
+	UpdateModifierValue(productID, modifierID int64, value *OptionValue) (*OptionValue, error)
+	DeleteModifierValue(productID, modifierID, valueID int64) error
+	GetVariantByOptions(productID int64, choices map[int64]int64) (*Variant, error)
+}
+
+// MetafieldClient interface handles the metafields of products, variants, categories, brands,
+// customers, orders, carts and channels
+type MetafieldClient interface {
+	GetAllMetafields(owner MetafieldOwner, args map[string]string) ([]Metafield, error)
+	GetMetafields(owner MetafieldOwner, args map[string]string, page int) ([]Metafield, bool, error)
+	GetMetafield(owner MetafieldOwner, metafieldID int64) (*Metafield, error)
+	GetMetafieldByKey(owner MetafieldOwner, namespace, key string) (*Metafield, error)
+	CreateMetafield(owner MetafieldOwner, metafield *Metafield) (*Metafield, error)
+	UpdateMetafield(owner MetafieldOwner, metafield *Metafield) (*Metafield, error)
+	DeleteMetafield(owner MetafieldOwner, metafieldID int64) error
+	UpsertMetafield(owner MetafieldOwner, metafield *Metafield) (*Metafield, error)
+	CreateMetafields(resourceType string, metafields []Metafield) ([]Metafield, error)
+	UpdateMetafields(resourceType string, metafields []Metafield) ([]Metafield, error)
+	DeleteMetafields(resourceType string, metafieldIDs []int64) error
 }
 
 // BlogClient interface handles blog-related requests
 type BlogClient interface {
-	GetAllPosts(context, xAuthToken string) ([]Post, error)
+	GetAllPosts() ([]Post, error)
 	GetPosts(page int) ([]Post, bool, error)
 }
 
 // CartClient interface handles cart and login related requests
 type CartClient interface {
 	CreateCart(items []LineItem) (*Cart, error)
+	CreateCartInChannel(channelID int, items []LineItem) (*Cart, error)
 	GetCart(cartID string) (*Cart, error)
 	CartAddItems(cartID string, items []LineItem) (*Cart, error)
 	CartEditItem(cartID string, item LineItem) (*Cart, error)
@@ -44,19 +258,73 @@ type CartClient interface {
 	DeleteCart(cartID string) error
 }
 
+// CheckoutClient interface handles checkout requests
+type CheckoutClient interface {
+	GetCheckout(checkoutID string) (*Checkout, error)
+	AddDiscountToCheckout(checkoutID string, discountAmount float64, discountName string) (*Cart, error)
+}
+
+// CustomerClient interface handles customer accounts
 type CustomerClient interface {
 	ValidateCredentials(email, password string) (int64, error)
+	ValidateCredentialsInChannel(channelID int, email, password string) (int64, error)
 	CreateAccount(customer *CreateAccountPayload) (*Customer, error)
 	CustomerSetFormFields(customerID int64, formFields []FormField) error
 	CustomerGetFormFields(customerID int64) ([]FormField, error)
 	GetCustomerByID(customerID int64) (*Customer, error)
 	GetCustomerByEmail(email string) (*Customer, error)
+	GetAllCustomers(args map[string]string) ([]Customer, error)
+	GetCustomers(args map[string]string, page int) ([]Customer, bool, error)
+	GetCustomerGroups() ([]CustomerGroup, error)
 	SaveAccount(customer *SaveAccountPayload) (*Customer, error)
+	PatchCustomer(customerID int64, patch Patch) (*Customer, error)
 }
 
+// AddressClient interface handles customer addresses
 type AddressClient interface {
 	CreateAddress(customerID int64, address *Address) (*Address, error)
 	UpdateAddress(customerID int64, address *Address) (*Address, error)
 	DeleteAddress(customerID int64, addressID int64) error
 	GetAddresses(customerID int64) ([]Address, error)
+	GetAddressPage(customerID int64, page int) ([]Address, bool, error)
+}
+
+// OrderClient interface handles orders and their subresources
+type OrderClient interface {
+	GetOrders(filters map[string]string) ([]Order, error)
+	GetOrder(orderID int64) (*Order, error)
+	GetOrderProducts(orderID int64) ([]OrderProduct, error)
+	GetOrderShippingAddresses(orderID int64) ([]OrderShippingAddress, error)
+	GetOrderCoupons(orderID int64) ([]OrderCoupon, error)
+	GetOrderTransactions(orderID int64) ([]OrderTransaction, error)
+	CreateOrderShipment(orderID int64, orderShipment *OrderShipment) (OrderShipmentResponse, error)
+}
+
+// CouponClient interface handles coupons
+type CouponClient interface {
+	CreateCoupon(coupon Coupon) (*Coupon, error)
+	GetCoupon(couponID int64) (*Coupon, error)
+	UpdateCoupon(couponID int64, coupon Coupon) (*Coupon, error)
+	DeleteCoupon(couponID int64) error
+	GetAllCoupons(args map[string]string) ([]Coupon, error)
+	GetCoupons(args map[string]string, page int) ([]Coupon, bool, error)
+}
+
+// WebhookClient interface handles webhook subscriptions
+type WebhookClient interface {
+	GetWebhooks() ([]Webhook, error)
+	CreateWebhook(scope, destination string, headers map[string]string) (int64, error)
+}
+
+// ContentClient interface handles scripts, widget templates and themes
+type ContentClient interface {
+	CreateScript(s *Script) (*Script, error)
+	GetScriptByID(uuid string) (*Script, error)
+	GetScripts() ([]Script, error)
+	CreateWidgetTemplate(pt *PageBuilderTemplate) (*PageBuilderTemplate, error)
+	GetWidgetTemplates() ([]PageBuilderTemplate, error)
+	DeleteWidgetTemplate(uuid string) error
+	GetThemes() ([]Theme, error)
+	GetThemeConfig(uuid string) (*ThemeConfig, error)
+	GetActiveThemeConfig() (*ThemeConfig, error)
 }
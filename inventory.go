@@ -0,0 +1,299 @@
+package bigcommerce
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// inventoryBatchSize is the maximum number of items BigCommerce accepts in an inventory request
+const inventoryBatchSize = 2000
+
+// DefaultLocationID is the location created with the store, it holds the inventory set through
+// the catalog API
+const DefaultLocationID = 1
+
+// InventoryLocation is a place holding inventory like a warehouse or a store, TypeID is PHYSICAL
+// or VIRTUAL
+type InventoryLocation struct {
+	ID                      int64                     `json:"id,omitempty"`
+	Code                    string                    `json:"code"`
+	Label                   string                    `json:"label"`
+	Description             string                    `json:"description,omitempty"`
+	ManagedByExternalSource bool                      `json:"managed_by_external_source"`
+	TypeID                  string                    `json:"type_id,omitempty"`
+	Enabled                 bool                      `json:"enabled"`
+	StorefrontVisibility    bool                      `json:"storefront_visibility"`
+	TimeZone                string                    `json:"time_zone,omitempty"`
+	Address                 *InventoryLocationAddress `json:"address,omitempty"`
+}
+
+// InventoryLocationAddress is the address of a physical location
+type InventoryLocationAddress struct {
+	Address1    string `json:"address1"`
+	Address2    string `json:"address2,omitempty"`
+	City        string `json:"city"`
+	State       string `json:"state"`
+	Zip         string `json:"zip"`
+	CountryCode string `json:"country_code"`
+	Email       string `json:"email,omitempty"`
+	Phone       string `json:"phone,omitempty"`
+}
+
+// InventoryIdentity identifies an item by Sku, VariantID or ProductID, ProductID only for
+// products without variants
+type InventoryIdentity struct {
+	Sku       string `json:"sku,omitempty"`
+	VariantID int64  `json:"variant_id,omitempty"`
+	ProductID int64  `json:"product_id,omitempty"`
+}
+
+// InventoryAdjustment sets the quantity of an item in a location, or changes it by Quantity
+// in relative adjustments
+type InventoryAdjustment struct {
+	LocationID int64 `json:"location_id"`
+	InventoryIdentity
+	Quantity int `json:"quantity"`
+}
+
+// InventorySettings are the settings of an item in a location. SafetyStock is held back from
+// the quantity available to sell, WarningLevel triggers the low stock warning.
+type InventorySettings struct {
+	SafetyStock      int    `json:"safety_stock"`
+	IsInStock        bool   `json:"is_in_stock"`
+	WarningLevel     int    `json:"warning_level"`
+	BinPickingNumber string `json:"bin_picking_number"`
+}
+
+// InventoryItemSettings are the settings of an item to change with UpdateLocationItemSettings.
+// Only the fields of Settings are sent, zero values included, e.g.
+// Patch{"safety_stock": 0, "bin_picking_number": "A-12"}
+type InventoryItemSettings struct {
+	Identity InventoryIdentity
+	Settings Patch
+}
+
+// LocationInventory is the inventory of an item in a location
+type LocationInventory struct {
+	LocationID           int64             `json:"location_id"`
+	LocationCode         string            `json:"location_code"`
+	LocationName         string            `json:"location_name"`
+	AvailableToSell      int               `json:"available_to_sell"`
+	TotalInventoryOnhand int               `json:"total_inventory_onhand"`
+	Settings             InventorySettings `json:"settings"`
+}
+
+// InventoryItem is the inventory of an item in all the locations
+type InventoryItem struct {
+	Identity  InventoryIdentity   `json:"identity"`
+	Locations []LocationInventory `json:"locations"`
+}
+
+// LocationItem is the inventory of an item in one location
+type LocationItem struct {
+	Identity             InventoryIdentity `json:"identity"`
+	AvailableToSell      int               `json:"available_to_sell"`
+	TotalInventoryOnhand int               `json:"total_inventory_onhand"`
+	Settings             InventorySettings `json:"settings"`
+}
+
+// GetAllInventoryLocations returns all the inventory locations
+// args is a map of arguments to pass to the API, e.g. location_id:in, location_code:in or is_active
+func (bc *Client) GetAllInventoryLocations(args map[string]string) ([]InventoryLocation, error) {
+	ret := []InventoryLocation{}
+	page := 1
+	more := true
+	for more {
+		var ls []InventoryLocation
+		var err error
+		ls, more, err = bc.GetInventoryLocations(args, page)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, ls...)
+		page++
+	}
+	return ret, nil
+}
+
+// GetInventoryLocations returns a page of inventory locations, and whether there are more pages
+func (bc *Client) GetInventoryLocations(args map[string]string, page int) ([]InventoryLocation, bool, error) {
+	var ls []InventoryLocation
+	more, err := bc.getPage(withArgs("/v3/inventory/locations", args), page, &ls)
+	if err != nil {
+		return nil, false, err
+	}
+	return ls, more, nil
+}
+
+// CreateInventoryLocations creates locations and returns them with their IDs. The API only returns a
+// transaction ID, so the locations are read back by code, which must be unique.
+func (bc *Client) CreateInventoryLocations(locations []InventoryLocation) ([]InventoryLocation, error) {
+	payload := []InventoryLocation{}
+	codes := []string{}
+	for _, l := range locations {
+		if l.Code == "" {
+			return nil, fmt.Errorf("location %s has no code", l.Label)
+		}
+		l.ID = 0
+		payload = append(payload, l)
+		codes = append(codes, l.Code)
+	}
+	err := bc.sendJSON(http.MethodPost, "/v3/inventory/locations", payload, nil)
+	if err != nil && err != ErrNoContent {
+		return nil, err
+	}
+	return bc.GetAllInventoryLocations(map[string]string{"location_code:in": strings.Join(codes, ",")})
+}
+
+// UpdateInventoryLocations updates locations by ID
+func (bc *Client) UpdateInventoryLocations(locations []InventoryLocation) error {
+	for _, l := range locations {
+		if l.ID == 0 {
+			return fmt.Errorf("location %s has no ID", l.Code)
+		}
+	}
+	err := bc.sendJSON(http.MethodPut, "/v3/inventory/locations", locations, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// DeleteInventoryLocations deletes locations, the default location can't be deleted
+func (bc *Client) DeleteInventoryLocations(locationIDs []int64) error {
+	if len(locationIDs) == 0 {
+		return errors.New("no locations to delete")
+	}
+	err := bc.sendJSON(http.MethodDelete, "/v3/inventory/locations?location_id:in="+joinIDs(locationIDs), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// SetInventory sets the quantity of items in locations, in batches of 2000. reason is shown in
+// the inventory history. It returns the errors of the adjustments that were not applied by
+// position in adjustments, and an error if any failed. The API rejects a whole batch when one
+// of its items is invalid.
+func (bc *Client) SetInventory(reason string, adjustments []InventoryAdjustment) (map[int]error, error) {
+	return bc.adjustInventory(http.MethodPut, "/v3/inventory/adjustments/absolute", reason, adjustments)
+}
+
+// AdjustInventory changes the quantity of items in locations by the quantity of the adjustments,
+// like SetInventory
+func (bc *Client) AdjustInventory(reason string, adjustments []InventoryAdjustment) (map[int]error, error) {
+	return bc.adjustInventory(http.MethodPost, "/v3/inventory/adjustments/relative", reason, adjustments)
+}
+
+func (bc *Client) adjustInventory(method, path, reason string, adjustments []InventoryAdjustment) (map[int]error, error) {
+	for _, a := range adjustments {
+		if a.LocationID == 0 {
+			return nil, errors.New("inventory adjustment has no location ID")
+		}
+	}
+	return bc.inventoryBatches(method, path, len(adjustments), func(start, end int) interface{} {
+		return map[string]interface{}{"reason": reason, "items": adjustments[start:end]}
+	})
+}
+
+// GetAllInventoryItems returns the inventory of items in all the locations
+// args is a map of arguments to pass to the API, e.g. sku:in, variant_id:in, product_id:in or location_id:in
+func (bc *Client) GetAllInventoryItems(args map[string]string) ([]InventoryItem, error) {
+	ret := []InventoryItem{}
+	page := 1
+	more := true
+	for more {
+		var is []InventoryItem
+		var err error
+		is, more, err = bc.GetInventoryItems(args, page)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, is...)
+		page++
+	}
+	return ret, nil
+}
+
+// GetInventoryItems returns a page of the inventory of items, and whether there are more pages
+func (bc *Client) GetInventoryItems(args map[string]string, page int) ([]InventoryItem, bool, error) {
+	var is []InventoryItem
+	more, err := bc.getPage(withArgs("/v3/inventory/items", args), page, &is)
+	if err != nil {
+		return nil, false, err
+	}
+	return is, more, nil
+}
+
+// GetAllLocationItems returns the inventory of items in a location
+// args is a map of arguments to pass to the API, e.g. sku:in, variant_id:in or product_id:in
+func (bc *Client) GetAllLocationItems(locationID int64, args map[string]string) ([]LocationItem, error) {
+	ret := []LocationItem{}
+	page := 1
+	more := true
+	for more {
+		var is []LocationItem
+		var err error
+		is, more, err = bc.GetLocationItems(locationID, args, page)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, is...)
+		page++
+	}
+	return ret, nil
+}
+
+// GetLocationItems returns a page of the inventory of items in a location, and whether there are more pages
+func (bc *Client) GetLocationItems(locationID int64, args map[string]string, page int) ([]LocationItem, bool, error) {
+	var is []LocationItem
+	more, err := bc.getPage(withArgs(fmt.Sprintf("/v3/inventory/locations/%d/items", locationID), args), page, &is)
+	if err != nil {
+		return nil, false, err
+	}
+	return is, more, nil
+}
+
+// UpdateLocationItemSettings updates the safety stock, in stock flag, warning level and bin picking
+// number of items in a location, the settings missing from a patch are kept. Errors are returned
+// like SetInventory.
+func (bc *Client) UpdateLocationItemSettings(locationID int64, settings []InventoryItemSettings) (map[int]error, error) {
+	path := fmt.Sprintf("/v3/inventory/locations/%d/items", locationID)
+	return bc.inventoryBatches(http.MethodPut, path, len(settings), func(start, end int) interface{} {
+		payload := []Patch{}
+		for _, st := range settings[start:end] {
+			p := Patch{}
+			for k, v := range st.Settings {
+				p[k] = v
+			}
+			p["identity"] = st.Identity
+			payload = append(payload, p)
+		}
+		return map[string]interface{}{"settings": payload}
+	})
+}
+
+// inventoryBatches sends n items in batches, payload returns the body of the items from start to end
+func (bc *Client) inventoryBatches(method, path string, n int, payload func(start, end int) interface{}) (map[int]error, error) {
+	failed := map[int]error{}
+	for start := 0; start < n; start += inventoryBatchSize {
+		end := start + inventoryBatchSize
+		if end > n {
+			end = n
+		}
+		itemErrs, err := bc.sendBatch(method, path, payload(start, end), end-start, nil)
+		for i := start; i < end; i++ {
+			if e := itemErrs[i-start]; e != nil {
+				failed[i] = e
+			} else if err != nil {
+				failed[i] = err
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return failed, fmt.Errorf("%d of %d inventory items failed", len(failed), n)
+	}
+	return failed, nil
+}
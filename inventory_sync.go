@@ -0,0 +1,369 @@
+package bigcommerce
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// skuIndexTTL is the default time a SkuIndex is kept before it is rebuilt
+const skuIndexTTL = 15 * time.Minute
+
+// inventorySyncBatchSize is the default number of records read and adjusted at once
+const inventorySyncBatchSize = 250
+
+// inventorySyncInterval is the default minimum time between two batches of an InventorySync
+const inventorySyncInterval = 500 * time.Millisecond
+
+// SkuIndex resolves SKUs to variant and product IDs. It is built from all the variants of the
+// catalog on first use and rebuilt after TTL, 0 keeps it until Invalidate is called.
+type SkuIndex struct {
+	Client CatalogClient
+	TTL    time.Duration
+
+	mu     sync.Mutex
+	bySku  map[string]InventoryIdentity
+	loaded time.Time
+}
+
+// NewSkuIndex returns a SKU index of the catalog of client
+func NewSkuIndex(client CatalogClient) *SkuIndex {
+	return &SkuIndex{Client: client, TTL: skuIndexTTL}
+}
+
+// load builds the index unless it is still valid
+func (x *SkuIndex) load() error {
+	if x.bySku != nil && (x.TTL == 0 || time.Since(x.loaded) < x.TTL) {
+		return nil
+	}
+	args := map[string]string{"include_fields": "sku,product_id", "limit": "250"}
+	bySku := map[string]InventoryIdentity{}
+	page := 1
+	more := true
+	for more {
+		var vs []Variant
+		var err error
+		vs, more, err = x.Client.GetVariants(args, page)
+		if err == ErrNoContent {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		for _, v := range vs {
+			if v.Sku != "" {
+				bySku[v.Sku] = InventoryIdentity{Sku: v.Sku, VariantID: v.ID, ProductID: v.ProductID}
+			}
+		}
+		page++
+	}
+	x.bySku = bySku
+	x.loaded = time.Now()
+	return nil
+}
+
+// Lookup returns the identity of the variant with a SKU, and whether there is one
+func (x *SkuIndex) Lookup(sku string) (InventoryIdentity, bool, error) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	err := x.load()
+	if err != nil {
+		return InventoryIdentity{}, false, err
+	}
+	id, ok := x.bySku[sku]
+	return id, ok, nil
+}
+
+// builtBefore returns whether the index was built before t
+func (x *SkuIndex) builtBefore(t time.Time) bool {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	return x.bySku != nil && x.loaded.Before(t)
+}
+
+// Invalidate drops the index, it is built again on the next lookup
+func (x *SkuIndex) Invalidate() {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.bySku = nil
+}
+
+// InventoryRecord is the quantity of a SKU in a location according to the source of truth,
+// LocationID 0 is the default location
+type InventoryRecord struct {
+	Sku        string
+	LocationID int64
+	Quantity   int
+}
+
+// Statuses of the records of an inventory sync
+const (
+	InventoryChanged    = "changed"
+	InventoryUnchanged  = "unchanged"
+	InventoryUnknownSku = "unknown_sku"
+	InventoryFailed     = "failed"
+)
+
+// InventorySyncItem is the outcome of a record. Previous is the quantity in BigCommerce before
+// the sync, Err is set for failed records.
+type InventorySyncItem struct {
+	Record   InventoryRecord
+	Identity InventoryIdentity
+	Previous int
+	Status   string
+	Err      error
+}
+
+// InventorySyncReport lists the records of a sync by status
+type InventorySyncReport struct {
+	Changed    []InventorySyncItem
+	Unchanged  []InventorySyncItem
+	UnknownSku []InventorySyncItem
+	Failed     []InventorySyncItem
+}
+
+func (r *InventorySyncReport) add(item InventorySyncItem) {
+	switch item.Status {
+	case InventoryChanged:
+		r.Changed = append(r.Changed, item)
+	case InventoryUnchanged:
+		r.Unchanged = append(r.Unchanged, item)
+	case InventoryUnknownSku:
+		r.UnknownSku = append(r.UnknownSku, item)
+	default:
+		r.Failed = append(r.Failed, item)
+	}
+}
+
+// InventorySync sets the inventory of BigCommerce to the quantities of an external source of
+// truth. Records are handled in batches of BatchSize: the current quantities of the batch are
+// read, and the differences applied as relative adjustments, so sales made in the meantime are
+// kept. Batches start at least Interval apart to stay under the rate limit, also across
+// concurrent calls of Sync.
+type InventorySync struct {
+	Inventory InventoryClient
+	Index     *SkuIndex
+	// Reason is shown in the inventory history of BigCommerce
+	Reason    string
+	BatchSize int
+	Interval  time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+// NewInventorySync returns an inventory sync resolving SKUs in the catalog of catalog
+func NewInventorySync(catalog CatalogClient, inventory InventoryClient) *InventorySync {
+	return &InventorySync{
+		Inventory: inventory,
+		Index:     NewSkuIndex(catalog),
+		Reason:    "Inventory sync",
+		BatchSize: inventorySyncBatchSize,
+		Interval:  inventorySyncInterval,
+	}
+}
+
+// inventoryKey identifies the stock of a variant in a location
+type inventoryKey struct {
+	variantID  int64
+	locationID int64
+}
+
+// Sync applies the records until the channel is closed or the context is cancelled, and returns
+// the report of the records handled. A record repeating the SKU and location of a record of the
+// current batch starts a new batch, so the last one wins. Records of unknown locations fail
+// before they are sent, as the API rejects a whole batch for one invalid item. On the first
+// unknown SKU the SKU index is rebuilt, unless it was built during the sync, to find variants
+// created since. Errors reading the locations or building the SKU index stop the sync, errors of
+// a batch only fail its records. When the context is cancelled the records of the pending batch
+// fail with the context error.
+func (s *InventorySync) Sync(ctx context.Context, records <-chan InventoryRecord) (*InventorySyncReport, error) {
+	report := &InventorySyncReport{}
+	started := time.Now()
+	locations, err := s.Inventory.GetAllInventoryLocations(nil)
+	if err != nil {
+		return report, err
+	}
+	known := map[int64]bool{}
+	for _, l := range locations {
+		known[l.ID] = true
+	}
+	size := s.BatchSize
+	if size <= 0 {
+		size = inventorySyncBatchSize
+	}
+	batch := []InventorySyncItem{}
+	keys := map[inventoryKey]bool{}
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		err := s.wait(ctx)
+		items := batch
+		if err != nil {
+			for i := range items {
+				items[i].Status, items[i].Err = InventoryFailed, err
+			}
+		} else {
+			items = s.apply(batch)
+		}
+		for _, item := range items {
+			report.add(item)
+		}
+		batch = []InventorySyncItem{}
+		keys = map[inventoryKey]bool{}
+		return err
+	}
+	refreshed := false
+	for {
+		var r InventoryRecord
+		var ok bool
+		select {
+		case <-ctx.Done():
+			for _, item := range batch {
+				item.Status, item.Err = InventoryFailed, ctx.Err()
+				report.add(item)
+			}
+			return report, ctx.Err()
+		case r, ok = <-records:
+		}
+		if !ok {
+			return report, flush()
+		}
+		if r.LocationID == 0 {
+			r.LocationID = DefaultLocationID
+		}
+		id, found, err := s.Index.Lookup(r.Sku)
+		if err == nil && !found && !refreshed {
+			refreshed = true
+			if s.Index.builtBefore(started) {
+				s.Index.Invalidate()
+				id, found, err = s.Index.Lookup(r.Sku)
+			}
+		}
+		if err != nil {
+			return report, err
+		}
+		if !found {
+			report.add(InventorySyncItem{Record: r, Status: InventoryUnknownSku})
+			continue
+		}
+		if !known[r.LocationID] {
+			report.add(InventorySyncItem{
+				Record:   r,
+				Identity: id,
+				Status:   InventoryFailed,
+				Err:      fmt.Errorf("location %d not found", r.LocationID),
+			})
+			continue
+		}
+		key := inventoryKey{id.VariantID, r.LocationID}
+		if keys[key] || len(batch) >= size {
+			err = flush()
+			if err != nil {
+				report.add(InventorySyncItem{Record: r, Identity: id, Status: InventoryFailed, Err: err})
+				return report, err
+			}
+		}
+		keys[key] = true
+		batch = append(batch, InventorySyncItem{Record: r, Identity: id})
+	}
+}
+
+// SyncRecords applies a list of records, like Sync
+func (s *InventorySync) SyncRecords(ctx context.Context, records []InventoryRecord) (*InventorySyncReport, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	ch := make(chan InventoryRecord)
+	go func() {
+		defer close(ch)
+		for _, r := range records {
+			select {
+			case ch <- r:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return s.Sync(ctx, ch)
+}
+
+// wait sleeps until Interval has passed since the previous batch, concurrent calls wait in turn
+func (s *InventorySync) wait(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if d := s.Interval - time.Since(s.last); !s.last.IsZero() && d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	s.last = time.Now()
+	return nil
+}
+
+// apply reads the current quantities of a batch and adjusts those that differ
+func (s *InventorySync) apply(batch []InventorySyncItem) []InventorySyncItem {
+	fail := func(err error) []InventorySyncItem {
+		for i := range batch {
+			batch[i].Status, batch[i].Err = InventoryFailed, err
+		}
+		return batch
+	}
+	variantIDs := []string{}
+	locationIDs := []string{}
+	for _, item := range batch {
+		variantIDs = append(variantIDs, strconv.FormatInt(item.Identity.VariantID, 10))
+		locationIDs = append(locationIDs, strconv.FormatInt(item.Record.LocationID, 10))
+	}
+	current, err := s.Inventory.GetAllInventoryItems(map[string]string{
+		"variant_id:in":  strings.Join(variantIDs, ","),
+		"location_id:in": strings.Join(locationIDs, ","),
+		"limit":          "250",
+	})
+	if err != nil {
+		return fail(err)
+	}
+	onhand := map[inventoryKey]int{}
+	for _, item := range current {
+		for _, l := range item.Locations {
+			onhand[inventoryKey{item.Identity.VariantID, l.LocationID}] = l.TotalInventoryOnhand
+		}
+	}
+	adjustments := []InventoryAdjustment{}
+	adjusted := []int{}
+	for i, item := range batch {
+		batch[i].Previous = onhand[inventoryKey{item.Identity.VariantID, item.Record.LocationID}]
+		delta := item.Record.Quantity - batch[i].Previous
+		if delta == 0 {
+			batch[i].Status = InventoryUnchanged
+			continue
+		}
+		batch[i].Status = InventoryChanged
+		adjustments = append(adjustments, InventoryAdjustment{
+			LocationID:        item.Record.LocationID,
+			InventoryIdentity: InventoryIdentity{VariantID: item.Identity.VariantID},
+			Quantity:          delta,
+		})
+		adjusted = append(adjusted, i)
+	}
+	if len(adjustments) == 0 {
+		return batch
+	}
+	failed, err := s.Inventory.AdjustInventory(s.Reason, adjustments)
+	if err != nil && len(failed) == 0 {
+		for _, i := range adjusted {
+			batch[i].Status, batch[i].Err = InventoryFailed, err
+		}
+	}
+	for n, e := range failed {
+		batch[adjusted[n]].Status, batch[adjusted[n]].Err = InventoryFailed, e
+	}
+	return batch
+}
@@ -0,0 +1,188 @@
+package bigcommerce_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+	"github.com/mvalenziano/bigcommerce-api-go/bctest"
+)
+
+func TestInventorySync(t *testing.T) {
+	tests := []struct {
+		name    string
+		records []bigcommerce.InventoryRecord
+		fault   *bctest.Fault
+		// want lists the records of each bucket as "SKU@location:previous"
+		want map[string][]string
+		// levels are the inventory levels of the products after the sync, summed over locations
+		levels map[string]int
+	}{
+		{
+			name:    "changed",
+			records: []bigcommerce.InventoryRecord{{Sku: "A", Quantity: 8}},
+			want:    map[string][]string{bigcommerce.InventoryChanged: {"A@1:5"}},
+			levels:  map[string]int{"A": 8, "B": 3},
+		},
+		{
+			name:    "unchanged",
+			records: []bigcommerce.InventoryRecord{{Sku: "A", Quantity: 5}},
+			want:    map[string][]string{bigcommerce.InventoryUnchanged: {"A@1:5"}},
+			levels:  map[string]int{"A": 5, "B": 3},
+		},
+		{
+			name:    "unknown SKU",
+			records: []bigcommerce.InventoryRecord{{Sku: "Z", Quantity: 1}, {Sku: "B", Quantity: 0}},
+			want: map[string][]string{
+				bigcommerce.InventoryUnknownSku: {"Z@1:0"},
+				bigcommerce.InventoryChanged:    {"B@1:3"},
+			},
+			levels: map[string]int{"A": 5, "B": 0},
+		},
+		{
+			name:    "unknown location",
+			records: []bigcommerce.InventoryRecord{{Sku: "A", LocationID: 42, Quantity: 1}, {Sku: "B", Quantity: 4}},
+			want: map[string][]string{
+				bigcommerce.InventoryFailed:  {"A@42:0"},
+				bigcommerce.InventoryChanged: {"B@1:3"},
+			},
+			levels: map[string]int{"A": 5, "B": 4},
+		},
+		{
+			name:    "second location",
+			records: []bigcommerce.InventoryRecord{{Sku: "A", LocationID: 2, Quantity: 4}, {Sku: "A", Quantity: 5}},
+			want: map[string][]string{
+				bigcommerce.InventoryChanged:   {"A@2:0"},
+				bigcommerce.InventoryUnchanged: {"A@1:5"},
+			},
+			levels: map[string]int{"A": 9, "B": 3},
+		},
+		{
+			name:    "repeated SKU applied in order",
+			records: []bigcommerce.InventoryRecord{{Sku: "A", Quantity: 7}, {Sku: "B", Quantity: 1}, {Sku: "A", Quantity: 2}},
+			want:    map[string][]string{bigcommerce.InventoryChanged: {"A@1:5", "B@1:3", "A@1:7"}},
+			levels:  map[string]int{"A": 2, "B": 1},
+		},
+		{
+			name:    "failed adjustment",
+			records: []bigcommerce.InventoryRecord{{Sku: "A", Quantity: 7}, {Sku: "B", Quantity: 3}},
+			fault:   &bctest.Fault{Path: "/v3/inventory/adjustments", Status: http.StatusInternalServerError},
+			want: map[string][]string{
+				bigcommerce.InventoryFailed:    {"A@1:5"},
+				bigcommerce.InventoryUnchanged: {"B@1:3"},
+			},
+			levels: map[string]int{"A": 5, "B": 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := bctest.NewServer()
+			s.AddProduct(bigcommerce.Product{Name: "A", Sku: "A", InventoryTracking: "product", InventoryLevel: 5})
+			s.AddProduct(bigcommerce.Product{Name: "B", Sku: "B", InventoryTracking: "product", InventoryLevel: 3})
+			s.AddLocation(bigcommerce.InventoryLocation{ID: 2, Code: "WH2", Label: "Warehouse 2", Enabled: true})
+			if tt.fault != nil {
+				s.InjectFault(*tt.fault)
+			}
+			bc := s.Client()
+			sync := bigcommerce.NewInventorySync(bc, bc)
+			sync.Interval = 0
+
+			report, err := sync.SyncRecords(context.Background(), tt.records)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := map[string][]string{}
+			for _, items := range [][]bigcommerce.InventorySyncItem{report.Changed, report.Unchanged, report.UnknownSku, report.Failed} {
+				for _, item := range items {
+					got[item.Status] = append(got[item.Status], fmt.Sprintf("%s@%d:%d", item.Record.Sku, item.Record.LocationID, item.Previous))
+					if (item.Status == bigcommerce.InventoryFailed) != (item.Err != nil) {
+						t.Errorf("%s is %s with error %v", item.Record.Sku, item.Status, item.Err)
+					}
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("report %v, want %v", got, tt.want)
+			}
+			for sku, want := range tt.levels {
+				p, err := bc.GetProductBySku(sku)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if p.InventoryLevel != want {
+					t.Errorf("inventory of %s is %d, want %d", sku, p.InventoryLevel, want)
+				}
+			}
+		})
+	}
+}
+
+func TestInventorySyncCancelled(t *testing.T) {
+	s := bctest.NewServer()
+	s.AddProduct(bigcommerce.Product{Name: "A", Sku: "A", InventoryTracking: "product", InventoryLevel: 5})
+	bc := s.Client()
+	sync := bigcommerce.NewInventorySync(bc, bc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	records := make(chan bigcommerce.InventoryRecord)
+	done := make(chan struct{})
+	var report *bigcommerce.InventorySyncReport
+	var err error
+	go func() {
+		report, err = sync.Sync(ctx, records)
+		close(done)
+	}()
+	// once received the record waits in the batch until the channel is closed
+	records <- bigcommerce.InventoryRecord{Sku: "A", Quantity: 8}
+	cancel()
+	<-done
+	if err != context.Canceled {
+		t.Fatalf("error %v, want %v", err, context.Canceled)
+	}
+	if len(report.Failed) != 1 || report.Failed[0].Err != context.Canceled {
+		t.Fatalf("failed %v, want the pending record failed with the context error", report.Failed)
+	}
+}
+
+func TestInventorySyncRefreshesIndexOnce(t *testing.T) {
+	s := bctest.NewServer()
+	s.AddProduct(bigcommerce.Product{Name: "A", Sku: "A", InventoryTracking: "product", InventoryLevel: 5})
+	bc := s.Client()
+	sync := bigcommerce.NewInventorySync(bc, bc)
+	sync.Interval = 0
+	variantLists := func() int {
+		n := 0
+		for _, r := range s.Requests() {
+			if r.Method == http.MethodGet && r.Path == "/v3/catalog/variants" {
+				n++
+			}
+		}
+		return n
+	}
+
+	// the index built during a sync is not rebuilt for its unknown SKUs
+	report, err := sync.SyncRecords(context.Background(), []bigcommerce.InventoryRecord{{Sku: "A", Quantity: 6}, {Sku: "C", Quantity: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.UnknownSku) != 1 || variantLists() != 1 {
+		t.Fatalf("unknown %v after %d variant lists, want C after 1", report.UnknownSku, variantLists())
+	}
+
+	// a product created since is found by rebuilding the index once
+	s.AddProduct(bigcommerce.Product{Name: "C", Sku: "C", InventoryTracking: "product", InventoryLevel: 0})
+	report, err = sync.SyncRecords(context.Background(), []bigcommerce.InventoryRecord{
+		{Sku: "C", Quantity: 1}, {Sku: "D", Quantity: 1}, {Sku: "E", Quantity: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Changed) != 1 || report.Changed[0].Record.Sku != "C" {
+		t.Errorf("changed %v, want C", report.Changed)
+	}
+	if len(report.UnknownSku) != 2 || variantLists() != 2 {
+		t.Errorf("unknown %v after %d variant lists, want D and E after 2", report.UnknownSku, variantLists())
+	}
+}
@@ -0,0 +1,390 @@
+package bigcommerce
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strconv"
+	"strings"
+)
+
+// Metafield resource types
+const (
+	MetafieldProduct  = "product"
+	MetafieldVariant  = "variant"
+	MetafieldCategory = "category"
+	MetafieldBrand    = "brand"
+	MetafieldCustomer = "customer"
+	MetafieldOrder    = "order"
+	MetafieldCart     = "cart"
+	MetafieldChannel  = "channel"
+)
+
+// metafieldBatchPaths are the batch endpoints by resource type
+var metafieldBatchPaths = map[string]string{
+	MetafieldProduct:  "/v3/catalog/products/metafields",
+	MetafieldVariant:  "/v3/catalog/variants/metafields",
+	MetafieldCategory: "/v3/catalog/categories/metafields",
+	MetafieldBrand:    "/v3/catalog/brands/metafields",
+	MetafieldCustomer: "/v3/customers/metafields",
+	MetafieldOrder:    "/v3/orders/metafields",
+	MetafieldCart:     "/v3/carts/metafields",
+	MetafieldChannel:  "/v3/channels/metafields",
+}
+
+// MetafieldOwner is the resource metafields belong to, see the constructors below
+type MetafieldOwner struct {
+	Type string
+	ID   string
+	// ProductID is the product of a variant
+	ProductID int64
+}
+
+// ProductMetafieldOwner returns the owner of the metafields of a product
+func ProductMetafieldOwner(productID int64) MetafieldOwner {
+	return MetafieldOwner{Type: MetafieldProduct, ID: strconv.FormatInt(productID, 10)}
+}
+
+// VariantMetafieldOwner returns the owner of the metafields of a variant
+func VariantMetafieldOwner(productID, variantID int64) MetafieldOwner {
+	return MetafieldOwner{Type: MetafieldVariant, ID: strconv.FormatInt(variantID, 10), ProductID: productID}
+}
+
+// CategoryMetafieldOwner returns the owner of the metafields of a category
+func CategoryMetafieldOwner(categoryID int64) MetafieldOwner {
+	return MetafieldOwner{Type: MetafieldCategory, ID: strconv.FormatInt(categoryID, 10)}
+}
+
+// BrandMetafieldOwner returns the owner of the metafields of a brand
+func BrandMetafieldOwner(brandID int64) MetafieldOwner {
+	return MetafieldOwner{Type: MetafieldBrand, ID: strconv.FormatInt(brandID, 10)}
+}
+
+// CustomerMetafieldOwner returns the owner of the metafields of a customer
+func CustomerMetafieldOwner(customerID int64) MetafieldOwner {
+	return MetafieldOwner{Type: MetafieldCustomer, ID: strconv.FormatInt(customerID, 10)}
+}
+
+// OrderMetafieldOwner returns the owner of the metafields of an order
+func OrderMetafieldOwner(orderID int64) MetafieldOwner {
+	return MetafieldOwner{Type: MetafieldOrder, ID: strconv.FormatInt(orderID, 10)}
+}
+
+// CartMetafieldOwner returns the owner of the metafields of a cart
+func CartMetafieldOwner(cartID string) MetafieldOwner {
+	return MetafieldOwner{Type: MetafieldCart, ID: cartID}
+}
+
+// ChannelMetafieldOwner returns the owner of the metafields of a channel
+func ChannelMetafieldOwner(channelID int64) MetafieldOwner {
+	return MetafieldOwner{Type: MetafieldChannel, ID: strconv.FormatInt(channelID, 10)}
+}
+
+// path returns the metafields path of the owner
+func (o MetafieldOwner) path() (string, error) {
+	if o.ID == "" || o.ID == "0" {
+		return "", errors.New("metafield owner has no ID")
+	}
+	switch o.Type {
+	case MetafieldProduct:
+		return "/v3/catalog/products/" + o.ID + "/metafields", nil
+	case MetafieldVariant:
+		if o.ProductID == 0 {
+			return "", errors.New("variant metafield owner has no product ID")
+		}
+		return "/v3/catalog/products/" + strconv.FormatInt(o.ProductID, 10) + "/variants/" + o.ID + "/metafields", nil
+	case MetafieldCategory:
+		return "/v3/catalog/categories/" + o.ID + "/metafields", nil
+	case MetafieldBrand:
+		return "/v3/catalog/brands/" + o.ID + "/metafields", nil
+	case MetafieldCustomer:
+		return "/v3/customers/" + o.ID + "/metafields", nil
+	case MetafieldOrder:
+		return "/v3/orders/" + o.ID + "/metafields", nil
+	case MetafieldCart:
+		return "/v3/carts/" + o.ID + "/metafields", nil
+	case MetafieldChannel:
+		return "/v3/channels/" + o.ID + "/metafields", nil
+	}
+	return "", fmt.Errorf("unknown metafield resource type %s", o.Type)
+}
+
+// UnmarshalJSON accepts numeric and string resource IDs, string IDs like cart IDs are set in ResourceKey
+func (m *Metafield) UnmarshalJSON(b []byte) error {
+	type metafield Metafield
+	var aux struct {
+		metafield
+		ResourceID json.RawMessage `json:"resource_id,omitempty"`
+	}
+	err := json.Unmarshal(b, &aux)
+	if err != nil {
+		return err
+	}
+	*m = Metafield(aux.metafield)
+	if len(aux.ResourceID) == 0 || string(aux.ResourceID) == "null" {
+		return nil
+	}
+	var s string
+	if json.Unmarshal(aux.ResourceID, &s) == nil {
+		m.ResourceKey = s
+		m.ResourceID, _ = strconv.ParseInt(s, 10, 64)
+		return nil
+	}
+	return json.Unmarshal(aux.ResourceID, &m.ResourceID)
+}
+
+// payload returns the writable fields of a metafield, with the resource ID for batch requests.
+// An empty permission set is left out, so updates keep the one of the metafield.
+func (m Metafield) payload(withResource bool) Patch {
+	p := Patch{
+		"namespace": m.Namespace,
+		"key":       m.Key,
+		"value":     m.Value,
+	}
+	if m.PermissionSet != "" {
+		p["permission_set"] = m.PermissionSet
+	}
+	if m.Description != "" {
+		p["description"] = m.Description
+	}
+	if m.ID != 0 {
+		p["id"] = m.ID
+	}
+	if withResource {
+		if m.ResourceKey != "" {
+			p["resource_id"] = m.ResourceKey
+		} else {
+			p["resource_id"] = m.ResourceID
+		}
+	}
+	return p
+}
+
+// GetAllMetafields returns all the metafields of a resource, handling pagination
+// args is a map of arguments to pass to the API, e.g. namespace or key
+func (bc *Client) GetAllMetafields(owner MetafieldOwner, args map[string]string) ([]Metafield, error) {
+	q := map[string]string{"limit": "250"}
+	for k, v := range args {
+		q[k] = v
+	}
+	ret := []Metafield{}
+	page := 1
+	more := true
+	for more {
+		var ms []Metafield
+		var err error
+		ms, more, err = bc.GetMetafields(owner, q, page)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, ms...)
+		page++
+	}
+	return ret, nil
+}
+
+// GetMetafields returns a page of the metafields of a resource, and whether there are more pages
+// args is a map of arguments to pass to the API, e.g. namespace or key
+func (bc *Client) GetMetafields(owner MetafieldOwner, args map[string]string, page int) ([]Metafield, bool, error) {
+	path, err := owner.path()
+	if err != nil {
+		return nil, false, err
+	}
+	q := neturl.Values{}
+	for k, v := range args {
+		q.Set(k, v)
+	}
+	if len(q) > 0 {
+		path += "?" + q.Encode()
+	}
+	var ms []Metafield
+	more, err := bc.getPage(path, page, &ms)
+	if err != nil {
+		return nil, false, err
+	}
+	return ms, more, nil
+}
+
+// GetMetafield gets a metafield of a resource by ID
+func (bc *Client) GetMetafield(owner MetafieldOwner, metafieldID int64) (*Metafield, error) {
+	path, err := owner.path()
+	if err != nil {
+		return nil, err
+	}
+	var ret Metafield
+	err = bc.sendJSON(http.MethodGet, path+"/"+strconv.FormatInt(metafieldID, 10), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// GetMetafieldByKey gets the metafield of a resource with a namespace and key, ErrNotFound if there is none
+func (bc *Client) GetMetafieldByKey(owner MetafieldOwner, namespace, key string) (*Metafield, error) {
+	ms, _, err := bc.GetMetafields(owner, map[string]string{"namespace": namespace, "key": key}, 1)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ms {
+		if ms[i].Namespace == namespace && ms[i].Key == key {
+			return &ms[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// CreateMetafield creates a metafield on a resource, permission set defaults to app_only
+func (bc *Client) CreateMetafield(owner MetafieldOwner, metafield *Metafield) (*Metafield, error) {
+	path, err := owner.path()
+	if err != nil {
+		return nil, err
+	}
+	m := *metafield
+	m.ID = 0
+	if m.PermissionSet == "" {
+		m.PermissionSet = "app_only"
+	}
+	var ret Metafield
+	err = bc.sendJSON(http.MethodPost, path, m.payload(false), &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// UpdateMetafield updates a metafield of a resource by ID, an empty permission set is not changed
+func (bc *Client) UpdateMetafield(owner MetafieldOwner, metafield *Metafield) (*Metafield, error) {
+	if metafield.ID == 0 {
+		return nil, errors.New("metafield has no ID")
+	}
+	path, err := owner.path()
+	if err != nil {
+		return nil, err
+	}
+	payload := metafield.payload(false)
+	delete(payload, "id")
+	var ret Metafield
+	err = bc.sendJSON(http.MethodPut, path+"/"+strconv.FormatInt(metafield.ID, 10), payload, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// DeleteMetafield deletes a metafield of a resource
+func (bc *Client) DeleteMetafield(owner MetafieldOwner, metafieldID int64) error {
+	path, err := owner.path()
+	if err != nil {
+		return err
+	}
+	err = bc.sendJSON(http.MethodDelete, path+"/"+strconv.FormatInt(metafieldID, 10), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// UpsertMetafield creates the metafield, or updates the metafield of the resource with the same namespace and key
+func (bc *Client) UpsertMetafield(owner MetafieldOwner, metafield *Metafield) (*Metafield, error) {
+	existing, err := bc.GetMetafieldByKey(owner, metafield.Namespace, metafield.Key)
+	if err == ErrNotFound {
+		return bc.CreateMetafield(owner, metafield)
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := *metafield
+	m.ID = existing.ID
+	if m.PermissionSet == "" {
+		m.PermissionSet = existing.PermissionSet
+	}
+	return bc.UpdateMetafield(owner, &m)
+}
+
+// CreateMetafields creates metafields on many resources of a type with one request,
+// ResourceID (ResourceKey for carts) of each metafield is the resource it belongs to.
+// Permission sets default to app_only.
+func (bc *Client) CreateMetafields(resourceType string, metafields []Metafield) ([]Metafield, error) {
+	payload := []Patch{}
+	for _, m := range metafields {
+		m.ID = 0
+		if m.PermissionSet == "" {
+			m.PermissionSet = "app_only"
+		}
+		payload = append(payload, m.payload(true))
+	}
+	return bc.metafieldBatch(http.MethodPost, resourceType, payload)
+}
+
+// UpdateMetafields updates metafields of many resources of a type by ID with one request,
+// empty permission sets are not changed
+func (bc *Client) UpdateMetafields(resourceType string, metafields []Metafield) ([]Metafield, error) {
+	payload := []Patch{}
+	for _, m := range metafields {
+		if m.ID == 0 {
+			return nil, fmt.Errorf("metafield %s.%s has no ID", m.Namespace, m.Key)
+		}
+		payload = append(payload, m.payload(false))
+	}
+	return bc.metafieldBatch(http.MethodPut, resourceType, payload)
+}
+
+// DeleteMetafields deletes metafields of a resource type by ID with one request
+func (bc *Client) DeleteMetafields(resourceType string, metafieldIDs []int64) error {
+	_, err := bc.metafieldBatch(http.MethodDelete, resourceType, metafieldIDs)
+	if err == ErrNoContent {
+		return nil
+	}
+	return err
+}
+
+// metafieldBatch sends a batch request and returns the metafields saved, errors of single
+// items in a successful response are joined in the returned error
+func (bc *Client) metafieldBatch(method, resourceType string, payload interface{}) ([]Metafield, error) {
+	path, ok := metafieldBatchPaths[resourceType]
+	if !ok {
+		return nil, fmt.Errorf("unknown metafield resource type %s", resourceType)
+	}
+	b, _ := json.Marshal(payload)
+	req := bc.getAPIRequest(method, path, bytes.NewBuffer(b))
+	res, err := bc.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	body, err := processBody(res)
+	if err != nil {
+		var errResp ErrorResult
+		if body != nil && json.Unmarshal(body, &errResp) == nil {
+			_, err = batchErrors(errResp, 0)
+		}
+		return nil, err
+	}
+	var batchResponse struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []ErrorResult   `json:"errors"`
+	}
+	err = json.Unmarshal(body, &batchResponse)
+	if err != nil {
+		return nil, err
+	}
+	ret := []Metafield{}
+	if method != http.MethodDelete && len(batchResponse.Data) > 0 {
+		err = json.Unmarshal(batchResponse.Data, &ret)
+		if err != nil {
+			return nil, err
+		}
+	}
+	msgs := []string{}
+	for _, e := range batchResponse.Errors {
+		_, itemErr := batchErrors(e, 0)
+		msgs = append(msgs, itemErr.Error())
+	}
+	if len(msgs) > 0 {
+		return ret, errors.New(strings.Join(msgs, ", "))
+	}
+	return ret, nil
+}
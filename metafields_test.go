@@ -0,0 +1,62 @@
+package bigcommerce_test
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+	"github.com/mvalenziano/bigcommerce-api-go/bctest"
+)
+
+func TestMetafieldPermissionSet(t *testing.T) {
+	tests := []struct {
+		name   string
+		stored string // permission set of the stored metafield, empty for none
+		write  func(bc *bigcommerce.Client, owner bigcommerce.MetafieldOwner, m *bigcommerce.Metafield) (*bigcommerce.Metafield, error)
+		given  string
+		want   string
+	}{
+		{name: "create defaults to app_only", write: (*bigcommerce.Client).CreateMetafield, want: "app_only"},
+		{name: "create with a permission set", write: (*bigcommerce.Client).CreateMetafield, given: "read", want: "read"},
+		{name: "update keeps the permission set", stored: "read_and_sf_access", write: (*bigcommerce.Client).UpdateMetafield, want: "read_and_sf_access"},
+		{name: "update changes the permission set", stored: "read", write: (*bigcommerce.Client).UpdateMetafield, given: "write", want: "write"},
+		{name: "upsert creates with app_only", write: (*bigcommerce.Client).UpsertMetafield, want: "app_only"},
+		{name: "upsert keeps the permission set", stored: "write_and_sf_access", write: (*bigcommerce.Client).UpsertMetafield, want: "write_and_sf_access"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := bctest.NewServer()
+			p := s.AddProduct(bigcommerce.Product{Name: "Tee", Sku: "TEE", Price: 20})
+			owner := bigcommerce.ProductMetafieldOwner(p.ID)
+			m := bigcommerce.Metafield{Namespace: "app", Key: "color", Value: "red", PermissionSet: tt.given}
+			if tt.stored != "" {
+				stored := s.AddMetafield(bigcommerce.MetafieldProduct, strconv.FormatInt(p.ID, 10),
+					bigcommerce.Metafield{Namespace: "app", Key: "color", Value: "blue", PermissionSet: tt.stored})
+				m.ID = stored.ID
+			}
+			got, err := tt.write(s.Client(), owner, &m)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.PermissionSet != tt.want || got.Value != "red" {
+				t.Errorf("metafield %s with permission set %s, want red with %s", got.Value, got.PermissionSet, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpdateMetafieldsKeepsPermissionSets(t *testing.T) {
+	s := bctest.NewServer()
+	p := s.AddProduct(bigcommerce.Product{Name: "Tee", Sku: "TEE", Price: 20})
+	stored := s.AddMetafield(bigcommerce.MetafieldProduct, strconv.FormatInt(p.ID, 10),
+		bigcommerce.Metafield{Namespace: "app", Key: "color", Value: "blue", PermissionSet: "read"})
+	stored.Value = "red"
+	stored.PermissionSet = ""
+	got, err := s.Client().UpdateMetafields(bigcommerce.MetafieldProduct, []bigcommerce.Metafield{stored})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].PermissionSet != "read" || got[0].Value != "red" {
+		t.Errorf("updated %+v, want red with permission set read", got)
+	}
+}
@@ -0,0 +1,107 @@
+package mocks
+
+import (
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+var _ bigcommerce.AddressClient = (*AddressClient)(nil)
+
+// AddressClient is a stateful mock of bigcommerce.AddressClient
+type AddressClient struct {
+	Recorder
+	// Addresses by address ID
+	Addresses map[int64]*bigcommerce.Address
+	nextID    int64
+}
+
+func (am *AddressClient) init() {
+	if am.Addresses == nil {
+		am.Addresses = map[int64]*bigcommerce.Address{}
+	}
+}
+
+// AddAddress stores an address for a customer and returns it with its ID
+func (am *AddressClient) AddAddress(customerID int64, a bigcommerce.Address) *bigcommerce.Address {
+	am.init()
+	a.CustomerID = customerID
+	if a.ID == 0 {
+		for id := range am.Addresses {
+			if id > am.nextID {
+				am.nextID = id
+			}
+		}
+		am.nextID++
+		a.ID = am.nextID
+	}
+	am.Addresses[a.ID] = &a
+	return &a
+}
+
+func (am *AddressClient) customerAddresses(customerID int64) []bigcommerce.Address {
+	ids := []int64{}
+	for id, a := range am.Addresses {
+		if a.CustomerID == customerID {
+			ids = append(ids, id)
+		}
+	}
+	ret := []bigcommerce.Address{}
+	for _, id := range sortedIDs(ids) {
+		ret = append(ret, *am.Addresses[id])
+	}
+	return ret
+}
+
+func (am *AddressClient) CreateAddress(customerID int64, address *bigcommerce.Address) (*bigcommerce.Address, error) {
+	if err := am.record("CreateAddress", customerID, address); err != nil {
+		return nil, err
+	}
+	a := *address
+	a.ID = 0
+	return am.AddAddress(customerID, a), nil
+}
+
+func (am *AddressClient) UpdateAddress(customerID int64, address *bigcommerce.Address) (*bigcommerce.Address, error) {
+	if err := am.record("UpdateAddress", customerID, address); err != nil {
+		return nil, err
+	}
+	am.init()
+	stored, ok := am.Addresses[address.ID]
+	if !ok || stored.CustomerID != customerID {
+		return nil, bigcommerce.ErrNotFound
+	}
+	a := *address
+	a.CustomerID = customerID
+	am.Addresses[a.ID] = &a
+	return &a, nil
+}
+
+func (am *AddressClient) DeleteAddress(customerID int64, addressID int64) error {
+	if err := am.record("DeleteAddress", customerID, addressID); err != nil {
+		return err
+	}
+	am.init()
+	stored, ok := am.Addresses[addressID]
+	if !ok || stored.CustomerID != customerID {
+		return bigcommerce.ErrNotFound
+	}
+	delete(am.Addresses, addressID)
+	return nil
+}
+
+func (am *AddressClient) GetAddresses(customerID int64) ([]bigcommerce.Address, error) {
+	if err := am.record("GetAddresses", customerID); err != nil {
+		return nil, err
+	}
+	am.init()
+	return am.customerAddresses(customerID), nil
+}
+
+func (am *AddressClient) GetAddressPage(customerID int64, page int) ([]bigcommerce.Address, bool, error) {
+	if err := am.record("GetAddressPage", customerID, page); err != nil {
+		return nil, false, err
+	}
+	am.init()
+	as := am.customerAddresses(customerID)
+	from, to, more := pageBounds(len(as), nil, page)
+	return as[from:to], more, nil
+}
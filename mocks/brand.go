@@ -0,0 +1,189 @@
+package mocks
+
+import (
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+func (cm *CatalogClient) brand(brandID int64) (*bigcommerce.Brand, error) {
+	cm.init()
+	b, ok := cm.Brands[brandID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return b, nil
+}
+
+// brandByName returns the brand with a name, ignoring case
+func (cm *CatalogClient) brandByName(name string) (*bigcommerce.Brand, error) {
+	cm.init()
+	for _, id := range cm.brandIDs() {
+		b := cm.Brands[id]
+		if strings.EqualFold(strings.TrimSpace(b.Name), strings.TrimSpace(name)) {
+			ret := *b
+			return &ret, nil
+		}
+	}
+	return nil, bigcommerce.ErrNotFound
+}
+
+func (cm *CatalogClient) brandIDs() []int64 {
+	ids := []int64{}
+	for id := range cm.Brands {
+		ids = append(ids, id)
+	}
+	return sortedIDs(ids)
+}
+
+func (cm *CatalogClient) GetBrand(brandID int64) (*bigcommerce.Brand, error) {
+	if err := cm.record("GetBrand", brandID); err != nil {
+		return nil, err
+	}
+	b, err := cm.brand(brandID)
+	if err != nil {
+		return nil, err
+	}
+	ret := *b
+	return &ret, nil
+}
+
+func (cm *CatalogClient) GetBrandByName(name string) (*bigcommerce.Brand, error) {
+	if err := cm.record("GetBrandByName", name); err != nil {
+		return nil, err
+	}
+	return cm.brandByName(name)
+}
+
+// GetOrCreateBrand is safe for concurrent use with itself, not with the other methods
+func (cm *CatalogClient) GetOrCreateBrand(name string) (*bigcommerce.Brand, error) {
+	if err := cm.record("GetOrCreateBrand", name); err != nil {
+		return nil, err
+	}
+	cm.brandMu.Lock()
+	defer cm.brandMu.Unlock()
+	if strings.TrimSpace(name) == "" {
+		return nil, errors.New("brand name is empty")
+	}
+	if b, err := cm.brandByName(name); err == nil {
+		return b, nil
+	}
+	ret := *cm.AddBrand(bigcommerce.Brand{Name: strings.TrimSpace(name)})
+	return &ret, nil
+}
+
+// saveBrand stores a brand, names are unique ignoring case
+func (cm *CatalogClient) saveBrand(b bigcommerce.Brand) (*bigcommerce.Brand, error) {
+	if b.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if other, err := cm.brandByName(b.Name); err == nil && other.ID != b.ID {
+		return nil, errors.New("Brand name is a duplicate")
+	}
+	b.URL = b.CustomURL.URL
+	ret := *cm.AddBrand(b)
+	return &ret, nil
+}
+
+func (cm *CatalogClient) CreateBrand(brand *bigcommerce.Brand) (*bigcommerce.Brand, error) {
+	if err := cm.record("CreateBrand", brand); err != nil {
+		return nil, err
+	}
+	cm.init()
+	b := *brand
+	b.ID = 0
+	return cm.saveBrand(b)
+}
+
+func (cm *CatalogClient) UpdateBrand(brand *bigcommerce.Brand) (*bigcommerce.Brand, error) {
+	if err := cm.record("UpdateBrand", brand); err != nil {
+		return nil, err
+	}
+	stored, err := cm.brand(brand.ID)
+	if err != nil {
+		return nil, err
+	}
+	b := *brand
+	if b.ImageURL == "" {
+		b.ImageURL = stored.ImageURL
+	}
+	return cm.saveBrand(b)
+}
+
+func (cm *CatalogClient) DeleteBrand(brandID int64) error {
+	if err := cm.record("DeleteBrand", brandID); err != nil {
+		return err
+	}
+	if _, err := cm.brand(brandID); err != nil {
+		return err
+	}
+	delete(cm.Brands, brandID)
+	for _, p := range cm.Products {
+		if p.BrandID == brandID {
+			p.BrandID = 0
+		}
+	}
+	return nil
+}
+
+// setBrandImage sets the image URL of a brand
+func (cm *CatalogClient) setBrandImage(brandID int64, imageURL string) (*bigcommerce.Brand, error) {
+	b, err := cm.brand(brandID)
+	if err != nil {
+		return nil, err
+	}
+	b.ImageURL = imageURL
+	ret := *b
+	return &ret, nil
+}
+
+func (cm *CatalogClient) UploadBrandImage(brandID int64, filename string, r io.Reader) (string, error) {
+	if err := cm.record("UploadBrandImage", brandID, filename); err != nil {
+		return "", err
+	}
+	b, err := cm.setBrandImage(brandID, "https://cdn.example.com/brands/"+itoa(brandID)+"/"+filepath.Base(filename))
+	if err != nil {
+		return "", err
+	}
+	return b.ImageURL, nil
+}
+
+func (cm *CatalogClient) UploadBrandImageFile(brandID int64, filename string) (string, error) {
+	if err := cm.record("UploadBrandImageFile", brandID, filename); err != nil {
+		return "", err
+	}
+	b, err := cm.setBrandImage(brandID, "https://cdn.example.com/brands/"+itoa(brandID)+"/"+filepath.Base(filename))
+	if err != nil {
+		return "", err
+	}
+	return b.ImageURL, nil
+}
+
+func (cm *CatalogClient) SetBrandImageURL(brandID int64, imageURL string) (*bigcommerce.Brand, error) {
+	if err := cm.record("SetBrandImageURL", brandID, imageURL); err != nil {
+		return nil, err
+	}
+	return cm.setBrandImage(brandID, imageURL)
+}
+
+func (cm *CatalogClient) DeleteBrandImage(brandID int64) error {
+	if err := cm.record("DeleteBrandImage", brandID); err != nil {
+		return err
+	}
+	_, err := cm.setBrandImage(brandID, "")
+	return err
+}
+
+func (cm *CatalogClient) GetBrandMetafields(brandID int64) (map[string]bigcommerce.Metafield, error) {
+	if err := cm.record("GetBrandMetafields", brandID); err != nil {
+		return nil, err
+	}
+	ret := map[string]bigcommerce.Metafield{}
+	for k, m := range cm.BrandMetafields[brandID] {
+		ret[k] = m
+	}
+	return ret, nil
+}
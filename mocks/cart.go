@@ -3,61 +3,123 @@ package mocks
 import (
 	"strconv"
 
-	"github.com/gpmd/bigcommerce-api-go"
+	"github.com/mvalenziano/bigcommerce-api-go"
 )
 
+var _ bigcommerce.CartClient = (*CartClient)(nil)
+
+// CartClient is a stateful mock of bigcommerce.CartClient
 type CartClient struct {
-	carts       map[string]*bigcommerce.Cart
+	Recorder
+	Carts       map[string]*bigcommerce.Cart
 	CartContent map[string]bigcommerce.LineItem
-	CartID      string
-	CustomerID  int64
+	// CartID is the ID of the next created cart, generated when empty
+	CartID string
+	// CustomerID is set on created carts
+	CustomerID int64
+	nextID     int
+}
+
+func (cm *CartClient) init() {
+	if cm.Carts == nil {
+		cm.Carts = map[string]*bigcommerce.Cart{}
+	}
+}
+
+// newID returns a generated ID with the given prefix
+func (cm *CartClient) newID(prefix string) string {
+	cm.nextID++
+	return prefix + strconv.Itoa(cm.nextID)
+}
+
+// addItems appends line items to a cart, assigning IDs to new items
+func (cm *CartClient) addItems(cart *bigcommerce.Cart, items []bigcommerce.LineItem) {
+	for _, item := range items {
+		if item.ID == "" {
+			item.ID = cm.newID("item-")
+		}
+		cart.LineItems.PhysicalItems = append(cart.LineItems.PhysicalItems, item)
+	}
+	cartTotals(cart)
+}
+
+// cartTotals updates the cart amounts from the line items
+func cartTotals(cart *bigcommerce.Cart) {
+	amount := 0.0
+	for i, item := range cart.LineItems.PhysicalItems {
+		price := item.SalePrice
+		if price == 0 {
+			price = item.ListPrice
+		}
+		cart.LineItems.PhysicalItems[i].ExtendedSalePrice = price * item.Quantity
+		amount += price * item.Quantity
+	}
+	cart.BaseAmount = amount
+	cart.CartAmount = amount - cart.DiscountAmount
 }
 
 func (cm *CartClient) CreateCart(items []bigcommerce.LineItem) (*bigcommerce.Cart, error) {
-	if cm.carts == nil {
-		cm.carts = map[string]*bigcommerce.Cart{}
+	if err := cm.record("CreateCart", items); err != nil {
+		return nil, err
+	}
+	return cm.createCart(0, items), nil
+}
+
+func (cm *CartClient) CreateCartInChannel(channelID int, items []bigcommerce.LineItem) (*bigcommerce.Cart, error) {
+	if err := cm.record("CreateCartInChannel", channelID, items); err != nil {
+		return nil, err
 	}
+	return cm.createCart(int64(channelID), items), nil
+}
+
+func (cm *CartClient) createCart(channelID int64, items []bigcommerce.LineItem) *bigcommerce.Cart {
+	cm.init()
 	cart := bigcommerce.Cart{
 		ID:         cm.CartID,
 		CustomerID: cm.CustomerID,
+		ChannelID:  channelID,
 	}
-	cart.LineItems.PhysicalItems = append(cart.LineItems.PhysicalItems, items...)
-	cm.carts[cart.ID] = &cart
-	return &cart, nil
+	if cart.ID == "" {
+		cart.ID = cm.newID("cart-")
+	}
+	cm.addItems(&cart, items)
+	cm.Carts[cart.ID] = &cart
+	return &cart
 }
 
 func (cm *CartClient) GetCart(cartID string) (*bigcommerce.Cart, error) {
-	if cm.carts == nil {
-		cm.carts = map[string]*bigcommerce.Cart{}
-		return nil, bigcommerce.ErrNotFound
+	if err := cm.record("GetCart", cartID); err != nil {
+		return nil, err
 	}
-	if cart, ok := cm.carts[cartID]; ok {
+	cm.init()
+	if cart, ok := cm.Carts[cartID]; ok {
 		return cart, nil
 	}
 	return nil, bigcommerce.ErrNotFound
 }
 
 func (cm *CartClient) CartAddItems(cartID string, items []bigcommerce.LineItem) (*bigcommerce.Cart, error) {
-	if cm.carts == nil {
-		cm.carts = map[string]*bigcommerce.Cart{}
-		return nil, bigcommerce.ErrNotFound
+	if err := cm.record("CartAddItems", cartID, items); err != nil {
+		return nil, err
 	}
-	if cart, ok := cm.carts[cartID]; ok {
-		cart.LineItems.PhysicalItems = append(cart.LineItems.PhysicalItems, items...)
+	cm.init()
+	if cart, ok := cm.Carts[cartID]; ok {
+		cm.addItems(cart, items)
 		return cart, nil
 	}
 	return nil, bigcommerce.ErrNotFound
 }
 
 func (cm *CartClient) CartEditItem(cartID string, item bigcommerce.LineItem) (*bigcommerce.Cart, error) {
-	if cm.carts == nil {
-		cm.carts = map[string]*bigcommerce.Cart{}
-		return nil, bigcommerce.ErrNotFound
+	if err := cm.record("CartEditItem", cartID, item); err != nil {
+		return nil, err
 	}
-	if cart, ok := cm.carts[cartID]; ok {
+	cm.init()
+	if cart, ok := cm.Carts[cartID]; ok {
 		for i, lineItem := range cart.LineItems.PhysicalItems {
 			if lineItem.ID == item.ID {
 				cart.LineItems.PhysicalItems[i] = item
+				cartTotals(cart)
 				return cart, nil
 			}
 		}
@@ -65,15 +127,21 @@ func (cm *CartClient) CartEditItem(cartID string, item bigcommerce.LineItem) (*b
 	return nil, bigcommerce.ErrNotFound
 }
 
+// CartDeleteItem removes a line item, like the API an empty cart is deleted and nil returned
 func (cm *CartClient) CartDeleteItem(cartID string, item bigcommerce.LineItem) (*bigcommerce.Cart, error) {
-	if cm.carts == nil {
-		cm.carts = map[string]*bigcommerce.Cart{}
-		return nil, bigcommerce.ErrNotFound
+	if err := cm.record("CartDeleteItem", cartID, item); err != nil {
+		return nil, err
 	}
-	if cart, ok := cm.carts[cartID]; ok {
+	cm.init()
+	if cart, ok := cm.Carts[cartID]; ok {
 		for i, lineItem := range cart.LineItems.PhysicalItems {
 			if lineItem.ID == item.ID {
 				cart.LineItems.PhysicalItems = append(cart.LineItems.PhysicalItems[:i], cart.LineItems.PhysicalItems[i+1:]...)
+				if len(cart.LineItems.PhysicalItems) == 0 {
+					delete(cm.Carts, cartID)
+					return nil, nil
+				}
+				cartTotals(cart)
 				return cart, nil
 			}
 		}
@@ -82,11 +150,11 @@ func (cm *CartClient) CartDeleteItem(cartID string, item bigcommerce.LineItem) (
 }
 
 func (cm *CartClient) CartUpdateCustomerID(cartID, customerID string) (*bigcommerce.Cart, error) {
-	if cm.carts == nil {
-		cm.carts = map[string]*bigcommerce.Cart{}
-		return nil, bigcommerce.ErrNotFound
+	if err := cm.record("CartUpdateCustomerID", cartID, customerID); err != nil {
+		return nil, err
 	}
-	if cart, ok := cm.carts[cartID]; ok {
+	cm.init()
+	if cart, ok := cm.Carts[cartID]; ok {
 		cid, err := strconv.ParseInt(customerID, 10, 64)
 		if err != nil {
 			return nil, err
@@ -98,10 +166,13 @@ func (cm *CartClient) CartUpdateCustomerID(cartID, customerID string) (*bigcomme
 }
 
 func (cm *CartClient) DeleteCart(cartID string) error {
-	if cm.carts == nil {
-		cm.carts = map[string]*bigcommerce.Cart{}
+	if err := cm.record("DeleteCart", cartID); err != nil {
+		return err
+	}
+	cm.init()
+	if _, ok := cm.Carts[cartID]; !ok {
 		return bigcommerce.ErrNotFound
 	}
-	delete(cm.carts, cartID)
+	delete(cm.Carts, cartID)
 	return nil
 }
@@ -0,0 +1,964 @@
+package mocks
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+var _ bigcommerce.CatalogClient = (*CatalogClient)(nil)
+
+// CatalogClient is a stateful mock of bigcommerce.CatalogClient
+type CatalogClient struct {
+	Recorder
+	Products   map[int64]*bigcommerce.Product
+	Variants   map[int64]*bigcommerce.Variant
+	Brands     map[int64]*bigcommerce.Brand
+	Categories map[int64]*bigcommerce.Category
+	// Images by product ID
+	Images map[int64][]bigcommerce.Image
+	// Metafields by product ID and key
+	Metafields map[int64]map[string]bigcommerce.Metafield
+	// BrandMetafields by brand ID and key
+	BrandMetafields map[int64]map[string]bigcommerce.Metafield
+	// ProductChannels are the channel IDs of each product
+	ProductChannels map[int64][]int64
+	// Videos, CustomFields and BulkPricingRules by product ID
+	Videos           map[int64][]bigcommerce.Video
+	CustomFields     map[int64][]bigcommerce.CustomField
+	BulkPricingRules map[int64][]bigcommerce.BulkPricingRule
+	// SortOrders are the sort orders of products by category ID and product ID,
+	// category assignments are the Categories of the products
+	SortOrders map[int64]map[int64]int64
+	// Trees are the category trees by ID, categories belong to a tree by TreeID
+	Trees map[int64]*bigcommerce.CatalogTree
+	// Listings are the channel listings by listing ID
+	Listings map[int64]*bigcommerce.ChannelListing
+	nextID   int64
+	// brandMu makes concurrent GetOrCreateBrand calls safe
+	brandMu sync.Mutex
+}
+
+func (cm *CatalogClient) init() {
+	if cm.Products == nil {
+		cm.Products = map[int64]*bigcommerce.Product{}
+	}
+	if cm.Variants == nil {
+		cm.Variants = map[int64]*bigcommerce.Variant{}
+	}
+	if cm.Brands == nil {
+		cm.Brands = map[int64]*bigcommerce.Brand{}
+	}
+	if cm.Categories == nil {
+		cm.Categories = map[int64]*bigcommerce.Category{}
+	}
+	if cm.Images == nil {
+		cm.Images = map[int64][]bigcommerce.Image{}
+	}
+	if cm.Metafields == nil {
+		cm.Metafields = map[int64]map[string]bigcommerce.Metafield{}
+	}
+	if cm.ProductChannels == nil {
+		cm.ProductChannels = map[int64][]int64{}
+	}
+}
+
+// newID returns an ID not used by any product, variant, brand or category
+func (cm *CatalogClient) newID() int64 {
+	cm.nextID++
+	for cm.Products[cm.nextID] != nil || cm.Variants[cm.nextID] != nil ||
+		cm.Brands[cm.nextID] != nil || cm.Categories[cm.nextID] != nil {
+		cm.nextID++
+	}
+	return cm.nextID
+}
+
+// AddProduct stores a product with a base variant and returns it with its ID
+func (cm *CatalogClient) AddProduct(p bigcommerce.Product) *bigcommerce.Product {
+	cm.init()
+	if p.ID == 0 {
+		p.ID = cm.newID()
+	}
+	cm.Products[p.ID] = &p
+	if p.BaseVariantID == 0 {
+		v := cm.AddVariant(bigcommerce.Variant{ProductID: p.ID, Sku: p.Sku, Price: p.Price})
+		p.BaseVariantID = v.ID
+	}
+	return &p
+}
+
+// AddVariant stores a variant and returns it with its ID
+func (cm *CatalogClient) AddVariant(v bigcommerce.Variant) *bigcommerce.Variant {
+	cm.init()
+	if v.ID == 0 {
+		v.ID = cm.newID()
+	}
+	cm.Variants[v.ID] = &v
+	return &v
+}
+
+// AddBrand stores a brand and returns it with its ID
+func (cm *CatalogClient) AddBrand(b bigcommerce.Brand) *bigcommerce.Brand {
+	cm.init()
+	if b.ID == 0 {
+		b.ID = cm.newID()
+	}
+	cm.Brands[b.ID] = &b
+	return &b
+}
+
+// AddCategory stores a category and returns it with its ID
+func (cm *CatalogClient) AddCategory(c bigcommerce.Category) *bigcommerce.Category {
+	cm.init()
+	if c.ID == 0 {
+		c.ID = cm.newID()
+	}
+	cm.Categories[c.ID] = &c
+	return &c
+}
+
+func (cm *CatalogClient) GetAllBrands(args map[string]string) ([]bigcommerce.Brand, error) {
+	if err := cm.record("GetAllBrands", args); err != nil {
+		return nil, err
+	}
+	return cm.brands(args), nil
+}
+
+func (cm *CatalogClient) GetBrands(args map[string]string, page int) ([]bigcommerce.Brand, bool, error) {
+	if err := cm.record("GetBrands", args, page); err != nil {
+		return nil, false, err
+	}
+	bs := cm.brands(args)
+	from, to, more := pageBounds(len(bs), args, page)
+	return bs[from:to], more, nil
+}
+
+func (cm *CatalogClient) brands(args map[string]string) []bigcommerce.Brand {
+	cm.init()
+	ids := []int64{}
+	for id := range cm.Brands {
+		ids = append(ids, id)
+	}
+	ret := []bigcommerce.Brand{}
+	for _, id := range sortedIDs(ids) {
+		b := cm.Brands[id]
+		if matchArgs(args, map[string]string{"id": itoa(b.ID), "name": b.Name}) {
+			ret = append(ret, *b)
+		}
+	}
+	return ret
+}
+
+func (cm *CatalogClient) GetAllCategories(args map[string]string) ([]bigcommerce.Category, error) {
+	if err := cm.record("GetAllCategories", args); err != nil {
+		return nil, err
+	}
+	return cm.categories(args), nil
+}
+
+func (cm *CatalogClient) GetCategories(args map[string]string, page int) ([]bigcommerce.Category, bool, error) {
+	if err := cm.record("GetCategories", args, page); err != nil {
+		return nil, false, err
+	}
+	cs := cm.categories(args)
+	from, to, more := pageBounds(len(cs), args, page)
+	return cs[from:to], more, nil
+}
+
+func (cm *CatalogClient) categories(args map[string]string) []bigcommerce.Category {
+	cm.init()
+	ids := []int64{}
+	for id := range cm.Categories {
+		ids = append(ids, id)
+	}
+	all := []bigcommerce.Category{}
+	for _, c := range cm.Categories {
+		all = append(all, *c)
+	}
+	tree := bigcommerce.NewCategoryTree(all)
+	ret := []bigcommerce.Category{}
+	for _, id := range sortedIDs(ids) {
+		c, _ := tree.Get(id)
+		if matchArgs(args, map[string]string{"id": itoa(c.ID), "name": c.Name, "parent_id": itoa(c.ParentID)}) {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+func (cm *CatalogClient) GetMainThumbnailURL(productID int64) (string, error) {
+	if err := cm.record("GetMainThumbnailURL", productID); err != nil {
+		return "", err
+	}
+	cm.init()
+	for _, img := range cm.Images[productID] {
+		if img.IsThumbnail {
+			return img.URLThumbnail, nil
+		}
+	}
+	return "", bigcommerce.ErrNoMainThumbnail
+}
+
+func (cm *CatalogClient) GetProductImages(productID int64) ([]bigcommerce.Image, error) {
+	if err := cm.record("GetProductImages", productID); err != nil {
+		return nil, err
+	}
+	cm.init()
+	return cm.Images[productID], nil
+}
+
+// addImage stores a new image of a product, unsetting the thumbnail flag of the others if it is set
+func (cm *CatalogClient) addImage(productID int64, file string, image *bigcommerce.Image) (*bigcommerce.Image, error) {
+	cm.init()
+	if _, ok := cm.Products[productID]; !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	img := bigcommerce.Image{ID: cm.newID(), ProductID: productID, ImageFile: file}
+	if image != nil {
+		img.Description = image.Description
+		img.SortOrder = image.SortOrder
+		img.IsThumbnail = image.IsThumbnail
+		img.ImageURL = image.ImageURL
+	}
+	img.URLZoom = "https://cdn.example.com/" + file
+	img.URLStandard = img.URLZoom
+	img.URLThumbnail = img.URLZoom
+	img.URLTiny = img.URLZoom
+	cm.Images[productID] = append(cm.Images[productID], img)
+	cm.setThumbnail(productID, img)
+	return &img, nil
+}
+
+func (cm *CatalogClient) setThumbnail(productID int64, img bigcommerce.Image) {
+	if !img.IsThumbnail {
+		return
+	}
+	for i := range cm.Images[productID] {
+		cm.Images[productID][i].IsThumbnail = cm.Images[productID][i].ID == img.ID
+	}
+}
+
+func (cm *CatalogClient) CreateProductImage(productID int64, image *bigcommerce.Image) (*bigcommerce.Image, error) {
+	if err := cm.record("CreateProductImage", productID, image); err != nil {
+		return nil, err
+	}
+	if image.ImageURL == "" {
+		return nil, errors.New("image has no image_url")
+	}
+	return cm.addImage(productID, path.Base(image.ImageURL), image)
+}
+
+// UploadProductImage stores an image named filename, the content of r is not read
+func (cm *CatalogClient) UploadProductImage(productID int64, filename string, r io.Reader, image *bigcommerce.Image) (*bigcommerce.Image, error) {
+	if err := cm.record("UploadProductImage", productID, filename, image); err != nil {
+		return nil, err
+	}
+	return cm.addImage(productID, path.Base(filename), image)
+}
+
+func (cm *CatalogClient) UpdateProductImage(productID int64, image *bigcommerce.Image) (*bigcommerce.Image, error) {
+	if err := cm.record("UpdateProductImage", productID, image); err != nil {
+		return nil, err
+	}
+	cm.init()
+	for i, img := range cm.Images[productID] {
+		if img.ID == image.ID {
+			img.Description = image.Description
+			img.SortOrder = image.SortOrder
+			img.IsThumbnail = image.IsThumbnail
+			cm.Images[productID][i] = img
+			cm.setThumbnail(productID, img)
+			return &img, nil
+		}
+	}
+	return nil, bigcommerce.ErrNotFound
+}
+
+func (cm *CatalogClient) DeleteProductImage(productID, imageID int64) error {
+	if err := cm.record("DeleteProductImage", productID, imageID); err != nil {
+		return err
+	}
+	cm.init()
+	images := cm.Images[productID]
+	for i, img := range images {
+		if img.ID == imageID {
+			cm.Images[productID] = append(images[:i], images[i+1:]...)
+			return nil
+		}
+	}
+	return bigcommerce.ErrNotFound
+}
+
+func (cm *CatalogClient) GetAllProducts(args map[string]string) ([]bigcommerce.Product, error) {
+	if err := cm.record("GetAllProducts", args); err != nil {
+		return nil, err
+	}
+	return cm.products(args), nil
+}
+
+func (cm *CatalogClient) GetProducts(args map[string]string, page int) ([]bigcommerce.Product, bool, error) {
+	if err := cm.record("GetProducts", args, page); err != nil {
+		return nil, false, err
+	}
+	ps := cm.products(args)
+	from, to, more := pageBounds(len(ps), args, page)
+	return ps[from:to], more, nil
+}
+
+func (cm *CatalogClient) products(args map[string]string) []bigcommerce.Product {
+	cm.init()
+	ids := []int64{}
+	for id := range cm.Products {
+		ids = append(ids, id)
+	}
+	ret := []bigcommerce.Product{}
+	for _, id := range sortedIDs(ids) {
+		p := cm.Products[id]
+		if matchArgs(args, map[string]string{
+			"id":         itoa(p.ID),
+			"sku":        p.Sku,
+			"name":       p.Name,
+			"type":       p.Type,
+			"brand_id":   itoa(p.BrandID),
+			"is_visible": boolString(p.IsVisible),
+		}) {
+			ret = append(ret, *p)
+		}
+	}
+	return ret
+}
+
+func boolString(b bool) string {
+	if b {
+		return "true"
+	}
+	return "false"
+}
+
+func (cm *CatalogClient) GetProductByID(productID int64) (*bigcommerce.Product, error) {
+	if err := cm.record("GetProductByID", productID); err != nil {
+		return nil, err
+	}
+	cm.init()
+	p, ok := cm.Products[productID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return p, nil
+}
+
+func (cm *CatalogClient) GetProductBySku(sku string) (*bigcommerce.Product, error) {
+	if err := cm.record("GetProductBySku", sku); err != nil {
+		return nil, err
+	}
+	cm.init()
+	p, err := cm.productBySku(sku)
+	if err != nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return p, nil
+}
+
+func (cm *CatalogClient) GetProductMetafields(productID int64) (map[string]bigcommerce.Metafield, error) {
+	if err := cm.record("GetProductMetafields", productID); err != nil {
+		return nil, err
+	}
+	cm.init()
+	ret := map[string]bigcommerce.Metafield{}
+	for k, m := range cm.Metafields[productID] {
+		ret[k] = m
+	}
+	return ret, nil
+}
+
+func (cm *CatalogClient) CreateProduct(payload *bigcommerce.Product) (*bigcommerce.Product, error) {
+	if err := cm.record("CreateProduct", payload); err != nil {
+		return nil, err
+	}
+	cm.init()
+	if payload.Name == "" || payload.Type == "" {
+		return nil, errors.New("name and type are required")
+	}
+	for _, p := range cm.Products {
+		if strings.EqualFold(p.Name, payload.Name) {
+			return nil, errors.New("The product name is a duplicate")
+		}
+		if payload.Sku != "" && p.Sku == payload.Sku {
+			return nil, errors.New("The product sku is a duplicate")
+		}
+	}
+	p := *payload
+	p.ID = 0
+	p.BaseVariantID = 0
+	return cm.AddProduct(p), nil
+}
+
+// productBySku returns the product with the given SKU, like the API returns 0 products when it doesn't exist
+func (cm *CatalogClient) productBySku(sku string) (*bigcommerce.Product, error) {
+	for _, p := range cm.Products {
+		if p.Sku == sku {
+			return p, nil
+		}
+	}
+	return nil, errors.New("Empty response back on getting product by sku: " + sku)
+}
+
+func (cm *CatalogClient) UpdateProductBySku(payload *bigcommerce.Product) (*bigcommerce.Product, error) {
+	if err := cm.record("UpdateProductBySku", payload); err != nil {
+		return nil, err
+	}
+	cm.init()
+	stored, err := cm.productBySku(payload.Sku)
+	if err != nil {
+		return nil, err
+	}
+	p := *payload
+	p.ID = stored.ID
+	p.BaseVariantID = stored.BaseVariantID
+	cm.Products[p.ID] = &p
+	return &p, nil
+}
+
+func (cm *CatalogClient) UpdateProductInventory(prodInventoryPayload *bigcommerce.ProductInventory) (*bigcommerce.Product, error) {
+	if err := cm.record("UpdateProductInventory", prodInventoryPayload); err != nil {
+		return nil, err
+	}
+	cm.init()
+	p, ok := cm.Products[prodInventoryPayload.ID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	p.InventoryLevel = prodInventoryPayload.InventoryLevel
+	p.InventoryWarningLevel = prodInventoryPayload.InventoryWarningLevel
+	p.InventoryTracking = prodInventoryPayload.InventoryTracking
+	p.IsVisible = prodInventoryPayload.IsVisible
+	p.Availability = prodInventoryPayload.Availability
+	p.AvailabilityDescription = prodInventoryPayload.AvailabilityDescription
+	return p, nil
+}
+
+func (cm *CatalogClient) UpdateProductSalePrice(prodSalePricePayload *bigcommerce.ProductSalePrice) (*bigcommerce.Product, error) {
+	if err := cm.record("UpdateProductSalePrice", prodSalePricePayload); err != nil {
+		return nil, err
+	}
+	cm.init()
+	p, ok := cm.Products[prodSalePricePayload.ID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	p.SalePrice = prodSalePricePayload.SalePrice
+	return p, nil
+}
+
+// UpdateProducts updates the products by ID, or by SKU when they have no ID, with per-product results
+func (cm *CatalogClient) UpdateProducts(products []bigcommerce.Product) (bigcommerce.ProductUpdateResults, error) {
+	results := bigcommerce.ProductUpdateResults{
+		ByID:  map[int64]*bigcommerce.ProductUpdateResult{},
+		BySku: map[string]*bigcommerce.ProductUpdateResult{},
+	}
+	if err := cm.record("UpdateProducts", products); err != nil {
+		return results, err
+	}
+	cm.init()
+	failed := 0
+	for _, payload := range products {
+		res := &bigcommerce.ProductUpdateResult{ID: payload.ID, Sku: payload.Sku}
+		stored, ok := cm.Products[payload.ID]
+		if payload.ID == 0 {
+			if payload.Sku == "" {
+				res.Err = errors.New("product has no ID and no SKU")
+			} else if stored, res.Err = cm.productBySku(payload.Sku); res.Err != nil {
+				res.Err = bigcommerce.ErrNotFound
+			}
+		} else if !ok {
+			res.Err = bigcommerce.ErrNotFound
+		}
+		if res.Err == nil {
+			p := *stored
+			res.Err = applySet(&p, payload)
+			if res.Err == nil {
+				cm.Products[p.ID] = &p
+				res.ID = p.ID
+				res.Sku = p.Sku
+				res.Product = &p
+			}
+		}
+		if res.Err != nil {
+			failed++
+		}
+		results.Results = append(results.Results, res)
+		if res.ID != 0 {
+			results.ByID[res.ID] = res
+		}
+		if res.Sku != "" {
+			results.BySku[res.Sku] = res
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d products failed to update", failed, len(products))
+	}
+	return results, nil
+}
+
+// PatchProducts applies the patches to the products with the ID of the patch, with per-product results
+func (cm *CatalogClient) PatchProducts(patches []bigcommerce.Patch) (bigcommerce.ProductUpdateResults, error) {
+	results := bigcommerce.ProductUpdateResults{
+		ByID:  map[int64]*bigcommerce.ProductUpdateResult{},
+		BySku: map[string]*bigcommerce.ProductUpdateResult{},
+	}
+	if err := cm.record("PatchProducts", patches); err != nil {
+		return results, err
+	}
+	cm.init()
+	failed := 0
+	for _, patch := range patches {
+		res := &bigcommerce.ProductUpdateResult{ID: patchID(patch)}
+		stored, ok := cm.Products[res.ID]
+		if !ok {
+			res.Err = bigcommerce.ErrNotFound
+		} else {
+			p := *stored
+			res.Err = applyPatch(&p, patch)
+			if res.Err == nil {
+				p.ID = stored.ID
+				cm.Products[p.ID] = &p
+				res.Sku = p.Sku
+				res.Product = &p
+			}
+		}
+		if res.Err != nil {
+			failed++
+		}
+		results.Results = append(results.Results, res)
+		if res.ID != 0 {
+			results.ByID[res.ID] = res
+		}
+		if res.Sku != "" {
+			results.BySku[res.Sku] = res
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d products failed to update", failed, len(patches))
+	}
+	return results, nil
+}
+
+// DeleteProduct deletes a product with its variants, images, metafields and channel assignments
+func (cm *CatalogClient) DeleteProduct(productID int64) error {
+	if err := cm.record("DeleteProduct", productID); err != nil {
+		return err
+	}
+	cm.init()
+	if _, ok := cm.Products[productID]; !ok {
+		return bigcommerce.ErrNotFound
+	}
+	cm.deleteProduct(productID)
+	return nil
+}
+
+// DeleteProducts deletes the products, ignoring IDs that don't exist like the API does
+func (cm *CatalogClient) DeleteProducts(productIDs []int64) error {
+	if err := cm.record("DeleteProducts", productIDs); err != nil {
+		return err
+	}
+	cm.init()
+	for _, id := range productIDs {
+		cm.deleteProduct(id)
+	}
+	return nil
+}
+
+func (cm *CatalogClient) deleteProduct(productID int64) {
+	delete(cm.Products, productID)
+	for id, v := range cm.Variants {
+		if v.ProductID == productID {
+			delete(cm.Variants, id)
+		}
+	}
+	delete(cm.Images, productID)
+	delete(cm.Metafields, productID)
+	delete(cm.ProductChannels, productID)
+}
+
+func (cm *CatalogClient) PatchProduct(productID int64, patch bigcommerce.Patch) (*bigcommerce.Product, error) {
+	if err := cm.record("PatchProduct", productID, patch); err != nil {
+		return nil, err
+	}
+	cm.init()
+	stored, ok := cm.Products[productID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	p := *stored
+	if err := applyPatch(&p, patch); err != nil {
+		return nil, err
+	}
+	p.ID = productID
+	cm.Products[productID] = &p
+	return &p, nil
+}
+
+func (cm *CatalogClient) PatchVariant(productID, variantID int64, patch bigcommerce.Patch) (*bigcommerce.Variant, error) {
+	if err := cm.record("PatchVariant", productID, variantID, patch); err != nil {
+		return nil, err
+	}
+	cm.init()
+	stored, ok := cm.Variants[variantID]
+	if !ok || stored.ProductID != productID {
+		return nil, bigcommerce.ErrNotFound
+	}
+	v := *stored
+	if err := applyPatch(&v, patch); err != nil {
+		return nil, err
+	}
+	v.ID = variantID
+	v.ProductID = productID
+	cm.Variants[variantID] = &v
+	return &v, nil
+}
+
+func (cm *CatalogClient) PatchCategory(categoryID int64, patch bigcommerce.Patch) (*bigcommerce.Category, error) {
+	if err := cm.record("PatchCategory", categoryID, patch); err != nil {
+		return nil, err
+	}
+	cm.init()
+	stored, ok := cm.Categories[categoryID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	c := *stored
+	if err := applyPatch(&c, patch); err != nil {
+		return nil, err
+	}
+	c.ID = categoryID
+	cm.Categories[categoryID] = &c
+	return &c, nil
+}
+
+func (cm *CatalogClient) PatchBrand(brandID int64, patch bigcommerce.Patch) (*bigcommerce.Brand, error) {
+	if err := cm.record("PatchBrand", brandID, patch); err != nil {
+		return nil, err
+	}
+	cm.init()
+	stored, ok := cm.Brands[brandID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	b := *stored
+	if err := applyPatch(&b, patch); err != nil {
+		return nil, err
+	}
+	b.ID = brandID
+	cm.Brands[brandID] = &b
+	return &b, nil
+}
+
+func (cm *CatalogClient) GetVariants(args map[string]string, page int) ([]bigcommerce.Variant, bool, error) {
+	if err := cm.record("GetVariants", args, page); err != nil {
+		return nil, false, err
+	}
+	cm.init()
+	vs := cm.variants(args)
+	from, to, more := pageBounds(len(vs), args, page)
+	return vs[from:to], more, nil
+}
+
+// variants returns the variants matching the id, sku and product_id arguments
+func (cm *CatalogClient) variants(args map[string]string) []bigcommerce.Variant {
+	ids := []int64{}
+	for id := range cm.Variants {
+		ids = append(ids, id)
+	}
+	vs := []bigcommerce.Variant{}
+	for _, id := range sortedIDs(ids) {
+		v := cm.Variants[id]
+		if matchArgs(args, map[string]string{"id": itoa(v.ID), "sku": v.Sku, "product_id": itoa(v.ProductID)}) {
+			vs = append(vs, *v)
+		}
+	}
+	return vs
+}
+
+func (cm *CatalogClient) GetAllProductVariants(productID int64, args map[string]string) ([]bigcommerce.Variant, error) {
+	if err := cm.record("GetAllProductVariants", productID, args); err != nil {
+		return nil, err
+	}
+	cm.init()
+	return cm.productVariants(productID, args), nil
+}
+
+func (cm *CatalogClient) GetProductVariants(productID int64, args map[string]string, page int) ([]bigcommerce.Variant, bool, error) {
+	if err := cm.record("GetProductVariants", productID, args, page); err != nil {
+		return nil, false, err
+	}
+	cm.init()
+	vs := cm.productVariants(productID, args)
+	from, to, more := pageBounds(len(vs), args, page)
+	return vs[from:to], more, nil
+}
+
+func (cm *CatalogClient) productVariants(productID int64, args map[string]string) []bigcommerce.Variant {
+	filter := map[string]string{"product_id": itoa(productID)}
+	for k, v := range args {
+		filter[k] = v
+	}
+	return cm.variants(filter)
+}
+
+func (cm *CatalogClient) GetVariant(productID, variantID int64) (*bigcommerce.Variant, error) {
+	if err := cm.record("GetVariant", productID, variantID); err != nil {
+		return nil, err
+	}
+	cm.init()
+	v, ok := cm.Variants[variantID]
+	if !ok || v.ProductID != productID {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return v, nil
+}
+
+func (cm *CatalogClient) GetVariantBySku(sku string) (*bigcommerce.Variant, error) {
+	if err := cm.record("GetVariantBySku", sku); err != nil {
+		return nil, err
+	}
+	cm.init()
+	v, err := cm.variantBySku(sku)
+	if err != nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return v, nil
+}
+
+func (cm *CatalogClient) CreateVariant(productID int64, variant *bigcommerce.Variant) (*bigcommerce.Variant, error) {
+	if err := cm.record("CreateVariant", productID, variant); err != nil {
+		return nil, err
+	}
+	cm.init()
+	if _, ok := cm.Products[productID]; !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	if variant.Sku != "" {
+		if _, err := cm.variantBySku(variant.Sku); err == nil {
+			return nil, errors.New("The variant sku is a duplicate")
+		}
+	}
+	v := *variant
+	v.ID = 0
+	v.ProductID = productID
+	return cm.AddVariant(v), nil
+}
+
+func (cm *CatalogClient) UpdateVariant(productID int64, variant *bigcommerce.Variant) (*bigcommerce.Variant, error) {
+	if err := cm.record("UpdateVariant", productID, variant); err != nil {
+		return nil, err
+	}
+	cm.init()
+	stored, ok := cm.Variants[variant.ID]
+	if !ok || stored.ProductID != productID {
+		return nil, bigcommerce.ErrNotFound
+	}
+	v := *stored
+	if err := applySet(&v, variant); err != nil {
+		return nil, err
+	}
+	v.ProductID = productID
+	cm.Variants[v.ID] = &v
+	return &v, nil
+}
+
+func (cm *CatalogClient) DeleteVariant(productID, variantID int64) error {
+	if err := cm.record("DeleteVariant", productID, variantID); err != nil {
+		return err
+	}
+	cm.init()
+	v, ok := cm.Variants[variantID]
+	if !ok || v.ProductID != productID {
+		return bigcommerce.ErrNotFound
+	}
+	delete(cm.Variants, variantID)
+	return nil
+}
+
+// UpdateVariants updates the variants by ID, or by SKU when they have no ID, with per-variant results
+func (cm *CatalogClient) UpdateVariants(variants []bigcommerce.Variant) (bigcommerce.VariantUpdateResults, error) {
+	results := bigcommerce.VariantUpdateResults{
+		ByID:  map[int64]*bigcommerce.VariantUpdateResult{},
+		BySku: map[string]*bigcommerce.VariantUpdateResult{},
+	}
+	if err := cm.record("UpdateVariants", variants); err != nil {
+		return results, err
+	}
+	cm.init()
+	failed := 0
+	for _, payload := range variants {
+		res := &bigcommerce.VariantUpdateResult{ID: payload.ID, Sku: payload.Sku}
+		stored, ok := cm.Variants[payload.ID]
+		if payload.ID == 0 {
+			if payload.Sku == "" {
+				res.Err = errors.New("variant has no ID and no SKU")
+			} else if stored, res.Err = cm.variantBySku(payload.Sku); res.Err != nil {
+				res.Err = bigcommerce.ErrNotFound
+			}
+		} else if !ok {
+			res.Err = bigcommerce.ErrNotFound
+		}
+		if res.Err == nil {
+			v := *stored
+			res.Err = applySet(&v, payload)
+			if res.Err == nil {
+				v.ProductID = stored.ProductID
+				cm.Variants[v.ID] = &v
+				res.ID = v.ID
+				res.Sku = v.Sku
+				res.Variant = &v
+			}
+		}
+		if res.Err != nil {
+			failed++
+		}
+		results.Results = append(results.Results, res)
+		if res.ID != 0 {
+			results.ByID[res.ID] = res
+		}
+		if res.Sku != "" {
+			results.BySku[res.Sku] = res
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d variants failed to update", failed, len(variants))
+	}
+	return results, nil
+}
+
+// PatchVariants applies the patches to the variants with the ID of the patch, with per-variant results
+func (cm *CatalogClient) PatchVariants(patches []bigcommerce.Patch) (bigcommerce.VariantUpdateResults, error) {
+	results := bigcommerce.VariantUpdateResults{
+		ByID:  map[int64]*bigcommerce.VariantUpdateResult{},
+		BySku: map[string]*bigcommerce.VariantUpdateResult{},
+	}
+	if err := cm.record("PatchVariants", patches); err != nil {
+		return results, err
+	}
+	cm.init()
+	failed := 0
+	for _, patch := range patches {
+		res := &bigcommerce.VariantUpdateResult{ID: patchID(patch)}
+		stored, ok := cm.Variants[res.ID]
+		if !ok {
+			res.Err = bigcommerce.ErrNotFound
+		} else {
+			v := *stored
+			res.Err = applyPatch(&v, patch)
+			if res.Err == nil {
+				v.ID = stored.ID
+				v.ProductID = stored.ProductID
+				cm.Variants[v.ID] = &v
+				res.Sku = v.Sku
+				res.Variant = &v
+			}
+		}
+		if res.Err != nil {
+			failed++
+		}
+		results.Results = append(results.Results, res)
+		if res.ID != 0 {
+			results.ByID[res.ID] = res
+		}
+		if res.Sku != "" {
+			results.BySku[res.Sku] = res
+		}
+	}
+	if failed > 0 {
+		return results, fmt.Errorf("%d of %d variants failed to update", failed, len(patches))
+	}
+	return results, nil
+}
+
+func (cm *CatalogClient) variantBySku(sku string) (*bigcommerce.Variant, error) {
+	for _, v := range cm.Variants {
+		if v.Sku == sku {
+			return v, nil
+		}
+	}
+	return nil, errors.New("Empty response back on getting variant by sku: " + sku)
+}
+
+func (cm *CatalogClient) UpdateVariantBySku(payload *bigcommerce.Variant) (*bigcommerce.Variant, error) {
+	if err := cm.record("UpdateVariantBySku", payload); err != nil {
+		return nil, err
+	}
+	cm.init()
+	stored, err := cm.variantBySku(payload.Sku)
+	if err != nil {
+		return nil, err
+	}
+	v := *payload
+	v.ID = stored.ID
+	v.ProductID = stored.ProductID
+	cm.Variants[v.ID] = &v
+	return &v, nil
+}
+
+func (cm *CatalogClient) UpdateVariantInventory(variantPayload *bigcommerce.VariantInventory) (*bigcommerce.Variant, error) {
+	if err := cm.record("UpdateVariantInventory", variantPayload); err != nil {
+		return nil, err
+	}
+	cm.init()
+	v, ok := cm.Variants[variantPayload.ID]
+	if !ok || v.ProductID != variantPayload.ProductID {
+		return nil, bigcommerce.ErrNotFound
+	}
+	v.InventoryLevel = variantPayload.InventoryLevel
+	v.InventoryWarningLevel = variantPayload.InventoryWarningLevel
+	return v, nil
+}
+
+func (cm *CatalogClient) UpdateVariantSalePrice(variantSalePricePayload *bigcommerce.VariantSalePrice) (*bigcommerce.Variant, error) {
+	if err := cm.record("UpdateVariantSalePrice", variantSalePricePayload); err != nil {
+		return nil, err
+	}
+	cm.init()
+	v, ok := cm.Variants[variantSalePricePayload.ID]
+	if !ok || v.ProductID != variantSalePricePayload.ProductID {
+		return nil, bigcommerce.ErrNotFound
+	}
+	v.SalePrice = variantSalePricePayload.SalePrice
+	return v, nil
+}
+
+func (cm *CatalogClient) AddProductToChannel(productId int64, channelId int64) (bool, error) {
+	if err := cm.record("AddProductToChannel", productId, channelId); err != nil {
+		return false, err
+	}
+	cm.init()
+	if _, ok := cm.Products[productId]; !ok {
+		return false, bigcommerce.ErrNotFound
+	}
+	for _, c := range cm.ProductChannels[productId] {
+		if c == channelId {
+			return true, nil
+		}
+	}
+	cm.ProductChannels[productId] = append(cm.ProductChannels[productId], channelId)
+	return true, nil
+}
+
+func (cm *CatalogClient) DeleteProductFromChannel(productId int64, channelId int64) (bool, error) {
+	if err := cm.record("DeleteProductFromChannel", productId, channelId); err != nil {
+		return false, err
+	}
+	cm.init()
+	channels := cm.ProductChannels[productId]
+	for i, c := range channels {
+		if c == channelId {
+			cm.ProductChannels[productId] = append(channels[:i], channels[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
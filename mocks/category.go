@@ -0,0 +1,259 @@
+package mocks
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+func (cm *CatalogClient) initTrees() {
+	cm.init()
+	if cm.Trees == nil {
+		cm.Trees = map[int64]*bigcommerce.CatalogTree{}
+	}
+}
+
+// AddTree stores a category tree and returns it with its ID
+func (cm *CatalogClient) AddTree(t bigcommerce.CatalogTree) *bigcommerce.CatalogTree {
+	cm.initTrees()
+	if t.ID == 0 {
+		t.ID = cm.newID()
+	}
+	cm.Trees[t.ID] = &t
+	return &t
+}
+
+func (cm *CatalogClient) GetCategory(categoryID int64) (*bigcommerce.Category, error) {
+	if err := cm.record("GetCategory", categoryID); err != nil {
+		return nil, err
+	}
+	cm.init()
+	c, ok := cm.Categories[categoryID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	ret := *c
+	return &ret, nil
+}
+
+// saveCategory validates and stores a category, the parent must exist
+func (cm *CatalogClient) saveCategory(c bigcommerce.Category) (*bigcommerce.Category, error) {
+	if c.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	if c.ParentID != 0 && cm.Categories[c.ParentID] == nil {
+		return nil, fmt.Errorf("parent category %d not found", c.ParentID)
+	}
+	c.URL = c.CustomURL.URL
+	return cm.AddCategory(c), nil
+}
+
+func (cm *CatalogClient) CreateCategory(category *bigcommerce.Category) (*bigcommerce.Category, error) {
+	if err := cm.record("CreateCategory", category); err != nil {
+		return nil, err
+	}
+	cm.init()
+	c := *category
+	c.ID = 0
+	return cm.saveCategory(c)
+}
+
+func (cm *CatalogClient) UpdateCategory(category *bigcommerce.Category) (*bigcommerce.Category, error) {
+	if err := cm.record("UpdateCategory", category); err != nil {
+		return nil, err
+	}
+	cm.init()
+	if cm.Categories[category.ID] == nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return cm.saveCategory(*category)
+}
+
+// deleteCategory deletes a category with its subcategories
+func (cm *CatalogClient) deleteCategory(categoryID int64) {
+	all := []bigcommerce.Category{}
+	for _, c := range cm.Categories {
+		all = append(all, *c)
+	}
+	for _, c := range bigcommerce.NewCategoryTree(all).Descendants(categoryID) {
+		delete(cm.Categories, c.ID)
+	}
+	delete(cm.Categories, categoryID)
+}
+
+func (cm *CatalogClient) DeleteCategory(categoryID int64) error {
+	if err := cm.record("DeleteCategory", categoryID); err != nil {
+		return err
+	}
+	cm.init()
+	if cm.Categories[categoryID] == nil {
+		return bigcommerce.ErrNotFound
+	}
+	cm.deleteCategory(categoryID)
+	return nil
+}
+
+func (cm *CatalogClient) CreateCategories(categories []bigcommerce.Category) ([]bigcommerce.Category, error) {
+	if err := cm.record("CreateCategories", categories); err != nil {
+		return nil, err
+	}
+	cm.initTrees()
+	ret := []bigcommerce.Category{}
+	for _, c := range categories {
+		if cm.Trees[c.TreeID] == nil {
+			return ret, fmt.Errorf("tree %d not found", c.TreeID)
+		}
+		c.ID = 0
+		created, err := cm.saveCategory(c)
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, *created)
+	}
+	return ret, nil
+}
+
+func (cm *CatalogClient) UpdateCategories(categories []bigcommerce.Category) ([]bigcommerce.Category, error) {
+	if err := cm.record("UpdateCategories", categories); err != nil {
+		return nil, err
+	}
+	cm.init()
+	for _, c := range categories {
+		if cm.Categories[c.ID] == nil {
+			return nil, fmt.Errorf("category %d not found", c.ID)
+		}
+	}
+	ret := []bigcommerce.Category{}
+	for _, c := range categories {
+		if c.TreeID == 0 {
+			c.TreeID = cm.Categories[c.ID].TreeID
+		}
+		updated, err := cm.saveCategory(c)
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, *updated)
+	}
+	return ret, nil
+}
+
+func (cm *CatalogClient) DeleteCategories(categoryIDs []int64) error {
+	if err := cm.record("DeleteCategories", categoryIDs); err != nil {
+		return err
+	}
+	cm.init()
+	for _, id := range categoryIDs {
+		cm.deleteCategory(id)
+	}
+	return nil
+}
+
+func (cm *CatalogClient) GetCatalogTrees(channelIDs ...int64) ([]bigcommerce.CatalogTree, error) {
+	if err := cm.record("GetCatalogTrees", channelIDs); err != nil {
+		return nil, err
+	}
+	cm.initTrees()
+	ids := []int64{}
+	for id := range cm.Trees {
+		ids = append(ids, id)
+	}
+	ret := []bigcommerce.CatalogTree{}
+	for _, id := range sortedIDs(ids) {
+		t := cm.Trees[id]
+		if len(channelIDs) == 0 || sharesChannel(t.Channels, channelIDs) {
+			ret = append(ret, *t)
+		}
+	}
+	return ret, nil
+}
+
+func sharesChannel(a, b []int64) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (cm *CatalogClient) UpsertCatalogTrees(trees []bigcommerce.CatalogTree) ([]bigcommerce.CatalogTree, error) {
+	if err := cm.record("UpsertCatalogTrees", trees); err != nil {
+		return nil, err
+	}
+	cm.initTrees()
+	for _, t := range trees {
+		if t.Name == "" {
+			return nil, errors.New("name is required")
+		}
+		if t.ID != 0 && cm.Trees[t.ID] == nil {
+			return nil, fmt.Errorf("tree %d not found", t.ID)
+		}
+		for id, other := range cm.Trees {
+			if id != t.ID && sharesChannel(other.Channels, t.Channels) {
+				return nil, fmt.Errorf("a channel of tree %s is already assigned to tree %d", t.Name, id)
+			}
+		}
+	}
+	ret := []bigcommerce.CatalogTree{}
+	for _, t := range trees {
+		ret = append(ret, *cm.AddTree(t))
+	}
+	return ret, nil
+}
+
+func (cm *CatalogClient) DeleteCatalogTrees(treeIDs []int64) error {
+	if err := cm.record("DeleteCatalogTrees", treeIDs); err != nil {
+		return err
+	}
+	cm.initTrees()
+	for _, id := range treeIDs {
+		delete(cm.Trees, id)
+		for cid, c := range cm.Categories {
+			if c.TreeID == id {
+				delete(cm.Categories, cid)
+			}
+		}
+	}
+	return nil
+}
+
+func (cm *CatalogClient) GetCategoryTree(treeID int64) (*bigcommerce.CategoryTree, error) {
+	if err := cm.record("GetCategoryTree", treeID); err != nil {
+		return nil, err
+	}
+	cm.initTrees()
+	if cm.Trees[treeID] == nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return cm.tree(treeID), nil
+}
+
+func (cm *CatalogClient) tree(treeID int64) *bigcommerce.CategoryTree {
+	cs := []bigcommerce.Category{}
+	for _, c := range cm.Categories {
+		if c.TreeID == treeID {
+			cs = append(cs, *c)
+		}
+	}
+	return bigcommerce.NewCategoryTree(cs)
+}
+
+func (cm *CatalogClient) GetChannelCategoryTree(channelID int64) (*bigcommerce.CategoryTree, error) {
+	if err := cm.record("GetChannelCategoryTree", channelID); err != nil {
+		return nil, err
+	}
+	cm.initTrees()
+	ids := []int64{}
+	for id, t := range cm.Trees {
+		if sharesChannel(t.Channels, []int64{channelID}) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return cm.tree(sortedIDs(ids)[0]), nil
+}
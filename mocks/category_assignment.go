@@ -0,0 +1,175 @@
+package mocks
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+func containsID(ids []int64, id int64) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
+
+// categoryAssignments returns the assignments of the products and categories, empty lists match all
+func (cm *CatalogClient) categoryAssignments(productIDs, categoryIDs []int64) []bigcommerce.CategoryAssignment {
+	cm.init()
+	ids := []int64{}
+	for id := range cm.Products {
+		ids = append(ids, id)
+	}
+	ret := []bigcommerce.CategoryAssignment{}
+	for _, id := range sortedIDs(ids) {
+		if len(productIDs) > 0 && !containsID(productIDs, id) {
+			continue
+		}
+		for _, cat := range cm.Products[id].Categories {
+			if len(categoryIDs) == 0 || containsID(categoryIDs, cat) {
+				ret = append(ret, bigcommerce.CategoryAssignment{ProductID: id, CategoryID: cat})
+			}
+		}
+	}
+	return ret
+}
+
+func (cm *CatalogClient) GetCategoryAssignments(productIDs, categoryIDs []int64) ([]bigcommerce.CategoryAssignment, error) {
+	if err := cm.record("GetCategoryAssignments", productIDs, categoryIDs); err != nil {
+		return nil, err
+	}
+	return cm.categoryAssignments(productIDs, categoryIDs), nil
+}
+
+func (cm *CatalogClient) CreateCategoryAssignments(assignments []bigcommerce.CategoryAssignment) error {
+	if err := cm.record("CreateCategoryAssignments", assignments); err != nil {
+		return err
+	}
+	cm.init()
+	for _, a := range assignments {
+		if cm.Products[a.ProductID] == nil {
+			return fmt.Errorf("product %d not found", a.ProductID)
+		}
+		if cm.Categories[a.CategoryID] == nil {
+			return fmt.Errorf("category %d not found", a.CategoryID)
+		}
+	}
+	for _, a := range assignments {
+		p := cm.Products[a.ProductID]
+		if !containsID(p.Categories, a.CategoryID) {
+			p.Categories = append(p.Categories, a.CategoryID)
+		}
+	}
+	return nil
+}
+
+func (cm *CatalogClient) DeleteCategoryAssignments(productIDs, categoryIDs []int64) error {
+	if err := cm.record("DeleteCategoryAssignments", productIDs, categoryIDs); err != nil {
+		return err
+	}
+	if len(productIDs) == 0 && len(categoryIDs) == 0 {
+		return errors.New("no products or categories to unassign")
+	}
+	for _, a := range cm.categoryAssignments(productIDs, categoryIDs) {
+		p := cm.Products[a.ProductID]
+		kept := []int64{}
+		for _, cat := range p.Categories {
+			if cat != a.CategoryID {
+				kept = append(kept, cat)
+			}
+		}
+		p.Categories = kept
+	}
+	return nil
+}
+
+// sortOrder returns the products of a category by sort order, products without one are last
+func (cm *CatalogClient) sortOrder(categoryID int64) []bigcommerce.ProductSortOrder {
+	orders := cm.SortOrders[categoryID]
+	ret := []bigcommerce.ProductSortOrder{}
+	unsorted := []bigcommerce.ProductSortOrder{}
+	for _, a := range cm.categoryAssignments(nil, []int64{categoryID}) {
+		o, ok := orders[a.ProductID]
+		if !ok {
+			unsorted = append(unsorted, bigcommerce.ProductSortOrder{ProductID: a.ProductID})
+			continue
+		}
+		ret = append(ret, bigcommerce.ProductSortOrder{ProductID: a.ProductID, SortOrder: o})
+	}
+	sort.SliceStable(ret, func(i, j int) bool { return ret[i].SortOrder < ret[j].SortOrder })
+	for _, o := range unsorted {
+		o.SortOrder = int64(len(ret))
+		ret = append(ret, o)
+	}
+	return ret
+}
+
+func (cm *CatalogClient) GetCategorySortOrder(categoryID int64) ([]bigcommerce.ProductSortOrder, error) {
+	if err := cm.record("GetCategorySortOrder", categoryID); err != nil {
+		return nil, err
+	}
+	cm.init()
+	if cm.Categories[categoryID] == nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return cm.sortOrder(categoryID), nil
+}
+
+func (cm *CatalogClient) SetCategorySortOrder(categoryID int64, orders []bigcommerce.ProductSortOrder) error {
+	if err := cm.record("SetCategorySortOrder", categoryID, orders); err != nil {
+		return err
+	}
+	return cm.setSortOrder(categoryID, orders)
+}
+
+func (cm *CatalogClient) setSortOrder(categoryID int64, orders []bigcommerce.ProductSortOrder) error {
+	cm.init()
+	if cm.Categories[categoryID] == nil {
+		return bigcommerce.ErrNotFound
+	}
+	for _, o := range orders {
+		p := cm.Products[o.ProductID]
+		if p == nil || !containsID(p.Categories, categoryID) {
+			return fmt.Errorf("product %d is not assigned to category %d", o.ProductID, categoryID)
+		}
+	}
+	if cm.SortOrders == nil {
+		cm.SortOrders = map[int64]map[int64]int64{}
+	}
+	if cm.SortOrders[categoryID] == nil {
+		cm.SortOrders[categoryID] = map[int64]int64{}
+	}
+	for _, o := range orders {
+		cm.SortOrders[categoryID][o.ProductID] = o.SortOrder
+	}
+	return nil
+}
+
+func (cm *CatalogClient) SortCategoryProducts(categoryID int64, productIDs []int64) error {
+	if err := cm.record("SortCategoryProducts", categoryID, productIDs); err != nil {
+		return err
+	}
+	cm.init()
+	if cm.Categories[categoryID] == nil {
+		return bigcommerce.ErrNotFound
+	}
+	orders := []bigcommerce.ProductSortOrder{}
+	placed := map[int64]bool{}
+	for _, id := range productIDs {
+		if !placed[id] {
+			placed[id] = true
+			orders = append(orders, bigcommerce.ProductSortOrder{ProductID: id, SortOrder: int64(len(orders))})
+		}
+	}
+	for _, o := range cm.sortOrder(categoryID) {
+		if !placed[o.ProductID] {
+			placed[o.ProductID] = true
+			orders = append(orders, bigcommerce.ProductSortOrder{ProductID: o.ProductID, SortOrder: int64(len(orders))})
+		}
+	}
+	return cm.setSortOrder(categoryID, orders)
+}
@@ -0,0 +1,426 @@
+package mocks
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+var _ bigcommerce.ChannelClient = (*ChannelClient)(nil)
+
+// ChannelClient is a stateful mock of bigcommerce.ChannelClient
+type ChannelClient struct {
+	Recorder
+	Channels map[int64]*bigcommerce.Channel
+	// Menus and Currencies by channel ID
+	Menus      map[int64]*bigcommerce.ChannelMenus
+	Currencies map[int64]*bigcommerce.ChannelCurrencies
+	Sites      map[int64]*bigcommerce.Site
+	// Routes by site ID
+	Routes map[int64][]bigcommerce.SiteRoute
+	nextID int64
+}
+
+func (cm *ChannelClient) init() {
+	if cm.Channels == nil {
+		cm.Channels = map[int64]*bigcommerce.Channel{}
+	}
+	if cm.Menus == nil {
+		cm.Menus = map[int64]*bigcommerce.ChannelMenus{}
+	}
+	if cm.Currencies == nil {
+		cm.Currencies = map[int64]*bigcommerce.ChannelCurrencies{}
+	}
+	if cm.Sites == nil {
+		cm.Sites = map[int64]*bigcommerce.Site{}
+	}
+	if cm.Routes == nil {
+		cm.Routes = map[int64][]bigcommerce.SiteRoute{}
+	}
+}
+
+func (cm *ChannelClient) newID() int64 {
+	cm.nextID++
+	for cm.Channels[cm.nextID] != nil || cm.Sites[cm.nextID] != nil {
+		cm.nextID++
+	}
+	return cm.nextID
+}
+
+// AddChannel stores a channel and returns it with its ID
+func (cm *ChannelClient) AddChannel(ch bigcommerce.Channel) *bigcommerce.Channel {
+	cm.init()
+	if ch.ID == 0 {
+		ch.ID = cm.newID()
+	}
+	cm.Channels[ch.ID] = &ch
+	return &ch
+}
+
+// AddSite stores a site and returns it with its ID
+func (cm *ChannelClient) AddSite(s bigcommerce.Site) *bigcommerce.Site {
+	cm.init()
+	if s.ID == 0 {
+		s.ID = cm.newID()
+	}
+	cm.Sites[s.ID] = &s
+	return &s
+}
+
+func (cm *ChannelClient) GetChannel(channelID int64) (*bigcommerce.Channel, error) {
+	if err := cm.record("GetChannel", channelID); err != nil {
+		return nil, err
+	}
+	cm.init()
+	ch, ok := cm.Channels[channelID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	ret := *ch
+	return &ret, nil
+}
+
+func (cm *ChannelClient) CreateChannel(channel *bigcommerce.Channel) (*bigcommerce.Channel, error) {
+	if err := cm.record("CreateChannel", channel); err != nil {
+		return nil, err
+	}
+	cm.init()
+	if channel.Name == "" || channel.Type == "" || channel.Platform == "" {
+		return nil, errors.New("name, type and platform are required")
+	}
+	ch := *channel
+	ch.ID = 0
+	if ch.Status == "" {
+		ch.Status = "active"
+	}
+	ch.DateCreated = time.Now()
+	ch.DateModified = ch.DateCreated
+	return cm.AddChannel(ch), nil
+}
+
+func (cm *ChannelClient) UpdateChannel(channel *bigcommerce.Channel) (*bigcommerce.Channel, error) {
+	if err := cm.record("UpdateChannel", channel); err != nil {
+		return nil, err
+	}
+	cm.init()
+	old, ok := cm.Channels[channel.ID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	ch := *channel
+	ch.Type, ch.Platform = old.Type, old.Platform
+	ch.DateCreated = old.DateCreated
+	ch.DateModified = time.Now()
+	return cm.AddChannel(ch), nil
+}
+
+func (cm *ChannelClient) GetChannelMenus(channelID int64) (*bigcommerce.ChannelMenus, error) {
+	if err := cm.record("GetChannelMenus", channelID); err != nil {
+		return nil, err
+	}
+	cm.init()
+	if cm.Channels[channelID] == nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	ret := bigcommerce.ChannelMenus{}
+	if m := cm.Menus[channelID]; m != nil {
+		ret = *m
+	}
+	return &ret, nil
+}
+
+func (cm *ChannelClient) SetChannelMenus(channelID int64, menus *bigcommerce.ChannelMenus) (*bigcommerce.ChannelMenus, error) {
+	if err := cm.record("SetChannelMenus", channelID, menus); err != nil {
+		return nil, err
+	}
+	cm.init()
+	if cm.Channels[channelID] == nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	m := *menus
+	cm.Menus[channelID] = &m
+	return &m, nil
+}
+
+func (cm *ChannelClient) DeleteChannelMenus(channelID int64) error {
+	if err := cm.record("DeleteChannelMenus", channelID); err != nil {
+		return err
+	}
+	cm.init()
+	delete(cm.Menus, channelID)
+	return nil
+}
+
+func (cm *ChannelClient) GetAllChannelCurrencies() ([]bigcommerce.ChannelCurrencies, error) {
+	if err := cm.record("GetAllChannelCurrencies"); err != nil {
+		return nil, err
+	}
+	cm.init()
+	ids := []int64{}
+	for id := range cm.Currencies {
+		ids = append(ids, id)
+	}
+	ret := []bigcommerce.ChannelCurrencies{}
+	for _, id := range sortedIDs(ids) {
+		ret = append(ret, *cm.Currencies[id])
+	}
+	return ret, nil
+}
+
+func (cm *ChannelClient) GetChannelCurrencies(channelID int64) (*bigcommerce.ChannelCurrencies, error) {
+	if err := cm.record("GetChannelCurrencies", channelID); err != nil {
+		return nil, err
+	}
+	cm.init()
+	c, ok := cm.Currencies[channelID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	ret := *c
+	return &ret, nil
+}
+
+// saveCurrencies validates and stores the currency assignments of a channel
+func (cm *ChannelClient) saveCurrencies(currencies *bigcommerce.ChannelCurrencies) (*bigcommerce.ChannelCurrencies, error) {
+	if cm.Channels[currencies.ChannelID] == nil {
+		return nil, fmt.Errorf("channel %d not found", currencies.ChannelID)
+	}
+	found := false
+	for _, code := range currencies.EnabledCurrencies {
+		found = found || code == currencies.DefaultCurrency
+	}
+	if !found {
+		return nil, fmt.Errorf("default currency %s is not enabled", currencies.DefaultCurrency)
+	}
+	c := *currencies
+	cm.Currencies[c.ChannelID] = &c
+	return &c, nil
+}
+
+func (cm *ChannelClient) CreateChannelCurrencies(currencies *bigcommerce.ChannelCurrencies) (*bigcommerce.ChannelCurrencies, error) {
+	if err := cm.record("CreateChannelCurrencies", currencies); err != nil {
+		return nil, err
+	}
+	cm.init()
+	if cm.Currencies[currencies.ChannelID] != nil {
+		return nil, fmt.Errorf("channel %d already has currency assignments", currencies.ChannelID)
+	}
+	return cm.saveCurrencies(currencies)
+}
+
+func (cm *ChannelClient) UpdateChannelCurrencies(currencies *bigcommerce.ChannelCurrencies) (*bigcommerce.ChannelCurrencies, error) {
+	if err := cm.record("UpdateChannelCurrencies", currencies); err != nil {
+		return nil, err
+	}
+	cm.init()
+	if cm.Currencies[currencies.ChannelID] == nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return cm.saveCurrencies(currencies)
+}
+
+func (cm *ChannelClient) DeleteChannelCurrencies(channelID int64) error {
+	if err := cm.record("DeleteChannelCurrencies", channelID); err != nil {
+		return err
+	}
+	cm.init()
+	delete(cm.Currencies, channelID)
+	return nil
+}
+
+func (cm *ChannelClient) GetSites(channelIDs ...int64) ([]bigcommerce.Site, error) {
+	if err := cm.record("GetSites", channelIDs); err != nil {
+		return nil, err
+	}
+	cm.init()
+	ids := []int64{}
+	for id := range cm.Sites {
+		ids = append(ids, id)
+	}
+	ret := []bigcommerce.Site{}
+	for _, id := range sortedIDs(ids) {
+		s := cm.Sites[id]
+		if len(channelIDs) == 0 || containsID(channelIDs, s.ChannelID) {
+			ret = append(ret, *s)
+		}
+	}
+	return ret, nil
+}
+
+func (cm *ChannelClient) GetSite(siteID int64) (*bigcommerce.Site, error) {
+	if err := cm.record("GetSite", siteID); err != nil {
+		return nil, err
+	}
+	cm.init()
+	s, ok := cm.Sites[siteID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	ret := *s
+	return &ret, nil
+}
+
+// channelSite returns the site of a channel, nil if it has none
+func (cm *ChannelClient) channelSite(channelID int64) *bigcommerce.Site {
+	for _, s := range cm.Sites {
+		if s.ChannelID == channelID {
+			return s
+		}
+	}
+	return nil
+}
+
+func (cm *ChannelClient) GetChannelSite(channelID int64) (*bigcommerce.Site, error) {
+	if err := cm.record("GetChannelSite", channelID); err != nil {
+		return nil, err
+	}
+	cm.init()
+	s := cm.channelSite(channelID)
+	if s == nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	ret := *s
+	return &ret, nil
+}
+
+func (cm *ChannelClient) CreateSite(site *bigcommerce.Site) (*bigcommerce.Site, error) {
+	if err := cm.record("CreateSite", site); err != nil {
+		return nil, err
+	}
+	cm.init()
+	if site.URL == "" {
+		return nil, errors.New("url is required")
+	}
+	if cm.Channels[site.ChannelID] == nil {
+		return nil, fmt.Errorf("channel %d not found", site.ChannelID)
+	}
+	if cm.channelSite(site.ChannelID) != nil {
+		return nil, fmt.Errorf("channel %d already has a site", site.ChannelID)
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	return cm.AddSite(bigcommerce.Site{URL: site.URL, ChannelID: site.ChannelID, CreatedAt: now, UpdatedAt: now}), nil
+}
+
+func (cm *ChannelClient) UpdateSite(site *bigcommerce.Site) (*bigcommerce.Site, error) {
+	if err := cm.record("UpdateSite", site); err != nil {
+		return nil, err
+	}
+	cm.init()
+	s, ok := cm.Sites[site.ID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	s.URL = site.URL
+	s.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	ret := *s
+	return &ret, nil
+}
+
+func (cm *ChannelClient) DeleteSite(siteID int64) error {
+	if err := cm.record("DeleteSite", siteID); err != nil {
+		return err
+	}
+	cm.init()
+	if cm.Sites[siteID] == nil {
+		return bigcommerce.ErrNotFound
+	}
+	delete(cm.Sites, siteID)
+	delete(cm.Routes, siteID)
+	return nil
+}
+
+func (cm *ChannelClient) GetSiteRoutes(siteID int64, routeType string) ([]bigcommerce.SiteRoute, error) {
+	if err := cm.record("GetSiteRoutes", siteID, routeType); err != nil {
+		return nil, err
+	}
+	cm.init()
+	if cm.Sites[siteID] == nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	ret := []bigcommerce.SiteRoute{}
+	for _, r := range cm.Routes[siteID] {
+		if routeType == "" || r.Type == routeType {
+			ret = append(ret, r)
+		}
+	}
+	return ret, nil
+}
+
+func (cm *ChannelClient) hasRoute(siteID, routeID int64) bool {
+	for _, r := range cm.Routes[siteID] {
+		if r.ID == routeID {
+			return true
+		}
+	}
+	return false
+}
+
+// saveRoute updates the route of the site with the same ID, or adds it with a new ID
+func (cm *ChannelClient) saveRoute(siteID int64, route bigcommerce.SiteRoute) bigcommerce.SiteRoute {
+	for i, r := range cm.Routes[siteID] {
+		if route.ID != 0 && r.ID == route.ID {
+			cm.Routes[siteID][i] = route
+			return route
+		}
+	}
+	cm.nextID++
+	route.ID = cm.nextID
+	cm.Routes[siteID] = append(cm.Routes[siteID], route)
+	return route
+}
+
+func (cm *ChannelClient) CreateSiteRoute(siteID int64, route *bigcommerce.SiteRoute) (*bigcommerce.SiteRoute, error) {
+	if err := cm.record("CreateSiteRoute", siteID, route); err != nil {
+		return nil, err
+	}
+	cm.init()
+	if cm.Sites[siteID] == nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	if route.Type == "" || route.Route == "" {
+		return nil, errors.New("type and route are required")
+	}
+	r := *route
+	r.ID = 0
+	r = cm.saveRoute(siteID, r)
+	return &r, nil
+}
+
+func (cm *ChannelClient) UpsertSiteRoutes(siteID int64, routes []bigcommerce.SiteRoute) ([]bigcommerce.SiteRoute, error) {
+	if err := cm.record("UpsertSiteRoutes", siteID, routes); err != nil {
+		return nil, err
+	}
+	cm.init()
+	if cm.Sites[siteID] == nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	for _, r := range routes {
+		if r.Type == "" || r.Route == "" {
+			return nil, errors.New("type and route are required")
+		}
+		if r.ID != 0 && !cm.hasRoute(siteID, r.ID) {
+			return nil, fmt.Errorf("route %d not found", r.ID)
+		}
+	}
+	ret := []bigcommerce.SiteRoute{}
+	for _, r := range routes {
+		ret = append(ret, cm.saveRoute(siteID, r))
+	}
+	return ret, nil
+}
+
+func (cm *ChannelClient) DeleteSiteRoute(siteID, routeID int64) error {
+	if err := cm.record("DeleteSiteRoute", siteID, routeID); err != nil {
+		return err
+	}
+	cm.init()
+	for i, r := range cm.Routes[siteID] {
+		if r.ID == routeID {
+			cm.Routes[siteID] = append(cm.Routes[siteID][:i], cm.Routes[siteID][i+1:]...)
+			return nil
+		}
+	}
+	return bigcommerce.ErrNotFound
+}
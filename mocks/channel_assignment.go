@@ -0,0 +1,144 @@
+package mocks
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+// channelAssignments returns the assignments of the products and channels, empty lists match all
+func (cm *CatalogClient) channelAssignments(productIDs, channelIDs []int64) []bigcommerce.ChannelAssignment {
+	cm.init()
+	ids := []int64{}
+	for id := range cm.ProductChannels {
+		ids = append(ids, id)
+	}
+	ret := []bigcommerce.ChannelAssignment{}
+	for _, id := range sortedIDs(ids) {
+		if len(productIDs) > 0 && !containsID(productIDs, id) {
+			continue
+		}
+		for _, ch := range cm.ProductChannels[id] {
+			if len(channelIDs) == 0 || containsID(channelIDs, ch) {
+				ret = append(ret, bigcommerce.ChannelAssignment{ProductID: id, ChannelID: ch})
+			}
+		}
+	}
+	return ret
+}
+
+func (cm *CatalogClient) GetChannelAssignments(productIDs, channelIDs []int64) ([]bigcommerce.ChannelAssignment, error) {
+	if err := cm.record("GetChannelAssignments", productIDs, channelIDs); err != nil {
+		return nil, err
+	}
+	return cm.channelAssignments(productIDs, channelIDs), nil
+}
+
+// assignChannels stores the assignments, all the products must exist
+func (cm *CatalogClient) assignChannels(assignments []bigcommerce.ChannelAssignment) error {
+	cm.init()
+	for _, a := range assignments {
+		if cm.Products[a.ProductID] == nil {
+			return fmt.Errorf("product %d not found", a.ProductID)
+		}
+	}
+	for _, a := range assignments {
+		if !containsID(cm.ProductChannels[a.ProductID], a.ChannelID) {
+			cm.ProductChannels[a.ProductID] = append(cm.ProductChannels[a.ProductID], a.ChannelID)
+		}
+	}
+	return nil
+}
+
+// CreateChannelAssignments stores the assignments, like the API nothing is assigned when a product
+// is missing: the assignments of missing products fail with their own error, the others with the batch error
+func (cm *CatalogClient) CreateChannelAssignments(assignments []bigcommerce.ChannelAssignment) (map[int]error, error) {
+	if err := cm.record("CreateChannelAssignments", assignments); err != nil {
+		return nil, err
+	}
+	cm.init()
+	failed := map[int]error{}
+	for i, a := range assignments {
+		if cm.Products[a.ProductID] == nil {
+			failed[i] = fmt.Errorf("product %d not found", a.ProductID)
+		}
+	}
+	if len(failed) > 0 {
+		for i := range assignments {
+			if failed[i] == nil {
+				failed[i] = errors.New("batch rejected")
+			}
+		}
+		return failed, fmt.Errorf("%d of %d channel assignments failed", len(failed), len(assignments))
+	}
+	return failed, cm.assignChannels(assignments)
+}
+
+func (cm *CatalogClient) AssignProductsToChannels(productIDs, channelIDs []int64) error {
+	if err := cm.record("AssignProductsToChannels", productIDs, channelIDs); err != nil {
+		return err
+	}
+	assignments := []bigcommerce.ChannelAssignment{}
+	for _, p := range productIDs {
+		for _, c := range channelIDs {
+			assignments = append(assignments, bigcommerce.ChannelAssignment{ProductID: p, ChannelID: c})
+		}
+	}
+	return cm.assignChannels(assignments)
+}
+
+// unassignChannels removes the matching assignments
+func (cm *CatalogClient) unassignChannels(productIDs, channelIDs []int64) {
+	for _, a := range cm.channelAssignments(productIDs, channelIDs) {
+		kept := []int64{}
+		for _, ch := range cm.ProductChannels[a.ProductID] {
+			if ch != a.ChannelID {
+				kept = append(kept, ch)
+			}
+		}
+		cm.ProductChannels[a.ProductID] = kept
+	}
+}
+
+func (cm *CatalogClient) DeleteChannelAssignments(productIDs, channelIDs []int64) error {
+	if err := cm.record("DeleteChannelAssignments", productIDs, channelIDs); err != nil {
+		return err
+	}
+	if len(productIDs) == 0 && len(channelIDs) == 0 {
+		return errors.New("no products or channels to unassign")
+	}
+	cm.unassignChannels(productIDs, channelIDs)
+	return nil
+}
+
+func (cm *CatalogClient) ReconcileProductChannels(productID int64, channelIDs []int64) (*bigcommerce.ChannelReconcileResult, error) {
+	if err := cm.record("ReconcileProductChannels", productID, channelIDs); err != nil {
+		return nil, err
+	}
+	cm.init()
+	if cm.Products[productID] == nil {
+		return nil, fmt.Errorf("product %d not found", productID)
+	}
+	ret := &bigcommerce.ChannelReconcileResult{}
+	current := cm.ProductChannels[productID]
+	for _, ch := range sortedIDs(append([]int64{}, current...)) {
+		if containsID(channelIDs, ch) {
+			ret.Unchanged = append(ret.Unchanged, ch)
+		} else {
+			ret.Removed = append(ret.Removed, ch)
+		}
+	}
+	for _, ch := range sortedIDs(append([]int64{}, channelIDs...)) {
+		if !containsID(current, ch) && !containsID(ret.Added, ch) {
+			ret.Added = append(ret.Added, ch)
+		}
+	}
+	if len(ret.Removed) > 0 {
+		cm.unassignChannels([]int64{productID}, ret.Removed)
+	}
+	for _, ch := range ret.Added {
+		cm.ProductChannels[productID] = append(cm.ProductChannels[productID], ch)
+	}
+	return ret, nil
+}
@@ -0,0 +1,108 @@
+package mocks
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+func (cm *CatalogClient) initListings() {
+	cm.init()
+	if cm.Listings == nil {
+		cm.Listings = map[int64]*bigcommerce.ChannelListing{}
+	}
+}
+
+// AddListing stores a channel listing and returns it with its listing ID
+func (cm *CatalogClient) AddListing(l bigcommerce.ChannelListing) *bigcommerce.ChannelListing {
+	cm.initListings()
+	if l.ListingID == 0 {
+		l.ListingID = cm.newID()
+	}
+	for i := range l.Variants {
+		l.Variants[i].ProductID = l.ProductID
+	}
+	cm.Listings[l.ListingID] = &l
+	return &l
+}
+
+func (cm *CatalogClient) GetChannelListings(channelID int64, productIDs []int64) ([]bigcommerce.ChannelListing, error) {
+	if err := cm.record("GetChannelListings", channelID, productIDs); err != nil {
+		return nil, err
+	}
+	cm.initListings()
+	ids := []int64{}
+	for id := range cm.Listings {
+		ids = append(ids, id)
+	}
+	ret := []bigcommerce.ChannelListing{}
+	for _, id := range sortedIDs(ids) {
+		l := cm.Listings[id]
+		if l.ChannelID == channelID && (len(productIDs) == 0 || containsID(productIDs, l.ProductID)) {
+			ret = append(ret, *l)
+		}
+	}
+	return ret, nil
+}
+
+func (cm *CatalogClient) GetChannelListing(channelID, listingID int64) (*bigcommerce.ChannelListing, error) {
+	if err := cm.record("GetChannelListing", channelID, listingID); err != nil {
+		return nil, err
+	}
+	cm.initListings()
+	l, ok := cm.Listings[listingID]
+	if !ok || l.ChannelID != channelID {
+		return nil, bigcommerce.ErrNotFound
+	}
+	ret := *l
+	return &ret, nil
+}
+
+func (cm *CatalogClient) CreateChannelListings(channelID int64, listings []bigcommerce.ChannelListing) ([]bigcommerce.ChannelListing, error) {
+	if err := cm.record("CreateChannelListings", channelID, listings); err != nil {
+		return nil, err
+	}
+	cm.initListings()
+	for _, l := range listings {
+		if cm.Products[l.ProductID] == nil {
+			return nil, fmt.Errorf("product %d not found", l.ProductID)
+		}
+		for _, other := range cm.Listings {
+			if other.ChannelID == channelID && other.ProductID == l.ProductID {
+				return nil, fmt.Errorf("product %d is already listed in channel %d", l.ProductID, channelID)
+			}
+		}
+	}
+	ret := []bigcommerce.ChannelListing{}
+	for _, l := range listings {
+		l.ListingID = 0
+		l.ChannelID = channelID
+		l.DateCreated = time.Now()
+		l.DateModified = l.DateCreated
+		ret = append(ret, *cm.AddListing(l))
+	}
+	return ret, nil
+}
+
+func (cm *CatalogClient) UpdateChannelListings(channelID int64, listings []bigcommerce.ChannelListing) ([]bigcommerce.ChannelListing, error) {
+	if err := cm.record("UpdateChannelListings", channelID, listings); err != nil {
+		return nil, err
+	}
+	cm.initListings()
+	for _, l := range listings {
+		if old, ok := cm.Listings[l.ListingID]; !ok || old.ChannelID != channelID {
+			return nil, fmt.Errorf("listing %d not found", l.ListingID)
+		}
+	}
+	ret := []bigcommerce.ChannelListing{}
+	for _, l := range listings {
+		old := cm.Listings[l.ListingID]
+		l.ChannelID = channelID
+		l.ProductID = old.ProductID
+		l.DateCreated = old.DateCreated
+		l.DateModified = time.Now()
+		ret = append(ret, *cm.AddListing(l))
+	}
+	return ret, nil
+}
@@ -0,0 +1,67 @@
+package mocks
+
+import (
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+var _ bigcommerce.CheckoutClient = (*CheckoutClient)(nil)
+
+// CheckoutClient is a stateful mock of bigcommerce.CheckoutClient.
+// Checkouts are looked up in Checkouts first, then built from the carts of Carts if set.
+type CheckoutClient struct {
+	Recorder
+	Checkouts map[string]*bigcommerce.Checkout
+	Carts     *CartClient
+}
+
+// checkout returns the checkout with the given ID, nil if it doesn't exist
+func (cm *CheckoutClient) checkout(checkoutID string) *bigcommerce.Checkout {
+	if cm.Checkouts == nil {
+		cm.Checkouts = map[string]*bigcommerce.Checkout{}
+	}
+	if co, ok := cm.Checkouts[checkoutID]; ok {
+		if cm.Carts != nil && cm.Carts.Carts[checkoutID] != nil {
+			co.Cart = *cm.Carts.Carts[checkoutID]
+		}
+		return co
+	}
+	if cm.Carts == nil || cm.Carts.Carts[checkoutID] == nil {
+		return nil
+	}
+	co := &bigcommerce.Checkout{ID: checkoutID, Cart: *cm.Carts.Carts[checkoutID]}
+	cm.Checkouts[checkoutID] = co
+	return co
+}
+
+func (cm *CheckoutClient) GetCheckout(checkoutID string) (*bigcommerce.Checkout, error) {
+	if err := cm.record("GetCheckout", checkoutID); err != nil {
+		return nil, err
+	}
+	co := cm.checkout(checkoutID)
+	if co == nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	co.SubtotalExTax = co.Cart.CartAmount
+	co.SubtotalIncTax = co.Cart.CartAmount
+	co.GrandTotal = co.Cart.CartAmount + co.ShippingCostTotalIncTax + co.HandlingCostTotalIncTax
+	return co, nil
+}
+
+func (cm *CheckoutClient) AddDiscountToCheckout(checkoutID string, discountAmount float64, discountName string) (*bigcommerce.Cart, error) {
+	if err := cm.record("AddDiscountToCheckout", checkoutID, discountAmount, discountName); err != nil {
+		return nil, err
+	}
+	co := cm.checkout(checkoutID)
+	if co == nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	cart := &co.Cart
+	if cm.Carts != nil && cm.Carts.Carts[checkoutID] != nil {
+		cart = cm.Carts.Carts[checkoutID]
+	}
+	cart.Discounts = append(cart.Discounts, bigcommerce.Discount{ID: discountName, DiscountedAmount: discountAmount})
+	cart.DiscountAmount += discountAmount
+	cart.CartAmount = cart.BaseAmount - cart.DiscountAmount
+	co.Cart = *cart
+	return cart, nil
+}
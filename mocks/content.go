@@ -0,0 +1,292 @@
+package mocks
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+var (
+	_ bigcommerce.CouponClient  = (*CouponClient)(nil)
+	_ bigcommerce.WebhookClient = (*WebhookClient)(nil)
+	_ bigcommerce.ContentClient = (*ContentClient)(nil)
+)
+
+// CouponClient is a stateful mock of bigcommerce.CouponClient
+type CouponClient struct {
+	Recorder
+	Coupons map[int64]*bigcommerce.Coupon
+	nextID  int64
+}
+
+func (cm *CouponClient) init() {
+	if cm.Coupons == nil {
+		cm.Coupons = map[int64]*bigcommerce.Coupon{}
+	}
+}
+
+func (cm *CouponClient) CreateCoupon(coupon bigcommerce.Coupon) (*bigcommerce.Coupon, error) {
+	if err := cm.record("CreateCoupon", coupon); err != nil {
+		return nil, err
+	}
+	cm.init()
+	if coupon.Code == "" || coupon.Name == "" {
+		return nil, errors.New("name and code are required")
+	}
+	for _, c := range cm.Coupons {
+		if c.Code == coupon.Code {
+			return nil, errors.New("The coupon code " + coupon.Code + " is a duplicate")
+		}
+		if c.ID > cm.nextID {
+			cm.nextID = c.ID
+		}
+	}
+	cm.nextID++
+	coupon.ID = cm.nextID
+	coupon.DateCreated = time.Now().UTC().Format(time.RFC1123Z)
+	cm.Coupons[coupon.ID] = &coupon
+	return &coupon, nil
+}
+
+func (cm *CouponClient) GetCoupon(couponID int64) (*bigcommerce.Coupon, error) {
+	if err := cm.record("GetCoupon", couponID); err != nil {
+		return nil, err
+	}
+	cm.init()
+	c, ok := cm.Coupons[couponID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return c, nil
+}
+
+func (cm *CouponClient) UpdateCoupon(couponID int64, coupon bigcommerce.Coupon) (*bigcommerce.Coupon, error) {
+	if err := cm.record("UpdateCoupon", couponID, coupon); err != nil {
+		return nil, err
+	}
+	cm.init()
+	stored, ok := cm.Coupons[couponID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	coupon.ID = couponID
+	coupon.DateCreated = stored.DateCreated
+	cm.Coupons[couponID] = &coupon
+	return &coupon, nil
+}
+
+func (cm *CouponClient) DeleteCoupon(couponID int64) error {
+	if err := cm.record("DeleteCoupon", couponID); err != nil {
+		return err
+	}
+	cm.init()
+	if _, ok := cm.Coupons[couponID]; !ok {
+		return bigcommerce.ErrNotFound
+	}
+	delete(cm.Coupons, couponID)
+	return nil
+}
+
+func (cm *CouponClient) coupons(args map[string]string) []bigcommerce.Coupon {
+	cm.init()
+	ids := []int64{}
+	for id := range cm.Coupons {
+		ids = append(ids, id)
+	}
+	ret := []bigcommerce.Coupon{}
+	for _, id := range sortedIDs(ids) {
+		c := cm.Coupons[id]
+		if matchArgs(args, map[string]string{"id": itoa(c.ID), "code": c.Code, "name": c.Name, "type": c.Type}) {
+			ret = append(ret, *c)
+		}
+	}
+	return ret
+}
+
+func (cm *CouponClient) GetAllCoupons(args map[string]string) ([]bigcommerce.Coupon, error) {
+	if err := cm.record("GetAllCoupons", args); err != nil {
+		return nil, err
+	}
+	return cm.coupons(args), nil
+}
+
+func (cm *CouponClient) GetCoupons(args map[string]string, page int) ([]bigcommerce.Coupon, bool, error) {
+	if err := cm.record("GetCoupons", args, page); err != nil {
+		return nil, false, err
+	}
+	cs := cm.coupons(args)
+	from, to, more := pageBounds(len(cs), args, page)
+	return cs[from:to], more, nil
+}
+
+// WebhookClient is a stateful mock of bigcommerce.WebhookClient
+type WebhookClient struct {
+	Recorder
+	Webhooks []bigcommerce.Webhook
+}
+
+func (wm *WebhookClient) GetWebhooks() ([]bigcommerce.Webhook, error) {
+	if err := wm.record("GetWebhooks"); err != nil {
+		return nil, err
+	}
+	return wm.Webhooks, nil
+}
+
+// CreateWebhook creates a webhook, or activates an existing one with the same scope and destination
+func (wm *WebhookClient) CreateWebhook(scope, destination string, headers map[string]string) (int64, error) {
+	if err := wm.record("CreateWebhook", scope, destination, headers); err != nil {
+		return 0, err
+	}
+	now := time.Now().Unix()
+	var id int64
+	for i, w := range wm.Webhooks {
+		if w.Scope == scope && w.Destination == destination {
+			wm.Webhooks[i].IsActive = true
+			wm.Webhooks[i].UpdatedAt = now
+			return w.ID, nil
+		}
+		if w.ID > id {
+			id = w.ID
+		}
+	}
+	wm.Webhooks = append(wm.Webhooks, bigcommerce.Webhook{
+		ID:          id + 1,
+		Scope:       scope,
+		Destination: destination,
+		Headers:     headers,
+		IsActive:    true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	})
+	return id + 1, nil
+}
+
+// ContentClient is a stateful mock of bigcommerce.ContentClient
+type ContentClient struct {
+	Recorder
+	Scripts         []bigcommerce.Script
+	WidgetTemplates []bigcommerce.PageBuilderTemplate
+	Themes          []bigcommerce.Theme
+	// ThemeConfigs by theme UUID
+	ThemeConfigs map[string]*bigcommerce.ThemeConfig
+	nextID       int
+}
+
+// newUUID returns a unique fake UUID
+func (cm *ContentClient) newUUID() string {
+	cm.nextID++
+	return "00000000-0000-4000-8000-" + leftPad(strconv.Itoa(cm.nextID), 12)
+}
+
+func leftPad(s string, n int) string {
+	for len(s) < n {
+		s = "0" + s
+	}
+	return s
+}
+
+func (cm *ContentClient) CreateScript(s *bigcommerce.Script) (*bigcommerce.Script, error) {
+	if err := cm.record("CreateScript", s); err != nil {
+		return nil, err
+	}
+	if s.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	script := *s
+	script.ID = cm.newUUID()
+	script.DateCreated = time.Now().UTC()
+	script.DateModified = script.DateCreated
+	cm.Scripts = append(cm.Scripts, script)
+	return &script, nil
+}
+
+func (cm *ContentClient) GetScriptByID(uuid string) (*bigcommerce.Script, error) {
+	if err := cm.record("GetScriptByID", uuid); err != nil {
+		return nil, err
+	}
+	for i := range cm.Scripts {
+		if cm.Scripts[i].ID == uuid {
+			return &cm.Scripts[i], nil
+		}
+	}
+	return nil, bigcommerce.ErrNotFound
+}
+
+func (cm *ContentClient) GetScripts() ([]bigcommerce.Script, error) {
+	if err := cm.record("GetScripts"); err != nil {
+		return nil, err
+	}
+	return cm.Scripts, nil
+}
+
+func (cm *ContentClient) CreateWidgetTemplate(pt *bigcommerce.PageBuilderTemplate) (*bigcommerce.PageBuilderTemplate, error) {
+	if err := cm.record("CreateWidgetTemplate", pt); err != nil {
+		return nil, err
+	}
+	if pt.Name == "" || pt.Template == "" {
+		return nil, errors.New("name and template are required")
+	}
+	t := *pt
+	t.UUID = cm.newUUID()
+	t.CurrentVersionUUID = cm.newUUID()
+	t.DateCreated = time.Now().UTC()
+	t.DateModified = t.DateCreated
+	cm.WidgetTemplates = append(cm.WidgetTemplates, t)
+	return &t, nil
+}
+
+func (cm *ContentClient) GetWidgetTemplates() ([]bigcommerce.PageBuilderTemplate, error) {
+	if err := cm.record("GetWidgetTemplates"); err != nil {
+		return nil, err
+	}
+	return cm.WidgetTemplates, nil
+}
+
+func (cm *ContentClient) DeleteWidgetTemplate(uuid string) error {
+	if err := cm.record("DeleteWidgetTemplate", uuid); err != nil {
+		return err
+	}
+	for i, t := range cm.WidgetTemplates {
+		if t.UUID == uuid {
+			cm.WidgetTemplates = append(cm.WidgetTemplates[:i], cm.WidgetTemplates[i+1:]...)
+			return nil
+		}
+	}
+	return bigcommerce.ErrNotFound
+}
+
+func (cm *ContentClient) GetThemes() ([]bigcommerce.Theme, error) {
+	if err := cm.record("GetThemes"); err != nil {
+		return nil, err
+	}
+	return cm.Themes, nil
+}
+
+func (cm *ContentClient) GetThemeConfig(uuid string) (*bigcommerce.ThemeConfig, error) {
+	if err := cm.record("GetThemeConfig", uuid); err != nil {
+		return nil, err
+	}
+	cfg, ok := cm.ThemeConfigs[uuid]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return cfg, nil
+}
+
+// GetActiveThemeConfig returns the configuration of the active theme, an empty one if no theme is active
+func (cm *ContentClient) GetActiveThemeConfig() (*bigcommerce.ThemeConfig, error) {
+	if err := cm.record("GetActiveThemeConfig"); err != nil {
+		return nil, err
+	}
+	for _, t := range cm.Themes {
+		if t.IsActive {
+			if cfg, ok := cm.ThemeConfigs[t.UUID]; ok {
+				return cfg, nil
+			}
+			return nil, bigcommerce.ErrNotFound
+		}
+	}
+	return &bigcommerce.ThemeConfig{}, nil
+}
@@ -1,44 +1,309 @@
 package mocks
 
 import (
-	"github.com/gpmd/bigcommerce-api-go"
+	"errors"
+	"strings"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
 )
 
+var _ bigcommerce.CustomerClient = (*CustomerClient)(nil)
+
+// CustomerClient is a stateful mock of bigcommerce.CustomerClient.
+// CustomerID, Email, Password, Customer and FormFields seed a single customer like in
+// earlier versions of the mock; Customers, Passwords and Fields hold the full state.
 type CustomerClient struct {
+	Recorder
 	CustomerID int64
 	Email      string
 	Password   string
 	Customer   *bigcommerce.Customer
 	FormFields []bigcommerce.FormField
+
+	Customers map[int64]*bigcommerce.Customer
+	// Passwords are the customer passwords by customer ID
+	Passwords map[int64]string
+	// Fields are the form field values by customer ID
+	Fields map[int64][]bigcommerce.FormField
+	Groups []bigcommerce.CustomerGroup
+	nextID int64
+}
+
+// init creates the maps and moves the seed fields into them
+func (cm *CustomerClient) init() {
+	if cm.Customers != nil {
+		return
+	}
+	cm.Customers = map[int64]*bigcommerce.Customer{}
+	if cm.Passwords == nil {
+		cm.Passwords = map[int64]string{}
+	}
+	if cm.Fields == nil {
+		cm.Fields = map[int64][]bigcommerce.FormField{}
+	}
+	id := cm.CustomerID
+	if cm.Customer != nil && cm.Customer.ID != 0 {
+		id = cm.Customer.ID
+	}
+	if id == 0 {
+		id = 1
+	}
+	if cm.Customer != nil {
+		c := *cm.Customer
+		c.ID = id
+		cm.Customers[id] = &c
+	} else if cm.Email != "" {
+		cm.Customers[id] = &bigcommerce.Customer{ID: id, Email: cm.Email}
+	}
+	if cm.Email != "" && cm.Password != "" {
+		cm.Passwords[id] = cm.Password
+	}
+	if cm.FormFields != nil {
+		cm.Fields[id] = cm.FormFields
+	}
+}
+
+// AddCustomer stores a customer, assigning an ID if it has none, and returns it
+func (cm *CustomerClient) AddCustomer(c bigcommerce.Customer, password string) *bigcommerce.Customer {
+	cm.init()
+	if c.ID == 0 {
+		c.ID = cm.newID()
+	}
+	cm.Customers[c.ID] = &c
+	if password != "" {
+		cm.Passwords[c.ID] = password
+	}
+	return &c
+}
+
+func (cm *CustomerClient) newID() int64 {
+	for id := range cm.Customers {
+		if id > cm.nextID {
+			cm.nextID = id
+		}
+	}
+	cm.nextID++
+	return cm.nextID
+}
+
+func (cm *CustomerClient) byEmail(email string) *bigcommerce.Customer {
+	for _, c := range cm.Customers {
+		if strings.EqualFold(c.Email, email) {
+			return c
+		}
+	}
+	return nil
 }
 
 func (cm *CustomerClient) ValidateCredentials(email, password string) (int64, error) {
-	if email == cm.Email && password == cm.Password {
-		return 1, nil
+	if err := cm.record("ValidateCredentials", email, password); err != nil {
+		return 0, err
+	}
+	return cm.validateCredentials(email, password)
+}
+
+// ValidateCredentialsInChannel checks the credentials like ValidateCredentials, customers are not
+// limited to channels in the mock
+func (cm *CustomerClient) ValidateCredentialsInChannel(channelID int, email, password string) (int64, error) {
+	if err := cm.record("ValidateCredentialsInChannel", channelID, email, password); err != nil {
+		return 0, err
 	}
-	return 0, bigcommerce.ErrNotFound
+	return cm.validateCredentials(email, password)
+}
+
+func (cm *CustomerClient) validateCredentials(email, password string) (int64, error) {
+	cm.init()
+	c := cm.byEmail(email)
+	if c == nil || cm.Passwords[c.ID] == "" || cm.Passwords[c.ID] != password {
+		return 0, bigcommerce.ErrNotFound
+	}
+	return c.ID, nil
 }
 
 func (cm *CustomerClient) CreateAccount(customer *bigcommerce.CreateAccountPayload) (*bigcommerce.Customer, error) {
-	return cm.Customer, nil
+	if err := cm.record("CreateAccount", customer); err != nil {
+		return nil, err
+	}
+	cm.init()
+	if customer.Email == "" || customer.FirstName == "" || customer.LastName == "" {
+		return nil, errors.New("email, first_name and last_name are required")
+	}
+	if cm.byEmail(customer.Email) != nil {
+		return nil, errors.New("The email " + customer.Email + " is already in use")
+	}
+	c := bigcommerce.Customer{
+		Company:       customer.Company,
+		Firstname:     customer.FirstName,
+		Lastname:      customer.LastName,
+		Email:         customer.Email,
+		Phone:         customer.Phone,
+		Notes:         customer.Notes,
+		TaxExempt:     customer.TaxExemptCategory,
+		CustomerGroup: customer.CustomerGroupID,
+		ResetPassword: customer.Authentication.ForcePasswordReset,
+		Addresses:     customer.Addresses,
+	}
+	return cm.AddCustomer(c, customer.Authentication.Password), nil
+}
+
+// SaveAccount updates the non-empty fields of the payload
+func (cm *CustomerClient) SaveAccount(customer *bigcommerce.SaveAccountPayload) (*bigcommerce.Customer, error) {
+	if err := cm.record("SaveAccount", customer); err != nil {
+		return nil, err
+	}
+	cm.init()
+	c, ok := cm.Customers[customer.ID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	if customer.Email != "" && !strings.EqualFold(customer.Email, c.Email) && cm.byEmail(customer.Email) != nil {
+		return nil, errors.New("The email " + customer.Email + " is already in use")
+	}
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	set(&c.Company, customer.Company)
+	set(&c.Firstname, customer.FirstName)
+	set(&c.Lastname, customer.LastName)
+	set(&c.Email, customer.Email)
+	set(&c.Phone, customer.Phone)
+	set(&c.Notes, customer.Notes)
+	set(&c.TaxExempt, customer.TaxExemptCategory)
+	if customer.CustomerGroupID != 0 {
+		c.CustomerGroup = customer.CustomerGroupID
+	}
+	if customer.Addresses != nil {
+		c.Addresses = customer.Addresses
+	}
+	if customer.Authentication.NewPassword != "" {
+		cm.Passwords[c.ID] = customer.Authentication.NewPassword
+	}
+	c.ResetPassword = customer.Authentication.ForcePasswordReset
+	return c, nil
+}
+
+func (cm *CustomerClient) PatchCustomer(customerID int64, patch bigcommerce.Patch) (*bigcommerce.Customer, error) {
+	if err := cm.record("PatchCustomer", customerID, patch); err != nil {
+		return nil, err
+	}
+	cm.init()
+	stored, ok := cm.Customers[customerID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	if email, ok := patch["email"].(string); ok && !strings.EqualFold(email, stored.Email) && cm.byEmail(email) != nil {
+		return nil, errors.New("The email " + email + " is already in use")
+	}
+	c := *stored
+	if err := applyPatch(&c, patch); err != nil {
+		return nil, err
+	}
+	c.ID = customerID
+	cm.Customers[customerID] = &c
+	return &c, nil
 }
 
 func (cm *CustomerClient) CustomerSetFormFields(customerID int64, formFields []bigcommerce.FormField) error {
+	if err := cm.record("CustomerSetFormFields", customerID, formFields); err != nil {
+		return err
+	}
+	cm.init()
+	if customerID == 0 {
+		return errors.New("customerID cannot be 0")
+	}
+	fields := cm.Fields[customerID]
+	for _, f := range formFields {
+		f.CustomerID = customerID
+		replaced := false
+		for i := range fields {
+			if fields[i].Name == f.Name {
+				fields[i] = f
+				replaced = true
+			}
+		}
+		if !replaced {
+			fields = append(fields, f)
+		}
+	}
+	cm.Fields[customerID] = fields
 	return nil
 }
 
 func (cm *CustomerClient) CustomerGetFormFields(customerID int64) ([]bigcommerce.FormField, error) {
-	return cm.FormFields, nil
+	if err := cm.record("CustomerGetFormFields", customerID); err != nil {
+		return nil, err
+	}
+	cm.init()
+	return cm.Fields[customerID], nil
 }
 
 func (cm *CustomerClient) GetCustomerByEmail(email string) (*bigcommerce.Customer, error) {
-	return cm.Customer, nil
+	if err := cm.record("GetCustomerByEmail", email); err != nil {
+		return nil, err
+	}
+	cm.init()
+	c := cm.byEmail(email)
+	if c == nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return c, nil
 }
 
 func (cm *CustomerClient) GetCustomerByID(customerID int64) (*bigcommerce.Customer, error) {
-	return cm.Customer, nil
+	if err := cm.record("GetCustomerByID", customerID); err != nil {
+		return nil, err
+	}
+	cm.init()
+	c, ok := cm.Customers[customerID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return c, nil
 }
 
-func (cm *CustomerClient) SaveAccount(customer *bigcommerce.SaveAccountPayload) (*bigcommerce.Customer, error) {
-	return cm.Customer, nil
+// customers returns the customers matching the id, email and customer_group_id arguments
+func (cm *CustomerClient) customers(args map[string]string) []bigcommerce.Customer {
+	ids := []int64{}
+	for id := range cm.Customers {
+		ids = append(ids, id)
+	}
+	ret := []bigcommerce.Customer{}
+	for _, id := range sortedIDs(ids) {
+		c := cm.Customers[id]
+		if matchArgs(args, map[string]string{
+			"id":                itoa(c.ID),
+			"email":             c.Email,
+			"customer_group_id": itoa(c.CustomerGroup),
+		}) {
+			ret = append(ret, *c)
+		}
+	}
+	return ret
+}
+
+func (cm *CustomerClient) GetAllCustomers(args map[string]string) ([]bigcommerce.Customer, error) {
+	if err := cm.record("GetAllCustomers", args); err != nil {
+		return nil, err
+	}
+	cm.init()
+	return cm.customers(args), nil
+}
+
+func (cm *CustomerClient) GetCustomers(args map[string]string, page int) ([]bigcommerce.Customer, bool, error) {
+	if err := cm.record("GetCustomers", args, page); err != nil {
+		return nil, false, err
+	}
+	cm.init()
+	cs := cm.customers(args)
+	from, to, more := pageBounds(len(cs), args, page)
+	return cs[from:to], more, nil
+}
+
+func (cm *CustomerClient) GetCustomerGroups() ([]bigcommerce.CustomerGroup, error) {
+	if err := cm.record("GetCustomerGroups"); err != nil {
+		return nil, err
+	}
+	return cm.Groups, nil
 }
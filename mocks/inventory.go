@@ -0,0 +1,397 @@
+package mocks
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+var _ bigcommerce.InventoryClient = (*InventoryClient)(nil)
+
+// InventoryClient is a stateful mock of bigcommerce.InventoryClient, it starts with the default location
+type InventoryClient struct {
+	Recorder
+	Locations map[int64]*bigcommerce.InventoryLocation
+	// Items by location ID, then by variant ID, or by product ID for items without variant
+	Items map[int64]map[int64]*bigcommerce.LocationItem
+	// Skus are the variant IDs by SKU, used to resolve items identified by SKU
+	Skus   map[string]int64
+	nextID int64
+}
+
+func (im *InventoryClient) init() {
+	if im.Locations == nil {
+		im.Locations = map[int64]*bigcommerce.InventoryLocation{
+			bigcommerce.DefaultLocationID: {
+				ID:                   bigcommerce.DefaultLocationID,
+				Code:                 "BC-LOCATION-1",
+				Label:                "Default location",
+				TypeID:               "PHYSICAL",
+				Enabled:              true,
+				StorefrontVisibility: true,
+			},
+		}
+	}
+	if im.Items == nil {
+		im.Items = map[int64]map[int64]*bigcommerce.LocationItem{}
+	}
+	if im.Skus == nil {
+		im.Skus = map[string]int64{}
+	}
+}
+
+func (im *InventoryClient) newID() int64 {
+	im.nextID++
+	for im.Locations[im.nextID] != nil {
+		im.nextID++
+	}
+	return im.nextID
+}
+
+// AddLocation stores a location and returns it with its ID
+func (im *InventoryClient) AddLocation(l bigcommerce.InventoryLocation) *bigcommerce.InventoryLocation {
+	im.init()
+	if l.ID == 0 {
+		l.ID = im.newID()
+	}
+	im.Locations[l.ID] = &l
+	return &l
+}
+
+// SetItem stores the inventory of an item in a location, identified like in adjustments
+func (im *InventoryClient) SetItem(locationID int64, item bigcommerce.LocationItem) error {
+	im.init()
+	key, err := im.itemKey(item.Identity)
+	if err != nil {
+		return err
+	}
+	item.Identity = im.identity(item.Identity, key)
+	item.AvailableToSell = available(item)
+	if im.Items[locationID] == nil {
+		im.Items[locationID] = map[int64]*bigcommerce.LocationItem{}
+	}
+	im.Items[locationID][key] = &item
+	return nil
+}
+
+// itemKey returns the key of an item in Items
+func (im *InventoryClient) itemKey(id bigcommerce.InventoryIdentity) (int64, error) {
+	switch {
+	case id.VariantID != 0:
+		return id.VariantID, nil
+	case id.Sku != "":
+		v, ok := im.Skus[id.Sku]
+		if !ok {
+			return 0, fmt.Errorf("item with sku %s not found", id.Sku)
+		}
+		return v, nil
+	case id.ProductID != 0:
+		return id.ProductID, nil
+	}
+	return 0, errors.New("sku, variant_id or product_id is required")
+}
+
+// identity completes the identity of an item with its SKU and variant ID
+func (im *InventoryClient) identity(id bigcommerce.InventoryIdentity, key int64) bigcommerce.InventoryIdentity {
+	if id.VariantID == 0 && id.Sku != "" {
+		id.VariantID = key
+	}
+	for sku, v := range im.Skus {
+		if id.VariantID != 0 && v == id.VariantID {
+			id.Sku = sku
+		}
+	}
+	return id
+}
+
+func available(item bigcommerce.LocationItem) int {
+	if n := item.TotalInventoryOnhand - item.Settings.SafetyStock; n > 0 {
+		return n
+	}
+	return 0
+}
+
+func locationValues(l bigcommerce.InventoryLocation) map[string]string {
+	return map[string]string{
+		"location_id":   itoa(l.ID),
+		"location_code": l.Code,
+		"is_active":     strconv.FormatBool(l.Enabled),
+		"type_id":       l.TypeID,
+	}
+}
+
+func (im *InventoryClient) GetAllInventoryLocations(args map[string]string) ([]bigcommerce.InventoryLocation, error) {
+	if err := im.record("GetAllInventoryLocations", args); err != nil {
+		return nil, err
+	}
+	return im.locations(args), nil
+}
+
+func (im *InventoryClient) GetInventoryLocations(args map[string]string, page int) ([]bigcommerce.InventoryLocation, bool, error) {
+	if err := im.record("GetInventoryLocations", args, page); err != nil {
+		return nil, false, err
+	}
+	ls := im.locations(args)
+	from, to, more := pageBounds(len(ls), args, page)
+	return ls[from:to], more, nil
+}
+
+func (im *InventoryClient) locations(args map[string]string) []bigcommerce.InventoryLocation {
+	im.init()
+	ids := []int64{}
+	for id := range im.Locations {
+		ids = append(ids, id)
+	}
+	ret := []bigcommerce.InventoryLocation{}
+	for _, id := range sortedIDs(ids) {
+		if l := im.Locations[id]; matchArgs(args, locationValues(*l)) {
+			ret = append(ret, *l)
+		}
+	}
+	return ret
+}
+
+func (im *InventoryClient) CreateInventoryLocations(locations []bigcommerce.InventoryLocation) ([]bigcommerce.InventoryLocation, error) {
+	if err := im.record("CreateInventoryLocations", locations); err != nil {
+		return nil, err
+	}
+	im.init()
+	for _, l := range locations {
+		if l.Code == "" {
+			return nil, fmt.Errorf("location %s has no code", l.Label)
+		}
+		for _, old := range im.Locations {
+			if old.Code == l.Code {
+				return nil, fmt.Errorf("location code %s is already used", l.Code)
+			}
+		}
+	}
+	ret := []bigcommerce.InventoryLocation{}
+	for _, l := range locations {
+		l.ID = 0
+		if l.TypeID == "" {
+			l.TypeID = "PHYSICAL"
+		}
+		ret = append(ret, *im.AddLocation(l))
+	}
+	return ret, nil
+}
+
+func (im *InventoryClient) UpdateInventoryLocations(locations []bigcommerce.InventoryLocation) error {
+	if err := im.record("UpdateInventoryLocations", locations); err != nil {
+		return err
+	}
+	im.init()
+	for _, l := range locations {
+		if im.Locations[l.ID] == nil {
+			return fmt.Errorf("location %d not found", l.ID)
+		}
+	}
+	for _, l := range locations {
+		l := l
+		im.Locations[l.ID] = &l
+	}
+	return nil
+}
+
+func (im *InventoryClient) DeleteInventoryLocations(locationIDs []int64) error {
+	if err := im.record("DeleteInventoryLocations", locationIDs); err != nil {
+		return err
+	}
+	im.init()
+	if len(locationIDs) == 0 {
+		return errors.New("no locations to delete")
+	}
+	if containsID(locationIDs, bigcommerce.DefaultLocationID) {
+		return errors.New("the default location can't be deleted")
+	}
+	for _, id := range locationIDs {
+		delete(im.Locations, id)
+		delete(im.Items, id)
+	}
+	return nil
+}
+
+func (im *InventoryClient) SetInventory(reason string, adjustments []bigcommerce.InventoryAdjustment) (map[int]error, error) {
+	if err := im.record("SetInventory", reason, adjustments); err != nil {
+		return nil, err
+	}
+	return im.adjust(adjustments, func(item *bigcommerce.LocationItem, quantity int) {
+		item.TotalInventoryOnhand = quantity
+	})
+}
+
+func (im *InventoryClient) AdjustInventory(reason string, adjustments []bigcommerce.InventoryAdjustment) (map[int]error, error) {
+	if err := im.record("AdjustInventory", reason, adjustments); err != nil {
+		return nil, err
+	}
+	return im.adjust(adjustments, func(item *bigcommerce.LocationItem, quantity int) {
+		item.TotalInventoryOnhand += quantity
+	})
+}
+
+// adjust applies the valid adjustments, unlike the API which rejects batches with invalid items
+func (im *InventoryClient) adjust(adjustments []bigcommerce.InventoryAdjustment, apply func(item *bigcommerce.LocationItem, quantity int)) (map[int]error, error) {
+	im.init()
+	failed := map[int]error{}
+	for i, a := range adjustments {
+		item, err := im.item(a.LocationID, a.InventoryIdentity)
+		if err != nil {
+			failed[i] = err
+			continue
+		}
+		apply(item, a.Quantity)
+		item.AvailableToSell = available(*item)
+	}
+	if len(failed) > 0 {
+		return failed, fmt.Errorf("%d of %d inventory items failed", len(failed), len(adjustments))
+	}
+	return failed, nil
+}
+
+// item returns the inventory of an item in a location, creating it if needed
+func (im *InventoryClient) item(locationID int64, id bigcommerce.InventoryIdentity) (*bigcommerce.LocationItem, error) {
+	if im.Locations[locationID] == nil {
+		return nil, fmt.Errorf("location %d not found", locationID)
+	}
+	key, err := im.itemKey(id)
+	if err != nil {
+		return nil, err
+	}
+	if im.Items[locationID] == nil {
+		im.Items[locationID] = map[int64]*bigcommerce.LocationItem{}
+	}
+	item, ok := im.Items[locationID][key]
+	if !ok {
+		item = &bigcommerce.LocationItem{Identity: im.identity(id, key), Settings: bigcommerce.InventorySettings{IsInStock: true}}
+		im.Items[locationID][key] = item
+	}
+	return item, nil
+}
+
+func itemValues(id bigcommerce.InventoryIdentity) map[string]string {
+	return map[string]string{
+		"sku":        id.Sku,
+		"variant_id": itoa(id.VariantID),
+		"product_id": itoa(id.ProductID),
+	}
+}
+
+func (im *InventoryClient) GetAllInventoryItems(args map[string]string) ([]bigcommerce.InventoryItem, error) {
+	if err := im.record("GetAllInventoryItems", args); err != nil {
+		return nil, err
+	}
+	return im.inventoryItems(args), nil
+}
+
+func (im *InventoryClient) GetInventoryItems(args map[string]string, page int) ([]bigcommerce.InventoryItem, bool, error) {
+	if err := im.record("GetInventoryItems", args, page); err != nil {
+		return nil, false, err
+	}
+	is := im.inventoryItems(args)
+	from, to, more := pageBounds(len(is), args, page)
+	return is[from:to], more, nil
+}
+
+// inventoryItems groups the items of the locations matching args by item
+func (im *InventoryClient) inventoryItems(args map[string]string) []bigcommerce.InventoryItem {
+	im.init()
+	byKey := map[int64]*bigcommerce.InventoryItem{}
+	keys := []int64{}
+	locationArgs := map[string]string{}
+	if ids, ok := args["location_id:in"]; ok {
+		locationArgs["location_id:in"] = ids
+	}
+	for _, l := range im.locations(locationArgs) {
+		for _, key := range im.itemKeys(l.ID, args) {
+			item := im.Items[l.ID][key]
+			if byKey[key] == nil {
+				byKey[key] = &bigcommerce.InventoryItem{Identity: item.Identity}
+				keys = append(keys, key)
+			}
+			byKey[key].Locations = append(byKey[key].Locations, bigcommerce.LocationInventory{
+				LocationID:           l.ID,
+				LocationCode:         l.Code,
+				LocationName:         l.Label,
+				AvailableToSell:      item.AvailableToSell,
+				TotalInventoryOnhand: item.TotalInventoryOnhand,
+				Settings:             item.Settings,
+			})
+		}
+	}
+	ret := []bigcommerce.InventoryItem{}
+	for _, key := range sortedIDs(keys) {
+		ret = append(ret, *byKey[key])
+	}
+	return ret
+}
+
+// itemKeys returns the sorted keys of the items of a location matching args
+func (im *InventoryClient) itemKeys(locationID int64, args map[string]string) []int64 {
+	keys := []int64{}
+	for key, item := range im.Items[locationID] {
+		if matchArgs(args, itemValues(item.Identity)) {
+			keys = append(keys, key)
+		}
+	}
+	return sortedIDs(keys)
+}
+
+func (im *InventoryClient) GetAllLocationItems(locationID int64, args map[string]string) ([]bigcommerce.LocationItem, error) {
+	if err := im.record("GetAllLocationItems", locationID, args); err != nil {
+		return nil, err
+	}
+	return im.locationItems(locationID, args)
+}
+
+func (im *InventoryClient) GetLocationItems(locationID int64, args map[string]string, page int) ([]bigcommerce.LocationItem, bool, error) {
+	if err := im.record("GetLocationItems", locationID, args, page); err != nil {
+		return nil, false, err
+	}
+	is, err := im.locationItems(locationID, args)
+	if err != nil {
+		return nil, false, err
+	}
+	from, to, more := pageBounds(len(is), args, page)
+	return is[from:to], more, nil
+}
+
+func (im *InventoryClient) locationItems(locationID int64, args map[string]string) ([]bigcommerce.LocationItem, error) {
+	im.init()
+	if im.Locations[locationID] == nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	ret := []bigcommerce.LocationItem{}
+	for _, key := range im.itemKeys(locationID, args) {
+		ret = append(ret, *im.Items[locationID][key])
+	}
+	return ret, nil
+}
+
+func (im *InventoryClient) UpdateLocationItemSettings(locationID int64, settings []bigcommerce.InventoryItemSettings) (map[int]error, error) {
+	if err := im.record("UpdateLocationItemSettings", locationID, settings); err != nil {
+		return nil, err
+	}
+	im.init()
+	failed := map[int]error{}
+	for i, s := range settings {
+		item, err := im.item(locationID, s.Identity)
+		if err != nil {
+			failed[i] = err
+			continue
+		}
+		current := item.Settings
+		if err := applyPatch(&current, s.Settings); err != nil {
+			failed[i] = err
+			continue
+		}
+		item.Settings = current
+		item.AvailableToSell = available(*item)
+	}
+	if len(failed) > 0 {
+		return failed, fmt.Errorf("%d of %d inventory items failed", len(failed), len(settings))
+	}
+	return failed, nil
+}
@@ -0,0 +1,283 @@
+package mocks
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+var _ bigcommerce.MetafieldClient = (*MetafieldClient)(nil)
+
+// MetafieldClient is a stateful mock of bigcommerce.MetafieldClient.
+// Metafields belong to the owner with their ResourceType and ResourceID, ResourceKey for carts.
+type MetafieldClient struct {
+	Recorder
+	// Metafields by ID
+	Metafields map[int64]*bigcommerce.Metafield
+	nextID     int64
+}
+
+func (mm *MetafieldClient) init() {
+	if mm.Metafields == nil {
+		mm.Metafields = map[int64]*bigcommerce.Metafield{}
+	}
+}
+
+func (mm *MetafieldClient) newID() int64 {
+	for id := range mm.Metafields {
+		if id > mm.nextID {
+			mm.nextID = id
+		}
+	}
+	mm.nextID++
+	return mm.nextID
+}
+
+// resourceID returns the ID of the resource a metafield belongs to
+func resourceID(m *bigcommerce.Metafield) string {
+	if m.ResourceKey != "" {
+		return m.ResourceKey
+	}
+	return itoa(m.ResourceID)
+}
+
+// AddMetafield stores a metafield of an owner, assigning an ID if it has none
+func (mm *MetafieldClient) AddMetafield(owner bigcommerce.MetafieldOwner, m bigcommerce.Metafield) *bigcommerce.Metafield {
+	mm.init()
+	mm.setOwner(&m, owner)
+	if m.ID == 0 {
+		m.ID = mm.newID()
+	}
+	if m.PermissionSet == "" {
+		m.PermissionSet = "app_only"
+	}
+	now := time.Now().UTC()
+	if m.DateCreated.IsZero() {
+		m.DateCreated = now
+	}
+	m.DateModified = now
+	mm.Metafields[m.ID] = &m
+	return &m
+}
+
+func (mm *MetafieldClient) setOwner(m *bigcommerce.Metafield, owner bigcommerce.MetafieldOwner) {
+	m.ResourceType = owner.Type
+	m.ResourceKey = ""
+	m.ResourceID = 0
+	if owner.Type == bigcommerce.MetafieldCart {
+		m.ResourceKey = owner.ID
+		return
+	}
+	m.ResourceID, _ = strconv.ParseInt(owner.ID, 10, 64)
+}
+
+func (mm *MetafieldClient) owned(owner bigcommerce.MetafieldOwner) []bigcommerce.Metafield {
+	mm.init()
+	ids := []int64{}
+	for id, m := range mm.Metafields {
+		if m.ResourceType == owner.Type && resourceID(m) == owner.ID {
+			ids = append(ids, id)
+		}
+	}
+	ret := []bigcommerce.Metafield{}
+	for _, id := range sortedIDs(ids) {
+		ret = append(ret, *mm.Metafields[id])
+	}
+	return ret
+}
+
+func (mm *MetafieldClient) metafield(owner bigcommerce.MetafieldOwner, metafieldID int64) (*bigcommerce.Metafield, error) {
+	mm.init()
+	m, ok := mm.Metafields[metafieldID]
+	if !ok || m.ResourceType != owner.Type || resourceID(m) != owner.ID {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return m, nil
+}
+
+func (mm *MetafieldClient) GetAllMetafields(owner bigcommerce.MetafieldOwner, args map[string]string) ([]bigcommerce.Metafield, error) {
+	if err := mm.record("GetAllMetafields", owner, args); err != nil {
+		return nil, err
+	}
+	return mm.filter(owner, args), nil
+}
+
+func (mm *MetafieldClient) GetMetafields(owner bigcommerce.MetafieldOwner, args map[string]string, page int) ([]bigcommerce.Metafield, bool, error) {
+	if err := mm.record("GetMetafields", owner, args, page); err != nil {
+		return nil, false, err
+	}
+	ms := mm.filter(owner, args)
+	from, to, more := pageBounds(len(ms), args, page)
+	return ms[from:to], more, nil
+}
+
+func (mm *MetafieldClient) filter(owner bigcommerce.MetafieldOwner, args map[string]string) []bigcommerce.Metafield {
+	ret := []bigcommerce.Metafield{}
+	for _, m := range mm.owned(owner) {
+		if matchArgs(args, map[string]string{"namespace": m.Namespace, "key": m.Key}) {
+			ret = append(ret, m)
+		}
+	}
+	return ret
+}
+
+func (mm *MetafieldClient) GetMetafield(owner bigcommerce.MetafieldOwner, metafieldID int64) (*bigcommerce.Metafield, error) {
+	if err := mm.record("GetMetafield", owner, metafieldID); err != nil {
+		return nil, err
+	}
+	m, err := mm.metafield(owner, metafieldID)
+	if err != nil {
+		return nil, err
+	}
+	ret := *m
+	return &ret, nil
+}
+
+func (mm *MetafieldClient) GetMetafieldByKey(owner bigcommerce.MetafieldOwner, namespace, key string) (*bigcommerce.Metafield, error) {
+	if err := mm.record("GetMetafieldByKey", owner, namespace, key); err != nil {
+		return nil, err
+	}
+	for _, m := range mm.owned(owner) {
+		if m.Namespace == namespace && m.Key == key {
+			return &m, nil
+		}
+	}
+	return nil, bigcommerce.ErrNotFound
+}
+
+func (mm *MetafieldClient) CreateMetafield(owner bigcommerce.MetafieldOwner, metafield *bigcommerce.Metafield) (*bigcommerce.Metafield, error) {
+	if err := mm.record("CreateMetafield", owner, metafield); err != nil {
+		return nil, err
+	}
+	return mm.create(owner, *metafield)
+}
+
+func (mm *MetafieldClient) create(owner bigcommerce.MetafieldOwner, m bigcommerce.Metafield) (*bigcommerce.Metafield, error) {
+	if m.Namespace == "" || m.Key == "" || m.Value == "" {
+		return nil, errors.New("namespace, key and value are required")
+	}
+	for _, existing := range mm.owned(owner) {
+		if existing.Namespace == m.Namespace && existing.Key == m.Key {
+			return nil, errors.New("A metafield with this namespace and key already exists")
+		}
+	}
+	m.ID = 0
+	m.DateCreated = time.Time{}
+	ret := *mm.AddMetafield(owner, m)
+	return &ret, nil
+}
+
+func (mm *MetafieldClient) UpdateMetafield(owner bigcommerce.MetafieldOwner, metafield *bigcommerce.Metafield) (*bigcommerce.Metafield, error) {
+	if err := mm.record("UpdateMetafield", owner, metafield); err != nil {
+		return nil, err
+	}
+	existing, err := mm.metafield(owner, metafield.ID)
+	if err != nil {
+		return nil, err
+	}
+	m := *metafield
+	m.DateCreated = existing.DateCreated
+	if m.PermissionSet == "" {
+		m.PermissionSet = existing.PermissionSet
+	}
+	ret := *mm.AddMetafield(owner, m)
+	return &ret, nil
+}
+
+func (mm *MetafieldClient) DeleteMetafield(owner bigcommerce.MetafieldOwner, metafieldID int64) error {
+	if err := mm.record("DeleteMetafield", owner, metafieldID); err != nil {
+		return err
+	}
+	if _, err := mm.metafield(owner, metafieldID); err != nil {
+		return err
+	}
+	delete(mm.Metafields, metafieldID)
+	return nil
+}
+
+func (mm *MetafieldClient) UpsertMetafield(owner bigcommerce.MetafieldOwner, metafield *bigcommerce.Metafield) (*bigcommerce.Metafield, error) {
+	if err := mm.record("UpsertMetafield", owner, metafield); err != nil {
+		return nil, err
+	}
+	for _, existing := range mm.owned(owner) {
+		if existing.Namespace == metafield.Namespace && existing.Key == metafield.Key {
+			m := *metafield
+			m.ID = existing.ID
+			m.DateCreated = existing.DateCreated
+			if m.PermissionSet == "" {
+				m.PermissionSet = existing.PermissionSet
+			}
+			ret := *mm.AddMetafield(owner, m)
+			return &ret, nil
+		}
+	}
+	return mm.create(owner, *metafield)
+}
+
+// batchOwner returns the owner of a metafield in a batch request, variants need no product ID here
+func batchOwner(resourceType string, m bigcommerce.Metafield) bigcommerce.MetafieldOwner {
+	if m.ResourceKey != "" {
+		return bigcommerce.MetafieldOwner{Type: resourceType, ID: m.ResourceKey}
+	}
+	return bigcommerce.MetafieldOwner{Type: resourceType, ID: itoa(m.ResourceID)}
+}
+
+func (mm *MetafieldClient) CreateMetafields(resourceType string, metafields []bigcommerce.Metafield) ([]bigcommerce.Metafield, error) {
+	if err := mm.record("CreateMetafields", resourceType, metafields); err != nil {
+		return nil, err
+	}
+	mm.init()
+	for _, m := range metafields {
+		if m.ResourceID == 0 && m.ResourceKey == "" {
+			return nil, errors.New("resource_id is required")
+		}
+	}
+	ret := []bigcommerce.Metafield{}
+	for _, m := range metafields {
+		created, err := mm.create(batchOwner(resourceType, m), m)
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, *created)
+	}
+	return ret, nil
+}
+
+func (mm *MetafieldClient) UpdateMetafields(resourceType string, metafields []bigcommerce.Metafield) ([]bigcommerce.Metafield, error) {
+	if err := mm.record("UpdateMetafields", resourceType, metafields); err != nil {
+		return nil, err
+	}
+	mm.init()
+	for _, m := range metafields {
+		existing, ok := mm.Metafields[m.ID]
+		if !ok || existing.ResourceType != resourceType {
+			return nil, bigcommerce.ErrNotFound
+		}
+	}
+	ret := []bigcommerce.Metafield{}
+	for _, m := range metafields {
+		existing := mm.Metafields[m.ID]
+		owner := batchOwner(resourceType, *existing)
+		m.DateCreated = existing.DateCreated
+		if m.PermissionSet == "" {
+			m.PermissionSet = existing.PermissionSet
+		}
+		ret = append(ret, *mm.AddMetafield(owner, m))
+	}
+	return ret, nil
+}
+
+func (mm *MetafieldClient) DeleteMetafields(resourceType string, metafieldIDs []int64) error {
+	if err := mm.record("DeleteMetafields", resourceType, metafieldIDs); err != nil {
+		return err
+	}
+	mm.init()
+	for _, id := range metafieldIDs {
+		if m, ok := mm.Metafields[id]; ok && m.ResourceType == resourceType {
+			delete(mm.Metafields, id)
+		}
+	}
+	return nil
+}
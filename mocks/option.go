@@ -0,0 +1,356 @@
+package mocks
+
+import (
+	"errors"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+var _ bigcommerce.OptionClient = (*OptionClient)(nil)
+
+// OptionClient is a stateful mock of bigcommerce.OptionClient.
+// Values are stored in the option_values of their option or modifier.
+// GetVariantByOptions resolves variants from Catalog, it returns ErrNotFound without it.
+type OptionClient struct {
+	Recorder
+	// Options and Modifiers by ID
+	Options   map[int64]*bigcommerce.VariantOption
+	Modifiers map[int64]*bigcommerce.Modifier
+	Catalog   *CatalogClient
+	nextID    int64
+}
+
+func (om *OptionClient) init() {
+	if om.Options == nil {
+		om.Options = map[int64]*bigcommerce.VariantOption{}
+	}
+	if om.Modifiers == nil {
+		om.Modifiers = map[int64]*bigcommerce.Modifier{}
+	}
+}
+
+// newID returns an ID not used by any option, modifier or value
+func (om *OptionClient) newID() int64 {
+	for id, o := range om.Options {
+		if id > om.nextID {
+			om.nextID = id
+		}
+		for _, v := range o.OptionValues {
+			if v.ID > om.nextID {
+				om.nextID = v.ID
+			}
+		}
+	}
+	for id, m := range om.Modifiers {
+		if id > om.nextID {
+			om.nextID = id
+		}
+		for _, v := range m.OptionValues {
+			if v.ID > om.nextID {
+				om.nextID = v.ID
+			}
+		}
+	}
+	om.nextID++
+	return om.nextID
+}
+
+// withValueIDs assigns IDs to the values that have none
+func (om *OptionClient) withValueIDs(values []bigcommerce.OptionValue) []bigcommerce.OptionValue {
+	ret := []bigcommerce.OptionValue{}
+	for _, v := range values {
+		if v.ID == 0 {
+			v.ID = om.newID()
+		}
+		ret = append(ret, v)
+	}
+	return ret
+}
+
+// AddVariantOption stores a variant option of a product, assigning IDs to it and its values
+func (om *OptionClient) AddVariantOption(productID int64, o bigcommerce.VariantOption) *bigcommerce.VariantOption {
+	om.init()
+	o.ProductID = productID
+	if o.ID == 0 {
+		o.ID = om.newID()
+	}
+	om.Options[o.ID] = &o
+	o.OptionValues = om.withValueIDs(o.OptionValues)
+	return &o
+}
+
+// AddModifier stores a modifier of a product, assigning IDs to it and its values
+func (om *OptionClient) AddModifier(productID int64, m bigcommerce.Modifier) *bigcommerce.Modifier {
+	om.init()
+	m.ProductID = productID
+	if m.ID == 0 {
+		m.ID = om.newID()
+	}
+	om.Modifiers[m.ID] = &m
+	m.OptionValues = om.withValueIDs(m.OptionValues)
+	return &m
+}
+
+func (om *OptionClient) option(productID, optionID int64) (*bigcommerce.VariantOption, error) {
+	om.init()
+	o, ok := om.Options[optionID]
+	if !ok || o.ProductID != productID {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return o, nil
+}
+
+func (om *OptionClient) modifier(productID, modifierID int64) (*bigcommerce.Modifier, error) {
+	om.init()
+	m, ok := om.Modifiers[modifierID]
+	if !ok || m.ProductID != productID {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return m, nil
+}
+
+func (om *OptionClient) GetVariantOptions(productID int64) ([]bigcommerce.VariantOption, error) {
+	if err := om.record("GetVariantOptions", productID); err != nil {
+		return nil, err
+	}
+	om.init()
+	ids := []int64{}
+	for id, o := range om.Options {
+		if o.ProductID == productID {
+			ids = append(ids, id)
+		}
+	}
+	ret := []bigcommerce.VariantOption{}
+	for _, id := range sortedIDs(ids) {
+		ret = append(ret, *om.Options[id])
+	}
+	return ret, nil
+}
+
+func (om *OptionClient) GetVariantOption(productID, optionID int64) (*bigcommerce.VariantOption, error) {
+	if err := om.record("GetVariantOption", productID, optionID); err != nil {
+		return nil, err
+	}
+	return om.option(productID, optionID)
+}
+
+func (om *OptionClient) CreateVariantOption(productID int64, option *bigcommerce.VariantOption) (*bigcommerce.VariantOption, error) {
+	if err := om.record("CreateVariantOption", productID, option); err != nil {
+		return nil, err
+	}
+	if option.DisplayName == "" || option.Type == "" {
+		return nil, errors.New("display_name and type are required")
+	}
+	o := *option
+	o.ID = 0
+	return om.AddVariantOption(productID, o), nil
+}
+
+func (om *OptionClient) UpdateVariantOption(productID int64, option *bigcommerce.VariantOption) (*bigcommerce.VariantOption, error) {
+	if err := om.record("UpdateVariantOption", productID, option); err != nil {
+		return nil, err
+	}
+	if _, err := om.option(productID, option.ID); err != nil {
+		return nil, err
+	}
+	return om.AddVariantOption(productID, *option), nil
+}
+
+func (om *OptionClient) DeleteVariantOption(productID, optionID int64) error {
+	if err := om.record("DeleteVariantOption", productID, optionID); err != nil {
+		return err
+	}
+	if _, err := om.option(productID, optionID); err != nil {
+		return err
+	}
+	delete(om.Options, optionID)
+	return nil
+}
+
+func (om *OptionClient) GetOptionValues(productID, optionID int64) ([]bigcommerce.OptionValue, error) {
+	if err := om.record("GetOptionValues", productID, optionID); err != nil {
+		return nil, err
+	}
+	o, err := om.option(productID, optionID)
+	if err != nil {
+		return nil, err
+	}
+	return o.OptionValues, nil
+}
+
+func (om *OptionClient) CreateOptionValue(productID, optionID int64, value *bigcommerce.OptionValue) (*bigcommerce.OptionValue, error) {
+	if err := om.record("CreateOptionValue", productID, optionID, value); err != nil {
+		return nil, err
+	}
+	o, err := om.option(productID, optionID)
+	if err != nil {
+		return nil, err
+	}
+	return om.createValue(&o.OptionValues, value)
+}
+
+func (om *OptionClient) UpdateOptionValue(productID, optionID int64, value *bigcommerce.OptionValue) (*bigcommerce.OptionValue, error) {
+	if err := om.record("UpdateOptionValue", productID, optionID, value); err != nil {
+		return nil, err
+	}
+	o, err := om.option(productID, optionID)
+	if err != nil {
+		return nil, err
+	}
+	return updateValue(o.OptionValues, value)
+}
+
+func (om *OptionClient) DeleteOptionValue(productID, optionID, valueID int64) error {
+	if err := om.record("DeleteOptionValue", productID, optionID, valueID); err != nil {
+		return err
+	}
+	o, err := om.option(productID, optionID)
+	if err != nil {
+		return err
+	}
+	return deleteValue(&o.OptionValues, valueID)
+}
+
+func (om *OptionClient) GetModifiers(productID int64) ([]bigcommerce.Modifier, error) {
+	if err := om.record("GetModifiers", productID); err != nil {
+		return nil, err
+	}
+	om.init()
+	ids := []int64{}
+	for id, m := range om.Modifiers {
+		if m.ProductID == productID {
+			ids = append(ids, id)
+		}
+	}
+	ret := []bigcommerce.Modifier{}
+	for _, id := range sortedIDs(ids) {
+		ret = append(ret, *om.Modifiers[id])
+	}
+	return ret, nil
+}
+
+func (om *OptionClient) GetModifier(productID, modifierID int64) (*bigcommerce.Modifier, error) {
+	if err := om.record("GetModifier", productID, modifierID); err != nil {
+		return nil, err
+	}
+	return om.modifier(productID, modifierID)
+}
+
+func (om *OptionClient) CreateModifier(productID int64, modifier *bigcommerce.Modifier) (*bigcommerce.Modifier, error) {
+	if err := om.record("CreateModifier", productID, modifier); err != nil {
+		return nil, err
+	}
+	if modifier.DisplayName == "" || modifier.Type == "" {
+		return nil, errors.New("display_name and type are required")
+	}
+	m := *modifier
+	m.ID = 0
+	return om.AddModifier(productID, m), nil
+}
+
+func (om *OptionClient) UpdateModifier(productID int64, modifier *bigcommerce.Modifier) (*bigcommerce.Modifier, error) {
+	if err := om.record("UpdateModifier", productID, modifier); err != nil {
+		return nil, err
+	}
+	if _, err := om.modifier(productID, modifier.ID); err != nil {
+		return nil, err
+	}
+	return om.AddModifier(productID, *modifier), nil
+}
+
+func (om *OptionClient) DeleteModifier(productID, modifierID int64) error {
+	if err := om.record("DeleteModifier", productID, modifierID); err != nil {
+		return err
+	}
+	if _, err := om.modifier(productID, modifierID); err != nil {
+		return err
+	}
+	delete(om.Modifiers, modifierID)
+	return nil
+}
+
+func (om *OptionClient) GetModifierValues(productID, modifierID int64) ([]bigcommerce.OptionValue, error) {
+	if err := om.record("GetModifierValues", productID, modifierID); err != nil {
+		return nil, err
+	}
+	m, err := om.modifier(productID, modifierID)
+	if err != nil {
+		return nil, err
+	}
+	return m.OptionValues, nil
+}
+
+func (om *OptionClient) CreateModifierValue(productID, modifierID int64, value *bigcommerce.OptionValue) (*bigcommerce.OptionValue, error) {
+	if err := om.record("CreateModifierValue", productID, modifierID, value); err != nil {
+		return nil, err
+	}
+	m, err := om.modifier(productID, modifierID)
+	if err != nil {
+		return nil, err
+	}
+	return om.createValue(&m.OptionValues, value)
+}
+
+func (om *OptionClient) UpdateModifierValue(productID, modifierID int64, value *bigcommerce.OptionValue) (*bigcommerce.OptionValue, error) {
+	if err := om.record("UpdateModifierValue", productID, modifierID, value); err != nil {
+		return nil, err
+	}
+	m, err := om.modifier(productID, modifierID)
+	if err != nil {
+		return nil, err
+	}
+	return updateValue(m.OptionValues, value)
+}
+
+func (om *OptionClient) DeleteModifierValue(productID, modifierID, valueID int64) error {
+	if err := om.record("DeleteModifierValue", productID, modifierID, valueID); err != nil {
+		return err
+	}
+	m, err := om.modifier(productID, modifierID)
+	if err != nil {
+		return err
+	}
+	return deleteValue(&m.OptionValues, valueID)
+}
+
+func (om *OptionClient) createValue(values *[]bigcommerce.OptionValue, value *bigcommerce.OptionValue) (*bigcommerce.OptionValue, error) {
+	if value.Label == "" {
+		return nil, errors.New("label is required")
+	}
+	v := *value
+	v.ID = om.newID()
+	*values = append(*values, v)
+	return &v, nil
+}
+
+func updateValue(values []bigcommerce.OptionValue, value *bigcommerce.OptionValue) (*bigcommerce.OptionValue, error) {
+	for i := range values {
+		if values[i].ID == value.ID {
+			values[i] = *value
+			return &values[i], nil
+		}
+	}
+	return nil, bigcommerce.ErrNotFound
+}
+
+func deleteValue(values *[]bigcommerce.OptionValue, valueID int64) error {
+	for i, v := range *values {
+		if v.ID == valueID {
+			*values = append((*values)[:i], (*values)[i+1:]...)
+			return nil
+		}
+	}
+	return bigcommerce.ErrNotFound
+}
+
+// GetVariantByOptions resolves the variant from the variants of the product in Catalog
+func (om *OptionClient) GetVariantByOptions(productID int64, choices map[int64]int64) (*bigcommerce.Variant, error) {
+	if err := om.record("GetVariantByOptions", productID, choices); err != nil {
+		return nil, err
+	}
+	if om.Catalog == nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	om.Catalog.init()
+	return bigcommerce.ResolveVariant(om.Catalog.productVariants(productID, nil), choices)
+}
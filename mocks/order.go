@@ -0,0 +1,177 @@
+package mocks
+
+import (
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+var _ bigcommerce.OrderClient = (*OrderClient)(nil)
+
+// OrderClient is a stateful mock of bigcommerce.OrderClient
+type OrderClient struct {
+	Recorder
+	Orders map[int64]*bigcommerce.Order
+	// Products, ShippingAddresses, Coupons, Transactions and Shipments by order ID
+	Products          map[int64][]bigcommerce.OrderProduct
+	ShippingAddresses map[int64][]bigcommerce.OrderShippingAddress
+	Coupons           map[int64][]bigcommerce.OrderCoupon
+	Transactions      map[int64][]bigcommerce.OrderTransaction
+	Shipments         map[int64][]bigcommerce.OrderShipmentResponse
+	nextShipmentID    int64
+}
+
+func (om *OrderClient) init() {
+	if om.Orders == nil {
+		om.Orders = map[int64]*bigcommerce.Order{}
+	}
+	if om.Products == nil {
+		om.Products = map[int64][]bigcommerce.OrderProduct{}
+	}
+	if om.ShippingAddresses == nil {
+		om.ShippingAddresses = map[int64][]bigcommerce.OrderShippingAddress{}
+	}
+	if om.Coupons == nil {
+		om.Coupons = map[int64][]bigcommerce.OrderCoupon{}
+	}
+	if om.Transactions == nil {
+		om.Transactions = map[int64][]bigcommerce.OrderTransaction{}
+	}
+	if om.Shipments == nil {
+		om.Shipments = map[int64][]bigcommerce.OrderShipmentResponse{}
+	}
+}
+
+// AddOrder stores an order and its products, assigning an ID if the order has none
+func (om *OrderClient) AddOrder(o bigcommerce.Order, products []bigcommerce.OrderProduct) *bigcommerce.Order {
+	om.init()
+	if o.ID == 0 {
+		for id := range om.Orders {
+			if id > o.ID {
+				o.ID = id
+			}
+		}
+		o.ID++
+	}
+	for i := range products {
+		products[i].OrderID = o.ID
+	}
+	om.Orders[o.ID] = &o
+	om.Products[o.ID] = products
+	return &o
+}
+
+// GetOrders returns the orders matching the customer_id, status_id, email and channel_id filters
+func (om *OrderClient) GetOrders(filters map[string]string) ([]bigcommerce.Order, error) {
+	if err := om.record("GetOrders", filters); err != nil {
+		return nil, err
+	}
+	om.init()
+	ids := []int64{}
+	for id := range om.Orders {
+		ids = append(ids, id)
+	}
+	ret := []bigcommerce.Order{}
+	for _, id := range sortedIDs(ids) {
+		o := om.Orders[id]
+		if matchArgs(filters, map[string]string{
+			"customer_id": itoa(o.CustomerID),
+			"status_id":   itoa(o.StatusID),
+			"email":       o.BillingAddress.Email,
+			"channel_id":  itoa(o.ChannelID),
+			"cart_id":     o.CartID,
+		}) {
+			ret = append(ret, *o)
+		}
+	}
+	return ret, nil
+}
+
+// GetOrder returns an order with its products, shipping addresses and coupons like the client does
+func (om *OrderClient) GetOrder(orderID int64) (*bigcommerce.Order, error) {
+	if err := om.record("GetOrder", orderID); err != nil {
+		return nil, err
+	}
+	om.init()
+	o, ok := om.Orders[orderID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	ret := *o
+	ret.Products = om.Products[orderID]
+	ret.ShippingAddresses = om.ShippingAddresses[orderID]
+	ret.Coupons = om.Coupons[orderID]
+	return &ret, nil
+}
+
+func (om *OrderClient) GetOrderProducts(orderID int64) ([]bigcommerce.OrderProduct, error) {
+	if err := om.record("GetOrderProducts", orderID); err != nil {
+		return nil, err
+	}
+	om.init()
+	if _, ok := om.Orders[orderID]; !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return om.Products[orderID], nil
+}
+
+func (om *OrderClient) GetOrderShippingAddresses(orderID int64) ([]bigcommerce.OrderShippingAddress, error) {
+	if err := om.record("GetOrderShippingAddresses", orderID); err != nil {
+		return nil, err
+	}
+	om.init()
+	if _, ok := om.Orders[orderID]; !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return om.ShippingAddresses[orderID], nil
+}
+
+func (om *OrderClient) GetOrderCoupons(orderID int64) ([]bigcommerce.OrderCoupon, error) {
+	if err := om.record("GetOrderCoupons", orderID); err != nil {
+		return nil, err
+	}
+	om.init()
+	if _, ok := om.Orders[orderID]; !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return om.Coupons[orderID], nil
+}
+
+func (om *OrderClient) GetOrderTransactions(orderID int64) ([]bigcommerce.OrderTransaction, error) {
+	if err := om.record("GetOrderTransactions", orderID); err != nil {
+		return nil, err
+	}
+	om.init()
+	if _, ok := om.Orders[orderID]; !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	return om.Transactions[orderID], nil
+}
+
+// CreateOrderShipment stores the shipment and marks the order as shipped
+func (om *OrderClient) CreateOrderShipment(orderID int64, orderShipment *bigcommerce.OrderShipment) (bigcommerce.OrderShipmentResponse, error) {
+	if err := om.record("CreateOrderShipment", orderID, orderShipment); err != nil {
+		return bigcommerce.OrderShipmentResponse{}, err
+	}
+	om.init()
+	o, ok := om.Orders[orderID]
+	if !ok {
+		return bigcommerce.OrderShipmentResponse{}, bigcommerce.ErrNotFound
+	}
+	om.nextShipmentID++
+	s := bigcommerce.OrderShipmentResponse{
+		ID:               om.nextShipmentID,
+		OrderID:          orderID,
+		CustomerID:       o.CustomerID,
+		OrderAddressID:   orderShipment.OrderAddressId,
+		DateCreated:      time.Now().UTC().Format(time.RFC1123Z),
+		TrackingNumber:   orderShipment.TrackingNumber,
+		Comments:         orderShipment.Comments,
+		ShippingProvider: orderShipment.ShippingProvider,
+	}
+	om.Shipments[orderID] = append(om.Shipments[orderID], s)
+	o.StatusID = 2
+	o.Status = "Shipped"
+	o.DateShipped = s.DateCreated
+	return s, nil
+}
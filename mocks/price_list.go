@@ -0,0 +1,398 @@
+package mocks
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+var _ bigcommerce.PriceListClient = (*PriceListClient)(nil)
+
+// PriceListClient is a stateful mock of bigcommerce.PriceListClient
+type PriceListClient struct {
+	Recorder
+	PriceLists map[int64]*bigcommerce.PriceList
+	// Records by price list ID
+	Records     map[int64][]bigcommerce.PriceRecord
+	Assignments []bigcommerce.PriceListAssignment
+	// Skus are the variant IDs by SKU, used to upsert and delete records by SKU
+	Skus map[string]int64
+	// Prices are the prices returned by GetProductPrices by variant ID, or by product ID
+	// for items without variant. The request does not change them.
+	Prices map[int64]bigcommerce.ProductPrice
+	nextID int64
+}
+
+func (pm *PriceListClient) init() {
+	if pm.PriceLists == nil {
+		pm.PriceLists = map[int64]*bigcommerce.PriceList{}
+	}
+	if pm.Records == nil {
+		pm.Records = map[int64][]bigcommerce.PriceRecord{}
+	}
+	if pm.Skus == nil {
+		pm.Skus = map[string]int64{}
+	}
+}
+
+func (pm *PriceListClient) newID() int64 {
+	pm.nextID++
+	for pm.PriceLists[pm.nextID] != nil {
+		pm.nextID++
+	}
+	return pm.nextID
+}
+
+// AddPriceList stores a price list and returns it with its ID
+func (pm *PriceListClient) AddPriceList(pl bigcommerce.PriceList) *bigcommerce.PriceList {
+	pm.init()
+	if pl.ID == 0 {
+		pl.ID = pm.newID()
+	}
+	pm.PriceLists[pl.ID] = &pl
+	return &pl
+}
+
+func (pm *PriceListClient) GetAllPriceLists(args map[string]string) ([]bigcommerce.PriceList, error) {
+	if err := pm.record("GetAllPriceLists", args); err != nil {
+		return nil, err
+	}
+	return pm.priceLists(args), nil
+}
+
+func (pm *PriceListClient) GetPriceLists(args map[string]string, page int) ([]bigcommerce.PriceList, bool, error) {
+	if err := pm.record("GetPriceLists", args, page); err != nil {
+		return nil, false, err
+	}
+	pls := pm.priceLists(args)
+	from, to, more := pageBounds(len(pls), args, page)
+	return pls[from:to], more, nil
+}
+
+func (pm *PriceListClient) priceLists(args map[string]string) []bigcommerce.PriceList {
+	pm.init()
+	ids := []int64{}
+	for id := range pm.PriceLists {
+		ids = append(ids, id)
+	}
+	ret := []bigcommerce.PriceList{}
+	for _, id := range sortedIDs(ids) {
+		pl := pm.PriceLists[id]
+		if matchArgs(args, map[string]string{"id": itoa(pl.ID), "name": pl.Name, "active": strconv.FormatBool(pl.Active)}) {
+			ret = append(ret, *pl)
+		}
+	}
+	return ret
+}
+
+func (pm *PriceListClient) GetPriceList(priceListID int64) (*bigcommerce.PriceList, error) {
+	if err := pm.record("GetPriceList", priceListID); err != nil {
+		return nil, err
+	}
+	pm.init()
+	pl, ok := pm.PriceLists[priceListID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	ret := *pl
+	return &ret, nil
+}
+
+func (pm *PriceListClient) CreatePriceList(priceList *bigcommerce.PriceList) (*bigcommerce.PriceList, error) {
+	if err := pm.record("CreatePriceList", priceList); err != nil {
+		return nil, err
+	}
+	pm.init()
+	if priceList.Name == "" {
+		return nil, errors.New("name is required")
+	}
+	now := time.Now()
+	return pm.AddPriceList(bigcommerce.PriceList{Name: priceList.Name, Active: priceList.Active, DateCreated: now, DateModified: now}), nil
+}
+
+func (pm *PriceListClient) UpdatePriceList(priceList *bigcommerce.PriceList) (*bigcommerce.PriceList, error) {
+	if err := pm.record("UpdatePriceList", priceList); err != nil {
+		return nil, err
+	}
+	pm.init()
+	pl, ok := pm.PriceLists[priceList.ID]
+	if !ok {
+		return nil, bigcommerce.ErrNotFound
+	}
+	pl.Name = priceList.Name
+	pl.Active = priceList.Active
+	pl.DateModified = time.Now()
+	ret := *pl
+	return &ret, nil
+}
+
+func (pm *PriceListClient) DeletePriceList(priceListID int64) error {
+	if err := pm.record("DeletePriceList", priceListID); err != nil {
+		return err
+	}
+	pm.init()
+	if pm.PriceLists[priceListID] == nil {
+		return bigcommerce.ErrNotFound
+	}
+	delete(pm.PriceLists, priceListID)
+	delete(pm.Records, priceListID)
+	pm.removeAssignments(map[string]string{"price_list_id": itoa(priceListID)})
+	return nil
+}
+
+func (pm *PriceListClient) GetAllPriceRecords(priceListID int64, args map[string]string) ([]bigcommerce.PriceRecord, error) {
+	if err := pm.record("GetAllPriceRecords", priceListID, args); err != nil {
+		return nil, err
+	}
+	return pm.priceRecords(priceListID, args), nil
+}
+
+func (pm *PriceListClient) GetPriceRecords(priceListID int64, args map[string]string, page int) ([]bigcommerce.PriceRecord, bool, error) {
+	if err := pm.record("GetPriceRecords", priceListID, args, page); err != nil {
+		return nil, false, err
+	}
+	rs := pm.priceRecords(priceListID, args)
+	from, to, more := pageBounds(len(rs), args, page)
+	return rs[from:to], more, nil
+}
+
+func recordValues(r bigcommerce.PriceRecord) map[string]string {
+	return map[string]string{
+		"variant_id": itoa(r.VariantID),
+		"product_id": itoa(r.ProductID),
+		"sku":        r.Sku,
+		"currency":   r.Currency,
+	}
+}
+
+func (pm *PriceListClient) priceRecords(priceListID int64, args map[string]string) []bigcommerce.PriceRecord {
+	pm.init()
+	ret := []bigcommerce.PriceRecord{}
+	for _, r := range pm.Records[priceListID] {
+		if matchArgs(args, recordValues(r)) {
+			ret = append(ret, r)
+		}
+	}
+	return ret
+}
+
+func (pm *PriceListClient) GetPriceRecord(priceListID, variantID int64, currency string) (*bigcommerce.PriceRecord, error) {
+	if err := pm.record("GetPriceRecord", priceListID, variantID, currency); err != nil {
+		return nil, err
+	}
+	pm.init()
+	for _, r := range pm.Records[priceListID] {
+		if r.VariantID == variantID && strings.EqualFold(r.Currency, currency) {
+			return &r, nil
+		}
+	}
+	return nil, bigcommerce.ErrNotFound
+}
+
+// skuOf returns the SKU of a variant in Skus
+func (pm *PriceListClient) skuOf(variantID int64) string {
+	for sku, id := range pm.Skus {
+		if id == variantID {
+			return sku
+		}
+	}
+	return ""
+}
+
+// UpsertPriceRecords saves the valid records, unlike the API which rejects batches with invalid records
+func (pm *PriceListClient) UpsertPriceRecords(priceListID int64, records []bigcommerce.PriceRecord) (map[int]error, error) {
+	if err := pm.record("UpsertPriceRecords", priceListID, records); err != nil {
+		return nil, err
+	}
+	pm.init()
+	if pm.PriceLists[priceListID] == nil {
+		return nil, bigcommerce.ErrNotFound
+	}
+	failed := map[int]error{}
+	for i, r := range records {
+		if r.VariantID == 0 {
+			id, ok := pm.Skus[r.Sku]
+			if !ok {
+				failed[i] = fmt.Errorf("variant with sku %s not found", r.Sku)
+				continue
+			}
+			r.VariantID = id
+		}
+		if r.Currency == "" {
+			failed[i] = errors.New("currency is required")
+			continue
+		}
+		r.PriceListID = priceListID
+		r.Currency = strings.ToLower(r.Currency)
+		r.Sku = pm.skuOf(r.VariantID)
+		r.CalculatedPrice = r.Price
+		if r.SalePrice != 0 {
+			r.CalculatedPrice = r.SalePrice
+		}
+		r.DateModified = time.Now()
+		replaced := false
+		for n, old := range pm.Records[priceListID] {
+			if old.VariantID == r.VariantID && old.Currency == r.Currency {
+				r.DateCreated = old.DateCreated
+				pm.Records[priceListID][n] = r
+				replaced = true
+			}
+		}
+		if !replaced {
+			r.DateCreated = r.DateModified
+			pm.Records[priceListID] = append(pm.Records[priceListID], r)
+		}
+	}
+	if len(failed) > 0 {
+		return failed, fmt.Errorf("%d of %d price records failed to save", len(failed), len(records))
+	}
+	return failed, nil
+}
+
+func (pm *PriceListClient) deleteRecords(priceListID int64, keep func(r bigcommerce.PriceRecord) bool) {
+	kept := []bigcommerce.PriceRecord{}
+	for _, r := range pm.Records[priceListID] {
+		if keep(r) {
+			kept = append(kept, r)
+		}
+	}
+	pm.Records[priceListID] = kept
+}
+
+func (pm *PriceListClient) DeletePriceRecords(priceListID int64, variantIDs []int64) error {
+	if err := pm.record("DeletePriceRecords", priceListID, variantIDs); err != nil {
+		return err
+	}
+	pm.init()
+	if len(variantIDs) == 0 {
+		return errors.New("no price records to delete")
+	}
+	pm.deleteRecords(priceListID, func(r bigcommerce.PriceRecord) bool { return !containsID(variantIDs, r.VariantID) })
+	return nil
+}
+
+func (pm *PriceListClient) DeletePriceRecordsBySku(priceListID int64, skus []string) error {
+	if err := pm.record("DeletePriceRecordsBySku", priceListID, skus); err != nil {
+		return err
+	}
+	pm.init()
+	if len(skus) == 0 {
+		return errors.New("no price records to delete")
+	}
+	pm.deleteRecords(priceListID, func(r bigcommerce.PriceRecord) bool { return !containsString(skus, r.Sku) })
+	return nil
+}
+
+func assignmentValues(a bigcommerce.PriceListAssignment) map[string]string {
+	return map[string]string{
+		"id":                itoa(a.ID),
+		"price_list_id":     itoa(a.PriceListID),
+		"customer_group_id": itoa(a.CustomerGroupID),
+		"channel_id":        itoa(a.ChannelID),
+	}
+}
+
+func (pm *PriceListClient) GetAllPriceListAssignments(args map[string]string) ([]bigcommerce.PriceListAssignment, error) {
+	if err := pm.record("GetAllPriceListAssignments", args); err != nil {
+		return nil, err
+	}
+	return pm.assignments(args), nil
+}
+
+func (pm *PriceListClient) GetPriceListAssignments(args map[string]string, page int) ([]bigcommerce.PriceListAssignment, bool, error) {
+	if err := pm.record("GetPriceListAssignments", args, page); err != nil {
+		return nil, false, err
+	}
+	as := pm.assignments(args)
+	from, to, more := pageBounds(len(as), args, page)
+	return as[from:to], more, nil
+}
+
+func (pm *PriceListClient) assignments(args map[string]string) []bigcommerce.PriceListAssignment {
+	ret := []bigcommerce.PriceListAssignment{}
+	for _, a := range pm.Assignments {
+		if matchArgs(args, assignmentValues(a)) {
+			ret = append(ret, a)
+		}
+	}
+	return ret
+}
+
+func (pm *PriceListClient) CreatePriceListAssignments(assignments []bigcommerce.PriceListAssignment) error {
+	if err := pm.record("CreatePriceListAssignments", assignments); err != nil {
+		return err
+	}
+	pm.init()
+	for _, a := range assignments {
+		if pm.PriceLists[a.PriceListID] == nil {
+			return fmt.Errorf("price list %d not found", a.PriceListID)
+		}
+		if a.CustomerGroupID == 0 && a.ChannelID == 0 {
+			return errors.New("customer_group_id or channel_id is required")
+		}
+		for _, old := range pm.Assignments {
+			if old.CustomerGroupID == a.CustomerGroupID && old.ChannelID == a.ChannelID {
+				return fmt.Errorf("customer group %d already has a price list in channel %d", a.CustomerGroupID, a.ChannelID)
+			}
+		}
+	}
+	for _, a := range assignments {
+		pm.nextID++
+		a.ID = pm.nextID
+		pm.Assignments = append(pm.Assignments, a)
+	}
+	return nil
+}
+
+func (pm *PriceListClient) removeAssignments(args map[string]string) {
+	kept := []bigcommerce.PriceListAssignment{}
+	for _, a := range pm.Assignments {
+		if !matchArgs(args, assignmentValues(a)) {
+			kept = append(kept, a)
+		}
+	}
+	pm.Assignments = kept
+}
+
+func (pm *PriceListClient) DeletePriceListAssignments(args map[string]string) error {
+	if err := pm.record("DeletePriceListAssignments", args); err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New("no price list assignments to delete")
+	}
+	pm.removeAssignments(args)
+	return nil
+}
+
+func (pm *PriceListClient) GetProductPrices(request bigcommerce.PricingRequest) ([]bigcommerce.ProductPrice, error) {
+	if err := pm.record("GetProductPrices", request); err != nil {
+		return nil, err
+	}
+	if request.ChannelID == 0 {
+		return nil, errors.New("pricing request has no channel ID")
+	}
+	ret := []bigcommerce.ProductPrice{}
+	for _, item := range request.Items {
+		key := item.VariantID
+		if key == 0 {
+			key = item.ProductID
+		}
+		p, ok := pm.Prices[key]
+		if !ok {
+			return nil, fmt.Errorf("product %d has no price", item.ProductID)
+		}
+		p.ProductID = item.ProductID
+		if item.VariantID != 0 {
+			p.VariantID = item.VariantID
+		}
+		p.Options = item.Options
+		p.ReferenceRequest = item
+		ret = append(ret, p)
+	}
+	return ret, nil
+}
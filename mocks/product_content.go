@@ -0,0 +1,229 @@
+package mocks
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+// initContent creates the maps of videos, custom fields and bulk pricing rules
+func (cm *CatalogClient) initContent() {
+	cm.init()
+	if cm.Videos == nil {
+		cm.Videos = map[int64][]bigcommerce.Video{}
+	}
+	if cm.CustomFields == nil {
+		cm.CustomFields = map[int64][]bigcommerce.CustomField{}
+	}
+	if cm.BulkPricingRules == nil {
+		cm.BulkPricingRules = map[int64][]bigcommerce.BulkPricingRule{}
+	}
+}
+
+func (cm *CatalogClient) GetProductVideos(productID int64) ([]bigcommerce.Video, error) {
+	if err := cm.record("GetProductVideos", productID); err != nil {
+		return nil, err
+	}
+	cm.initContent()
+	return cm.Videos[productID], nil
+}
+
+func (cm *CatalogClient) CreateProductVideo(productID int64, video *bigcommerce.Video) (*bigcommerce.Video, error) {
+	if err := cm.record("CreateProductVideo", productID, video); err != nil {
+		return nil, err
+	}
+	cm.initContent()
+	if video.VideoID == "" {
+		return nil, errors.New("video_id is required")
+	}
+	v := *video
+	v.ID = cm.newID()
+	v.ProductID = productID
+	if v.Type == "" {
+		v.Type = "youtube"
+	}
+	cm.Videos[productID] = append(cm.Videos[productID], v)
+	return &v, nil
+}
+
+func (cm *CatalogClient) UpdateProductVideo(productID int64, video *bigcommerce.Video) (*bigcommerce.Video, error) {
+	if err := cm.record("UpdateProductVideo", productID, video); err != nil {
+		return nil, err
+	}
+	cm.initContent()
+	for i, v := range cm.Videos[productID] {
+		if v.ID == video.ID {
+			v.Title = video.Title
+			v.Description = video.Description
+			v.SortOrder = video.SortOrder
+			cm.Videos[productID][i] = v
+			return &v, nil
+		}
+	}
+	return nil, bigcommerce.ErrNotFound
+}
+
+func (cm *CatalogClient) DeleteProductVideo(productID, videoID int64) error {
+	if err := cm.record("DeleteProductVideo", productID, videoID); err != nil {
+		return err
+	}
+	cm.initContent()
+	vs := cm.Videos[productID]
+	for i, v := range vs {
+		if v.ID == videoID {
+			cm.Videos[productID] = append(vs[:i], vs[i+1:]...)
+			return nil
+		}
+	}
+	return bigcommerce.ErrNotFound
+}
+
+func (cm *CatalogClient) GetCustomFields(productID int64) ([]bigcommerce.CustomField, error) {
+	if err := cm.record("GetCustomFields", productID); err != nil {
+		return nil, err
+	}
+	cm.initContent()
+	return cm.CustomFields[productID], nil
+}
+
+func (cm *CatalogClient) CreateCustomField(productID int64, customField *bigcommerce.CustomField) (*bigcommerce.CustomField, error) {
+	if err := cm.record("CreateCustomField", productID, customField); err != nil {
+		return nil, err
+	}
+	return cm.createCustomField(productID, customField.Name, customField.Value)
+}
+
+func (cm *CatalogClient) createCustomField(productID int64, name, value string) (*bigcommerce.CustomField, error) {
+	cm.initContent()
+	if name == "" || value == "" {
+		return nil, errors.New("name and value are required")
+	}
+	cf := bigcommerce.CustomField{ID: cm.newID(), Name: name, Value: value}
+	cm.CustomFields[productID] = append(cm.CustomFields[productID], cf)
+	return &cf, nil
+}
+
+func (cm *CatalogClient) UpdateCustomField(productID int64, customField *bigcommerce.CustomField) (*bigcommerce.CustomField, error) {
+	if err := cm.record("UpdateCustomField", productID, customField); err != nil {
+		return nil, err
+	}
+	cm.initContent()
+	for i, cf := range cm.CustomFields[productID] {
+		if cf.ID == customField.ID {
+			cm.CustomFields[productID][i] = *customField
+			ret := *customField
+			return &ret, nil
+		}
+	}
+	return nil, bigcommerce.ErrNotFound
+}
+
+func (cm *CatalogClient) DeleteCustomField(productID, customFieldID int64) error {
+	if err := cm.record("DeleteCustomField", productID, customFieldID); err != nil {
+		return err
+	}
+	cm.initContent()
+	cfs := cm.CustomFields[productID]
+	for i, cf := range cfs {
+		if cf.ID == customFieldID {
+			cm.CustomFields[productID] = append(cfs[:i], cfs[i+1:]...)
+			return nil
+		}
+	}
+	return bigcommerce.ErrNotFound
+}
+
+// SetCustomFields replaces the custom fields of a product and reports the changes like the client does
+func (cm *CatalogClient) SetCustomFields(productID int64, fields map[string]string) (*bigcommerce.CustomFieldChanges, error) {
+	if err := cm.record("SetCustomFields", productID, fields); err != nil {
+		return nil, err
+	}
+	cm.initContent()
+	ret := &bigcommerce.CustomFieldChanges{}
+	kept := []bigcommerce.CustomField{}
+	seen := map[string]bool{}
+	for _, cf := range cm.CustomFields[productID] {
+		value, wanted := fields[cf.Name]
+		switch {
+		case !wanted || seen[cf.Name]:
+			ret.Deleted = append(ret.Deleted, cf)
+			continue
+		case cf.Value == value:
+			ret.Unchanged = append(ret.Unchanged, cf)
+		default:
+			cf.Value = value
+			ret.Updated = append(ret.Updated, cf)
+		}
+		seen[cf.Name] = true
+		kept = append(kept, cf)
+	}
+	cm.CustomFields[productID] = kept
+	names := []string{}
+	for name := range fields {
+		if !seen[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cf, err := cm.createCustomField(productID, name, fields[name])
+		if err != nil {
+			return ret, err
+		}
+		ret.Created = append(ret.Created, *cf)
+	}
+	return ret, nil
+}
+
+func (cm *CatalogClient) GetBulkPricingRules(productID int64) ([]bigcommerce.BulkPricingRule, error) {
+	if err := cm.record("GetBulkPricingRules", productID); err != nil {
+		return nil, err
+	}
+	cm.initContent()
+	return cm.BulkPricingRules[productID], nil
+}
+
+func (cm *CatalogClient) CreateBulkPricingRule(productID int64, rule *bigcommerce.BulkPricingRule) (*bigcommerce.BulkPricingRule, error) {
+	if err := cm.record("CreateBulkPricingRule", productID, rule); err != nil {
+		return nil, err
+	}
+	cm.initContent()
+	if rule.QuantityMin == 0 || rule.Type == "" {
+		return nil, errors.New("quantity_min, type and amount are required")
+	}
+	r := *rule
+	r.ID = cm.newID()
+	cm.BulkPricingRules[productID] = append(cm.BulkPricingRules[productID], r)
+	return &r, nil
+}
+
+func (cm *CatalogClient) UpdateBulkPricingRule(productID int64, rule *bigcommerce.BulkPricingRule) (*bigcommerce.BulkPricingRule, error) {
+	if err := cm.record("UpdateBulkPricingRule", productID, rule); err != nil {
+		return nil, err
+	}
+	cm.initContent()
+	for i, r := range cm.BulkPricingRules[productID] {
+		if r.ID == rule.ID {
+			cm.BulkPricingRules[productID][i] = *rule
+			ret := *rule
+			return &ret, nil
+		}
+	}
+	return nil, bigcommerce.ErrNotFound
+}
+
+func (cm *CatalogClient) DeleteBulkPricingRule(productID, ruleID int64) error {
+	if err := cm.record("DeleteBulkPricingRule", productID, ruleID); err != nil {
+		return err
+	}
+	cm.initContent()
+	rs := cm.BulkPricingRules[productID]
+	for i, r := range rs {
+		if r.ID == ruleID {
+			cm.BulkPricingRules[productID] = append(rs[:i], rs[i+1:]...)
+			return nil
+		}
+	}
+	return bigcommerce.ErrNotFound
+}
@@ -0,0 +1,219 @@
+// Package mocks provides stateful in-memory implementations of the bigcommerce client
+// interfaces for unit tests. Every mock records its calls and can be told to fail.
+//
+// Use:
+//
+//	orders := &mocks.OrderClient{}
+//	orders.AddOrder(bigcommerce.Order{ID: 100, CustomerID: 1}, nil)
+//	orders.FailWith("GetOrderProducts", errors.New("boom"))
+//	handler := NewHandler(orders)
+//	...
+//	if orders.Called("GetOrder") != 1 { t.Error("GetOrder not called") }
+package mocks
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+// Call is a recorded call of a mock method
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// Recorder records the calls made to a mock, it is embedded in every mock
+type Recorder struct {
+	mu    sync.Mutex
+	calls []Call
+	errs  map[string]error
+}
+
+// record stores a call, it returns the error injected for the method, if any
+func (r *Recorder) record(method string, args ...interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Method: method, Args: args})
+	return r.errs[method]
+}
+
+// Calls returns every recorded call in order
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call{}, r.calls...)
+}
+
+// CallsTo returns the recorded calls of a method
+func (r *Recorder) CallsTo(method string) []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ret := []Call{}
+	for _, c := range r.calls {
+		if c.Method == method {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// Called returns how many times a method was called
+func (r *Recorder) Called(method string) int {
+	return len(r.CallsTo(method))
+}
+
+// FailWith makes every following call of method return err, a nil err clears it
+func (r *Recorder) FailWith(method string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.errs == nil {
+		r.errs = map[string]error{}
+	}
+	if err == nil {
+		delete(r.errs, method)
+		return
+	}
+	r.errs[method] = err
+}
+
+// ResetCalls clears the recorded calls, injected errors are kept
+func (r *Recorder) ResetCalls() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+// matchArgs checks the values of an item against filter arguments like the API does,
+// supporting field=value and field:in=a,b. Arguments the mock doesn't know are ignored.
+func matchArgs(args map[string]string, values map[string]string) bool {
+	for k, v := range args {
+		field, in := k, false
+		if strings.HasSuffix(k, ":in") {
+			field, in = strings.TrimSuffix(k, ":in"), true
+		}
+		actual, ok := values[field]
+		if !ok {
+			continue
+		}
+		if !in && actual != v {
+			return false
+		}
+		if in && !containsString(strings.Split(v, ","), actual) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, l := range list {
+		if strings.TrimSpace(l) == s {
+			return true
+		}
+	}
+	return false
+}
+
+// pageBounds returns the slice bounds of a page of n items, and whether more pages follow.
+// The page size is the limit argument, 50 by default like the API.
+func pageBounds(n int, args map[string]string, page int) (int, int, bool) {
+	limit, _ := strconv.Atoi(args["limit"])
+	if limit <= 0 {
+		limit = 50
+	}
+	if page < 1 {
+		page = 1
+	}
+	from := (page - 1) * limit
+	if from > n {
+		from = n
+	}
+	to := from + limit
+	if to > n {
+		to = n
+	}
+	return from, to, to < n
+}
+
+// sortedIDs returns the keys of an ID map in ascending order
+func sortedIDs(ids []int64) []int64 {
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+func itoa(i int64) string {
+	return strconv.FormatInt(i, 10)
+}
+
+// applyPatch sets the fields of the patch on dst like the API does, zero values included
+func applyPatch(dst interface{}, patch bigcommerce.Patch) error {
+	b, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, dst)
+}
+
+// patchID returns the "id" field of a patch, 0 if it has none
+func patchID(p bigcommerce.Patch) int64 {
+	switch id := p["id"].(type) {
+	case int64:
+		return id
+	case int:
+		return int64(id)
+	case float64:
+		return int64(id)
+	}
+	return 0
+}
+
+// applySet sets the fields of v that are not zero values on dst, like the Update methods
+// of the client send them
+func applySet(dst, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var fields map[string]interface{}
+	err = json.Unmarshal(b, &fields)
+	if err != nil {
+		return err
+	}
+	patch := bigcommerce.Patch{}
+	for k, f := range fields {
+		if f, ok := nonZero(f); ok {
+			patch[k] = f
+		}
+	}
+	return applyPatch(dst, patch)
+}
+
+// nonZero drops the zero fields of a decoded JSON value, and reports whether anything is left
+func nonZero(v interface{}) (interface{}, bool) {
+	switch t := v.(type) {
+	case nil:
+		return nil, false
+	case bool:
+		return t, t
+	case string:
+		return t, t != "" && t != "0001-01-01T00:00:00Z"
+	case float64:
+		return t, t != 0
+	case []interface{}:
+		return t, len(t) > 0
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for k, f := range t {
+			if f, ok := nonZero(f); ok {
+				m[k] = f
+			}
+		}
+		return m, len(m) > 0
+	}
+	return v, true
+}
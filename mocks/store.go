@@ -0,0 +1,140 @@
+package mocks
+
+import (
+	"net/url"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+var (
+	_ bigcommerce.StoreClient = (*StoreClient)(nil)
+	_ bigcommerce.BlogClient  = (*BlogClient)(nil)
+	_ bigcommerce.AppClient   = (*AppClient)(nil)
+)
+
+// StoreClient is a mock of bigcommerce.StoreClient returning the configured store data
+type StoreClient struct {
+	Recorder
+	Channels   []bigcommerce.Channel
+	StoreInfo  bigcommerce.StoreInfo
+	Currencies []bigcommerce.Currency
+	TaxZones   []bigcommerce.TaxZone
+	TaxRates   []bigcommerce.TaxClassRate
+}
+
+func (sm *StoreClient) GetAllChannels() ([]bigcommerce.Channel, error) {
+	if err := sm.record("GetAllChannels"); err != nil {
+		return nil, err
+	}
+	return sm.Channels, nil
+}
+
+func (sm *StoreClient) GetChannels(page int) ([]bigcommerce.Channel, bool, error) {
+	if err := sm.record("GetChannels", page); err != nil {
+		return nil, false, err
+	}
+	from, to, more := pageBounds(len(sm.Channels), nil, page)
+	return sm.Channels[from:to], more, nil
+}
+
+func (sm *StoreClient) GetStoreInfo() (bigcommerce.StoreInfo, error) {
+	if err := sm.record("GetStoreInfo"); err != nil {
+		return bigcommerce.StoreInfo{}, err
+	}
+	return sm.StoreInfo, nil
+}
+
+func (sm *StoreClient) GetCurrencies() ([]bigcommerce.Currency, error) {
+	if err := sm.record("GetCurrencies"); err != nil {
+		return nil, err
+	}
+	return sm.Currencies, nil
+}
+
+// GetTaxZones returns the zones with the given IDs, all zones if none are given
+func (sm *StoreClient) GetTaxZones(zoneIds []int) (*[]bigcommerce.TaxZone, error) {
+	if err := sm.record("GetTaxZones", zoneIds); err != nil {
+		return nil, err
+	}
+	ret := []bigcommerce.TaxZone{}
+	for _, z := range sm.TaxZones {
+		if len(zoneIds) == 0 || containsInt(zoneIds, z.ID) {
+			ret = append(ret, z)
+		}
+	}
+	return &ret, nil
+}
+
+// GetTaxRates returns the rates of the given zones, all rates if none are given
+func (sm *StoreClient) GetTaxRates(taxZoneIds []int) (*[]bigcommerce.TaxClassRate, error) {
+	if err := sm.record("GetTaxRates", taxZoneIds); err != nil {
+		return nil, err
+	}
+	ret := []bigcommerce.TaxClassRate{}
+	for _, r := range sm.TaxRates {
+		if len(taxZoneIds) == 0 || containsInt(taxZoneIds, r.TaxZoneId) {
+			ret = append(ret, r)
+		}
+	}
+	return &ret, nil
+}
+
+func containsInt(list []int, i int) bool {
+	for _, l := range list {
+		if l == i {
+			return true
+		}
+	}
+	return false
+}
+
+// BlogClient is a mock of bigcommerce.BlogClient returning the configured posts
+type BlogClient struct {
+	Recorder
+	Posts []bigcommerce.Post
+}
+
+func (bm *BlogClient) GetAllPosts() ([]bigcommerce.Post, error) {
+	if err := bm.record("GetAllPosts"); err != nil {
+		return nil, err
+	}
+	return bm.Posts, nil
+}
+
+func (bm *BlogClient) GetPosts(page int) ([]bigcommerce.Post, bool, error) {
+	if err := bm.record("GetPosts", page); err != nil {
+		return nil, false, err
+	}
+	from, to, more := pageBounds(len(bm.Posts), map[string]string{"limit": "250"}, page)
+	return bm.Posts[from:to], more, nil
+}
+
+// AppClient is a mock of bigcommerce.AppClient. Requests are accepted when their
+// signed_payload or code query parameter has an entry in ClientRequests or AuthContexts.
+type AppClient struct {
+	Recorder
+	// ClientRequests by signed_payload
+	ClientRequests map[string]*bigcommerce.ClientRequest
+	// AuthContexts by code
+	AuthContexts map[string]*bigcommerce.AuthContext
+}
+
+func (am *AppClient) GetClientRequest(requestURLQuery url.Values) (*bigcommerce.ClientRequest, error) {
+	if err := am.record("GetClientRequest", requestURLQuery); err != nil {
+		return nil, err
+	}
+	if cr, ok := am.ClientRequests[requestURLQuery.Get("signed_payload")]; ok {
+		return cr, nil
+	}
+	return nil, bigcommerce.ErrNotFound
+}
+
+func (am *AppClient) GetAuthContext(requestURLQuery url.Values) (*bigcommerce.AuthContext, error) {
+	if err := am.record("GetAuthContext", requestURLQuery); err != nil {
+		return nil, err
+	}
+	if ac, ok := am.AuthContexts[requestURLQuery.Get("code")]; ok {
+		return ac, nil
+	}
+	return nil, bigcommerce.ErrNotFound
+}
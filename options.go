@@ -0,0 +1,334 @@
+package bigcommerce
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// VariantOption is a BigCommerce product variant option, like size or color.
+// Options define the variants of a product: every variant has one value of each option.
+// (ProductOption is the option of an order product, see orders.go)
+type VariantOption struct {
+	ID           int64                  `json:"id,omitempty"`
+	ProductID    int64                  `json:"product_id,omitempty"`
+	Name         string                 `json:"name,omitempty"`
+	DisplayName  string                 `json:"display_name,omitempty"`
+	Type         string                 `json:"type,omitempty"`
+	SortOrder    int                    `json:"sort_order,omitempty"`
+	Config       map[string]interface{} `json:"config,omitempty"`
+	OptionValues []OptionValue          `json:"option_values,omitempty"`
+}
+
+// OptionValue is a value of a variant option or of a modifier.
+// In the option_values of a variant only ID, Label, OptionID and OptionDisplayName are set.
+type OptionValue struct {
+	ID                int64                  `json:"id,omitempty"`
+	Label             string                 `json:"label,omitempty"`
+	SortOrder         int                    `json:"sort_order,omitempty"`
+	IsDefault         bool                   `json:"is_default,omitempty"`
+	ValueData         map[string]interface{} `json:"value_data,omitempty"`
+	Adjusters         map[string]interface{} `json:"adjusters,omitempty"`
+	OptionID          int64                  `json:"option_id,omitempty"`
+	OptionDisplayName string                 `json:"option_display_name,omitempty"`
+}
+
+// Modifier is a BigCommerce product modifier, an option that doesn't create variants
+// like a text field or a gift wrap checkbox
+type Modifier struct {
+	ID           int64                  `json:"id,omitempty"`
+	ProductID    int64                  `json:"product_id,omitempty"`
+	Name         string                 `json:"name,omitempty"`
+	DisplayName  string                 `json:"display_name,omitempty"`
+	Type         string                 `json:"type,omitempty"`
+	Required     bool                   `json:"required"`
+	SortOrder    int                    `json:"sort_order,omitempty"`
+	Config       map[string]interface{} `json:"config,omitempty"`
+	OptionValues []OptionValue          `json:"option_values,omitempty"`
+}
+
+// GetVariantOptions returns all the variant options of a product with their values
+func (bc *Client) GetVariantOptions(productID int64) ([]VariantOption, error) {
+	ret := []VariantOption{}
+	page := 1
+	more := true
+	for more {
+		var ops []VariantOption
+		var err error
+		more, err = bc.getPage(fmt.Sprintf("/v3/catalog/products/%d/options?limit=250", productID), page, &ops)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, ops...)
+		page++
+	}
+	return ret, nil
+}
+
+// GetVariantOption gets a variant option of a product by ID
+func (bc *Client) GetVariantOption(productID, optionID int64) (*VariantOption, error) {
+	var ret VariantOption
+	err := bc.sendJSON(http.MethodGet, fmt.Sprintf("/v3/catalog/products/%d/options/%d", productID, optionID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// CreateVariantOption creates a variant option with its values on a product
+func (bc *Client) CreateVariantOption(productID int64, option *VariantOption) (*VariantOption, error) {
+	payload := *option
+	payload.ID = 0
+	payload.ProductID = productID
+	var ret VariantOption
+	err := bc.sendJSON(http.MethodPost, fmt.Sprintf("/v3/catalog/products/%d/options", productID), payload, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// UpdateVariantOption updates a variant option of a product by ID
+func (bc *Client) UpdateVariantOption(productID int64, option *VariantOption) (*VariantOption, error) {
+	if option.ID == 0 {
+		return nil, errors.New("option has no ID")
+	}
+	var ret VariantOption
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/catalog/products/%d/options/%d", productID, option.ID), option, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// DeleteVariantOption deletes a variant option of a product, BigCommerce deletes the variants using it
+func (bc *Client) DeleteVariantOption(productID, optionID int64) error {
+	err := bc.sendJSON(http.MethodDelete, fmt.Sprintf("/v3/catalog/products/%d/options/%d", productID, optionID), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// GetOptionValues returns all the values of a variant option
+func (bc *Client) GetOptionValues(productID, optionID int64) ([]OptionValue, error) {
+	return bc.getAllOptionValues(fmt.Sprintf("/v3/catalog/products/%d/options/%d/values?limit=250", productID, optionID))
+}
+
+// CreateOptionValue adds a value to a variant option
+func (bc *Client) CreateOptionValue(productID, optionID int64, value *OptionValue) (*OptionValue, error) {
+	return bc.createOptionValue(fmt.Sprintf("/v3/catalog/products/%d/options/%d/values", productID, optionID), value)
+}
+
+// UpdateOptionValue updates a value of a variant option by ID
+func (bc *Client) UpdateOptionValue(productID, optionID int64, value *OptionValue) (*OptionValue, error) {
+	return bc.updateOptionValue(fmt.Sprintf("/v3/catalog/products/%d/options/%d/values", productID, optionID), value)
+}
+
+// DeleteOptionValue deletes a value of a variant option
+func (bc *Client) DeleteOptionValue(productID, optionID, valueID int64) error {
+	return bc.deleteOptionValue(fmt.Sprintf("/v3/catalog/products/%d/options/%d/values/%d", productID, optionID, valueID))
+}
+
+// GetModifiers returns all the modifiers of a product with their values
+func (bc *Client) GetModifiers(productID int64) ([]Modifier, error) {
+	ret := []Modifier{}
+	page := 1
+	more := true
+	for more {
+		var ms []Modifier
+		var err error
+		more, err = bc.getPage(fmt.Sprintf("/v3/catalog/products/%d/modifiers?limit=250", productID), page, &ms)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, ms...)
+		page++
+	}
+	return ret, nil
+}
+
+// GetModifier gets a modifier of a product by ID
+func (bc *Client) GetModifier(productID, modifierID int64) (*Modifier, error) {
+	var ret Modifier
+	err := bc.sendJSON(http.MethodGet, fmt.Sprintf("/v3/catalog/products/%d/modifiers/%d", productID, modifierID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// CreateModifier creates a modifier with its values on a product
+func (bc *Client) CreateModifier(productID int64, modifier *Modifier) (*Modifier, error) {
+	payload := *modifier
+	payload.ID = 0
+	payload.ProductID = productID
+	var ret Modifier
+	err := bc.sendJSON(http.MethodPost, fmt.Sprintf("/v3/catalog/products/%d/modifiers", productID), payload, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// UpdateModifier updates a modifier of a product by ID
+func (bc *Client) UpdateModifier(productID int64, modifier *Modifier) (*Modifier, error) {
+	if modifier.ID == 0 {
+		return nil, errors.New("modifier has no ID")
+	}
+	var ret Modifier
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/catalog/products/%d/modifiers/%d", productID, modifier.ID), modifier, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// DeleteModifier deletes a modifier of a product
+func (bc *Client) DeleteModifier(productID, modifierID int64) error {
+	err := bc.sendJSON(http.MethodDelete, fmt.Sprintf("/v3/catalog/products/%d/modifiers/%d", productID, modifierID), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// GetModifierValues returns all the values of a modifier
+func (bc *Client) GetModifierValues(productID, modifierID int64) ([]OptionValue, error) {
+	return bc.getAllOptionValues(fmt.Sprintf("/v3/catalog/products/%d/modifiers/%d/values?limit=250", productID, modifierID))
+}
+
+// CreateModifierValue adds a value to a modifier
+func (bc *Client) CreateModifierValue(productID, modifierID int64, value *OptionValue) (*OptionValue, error) {
+	return bc.createOptionValue(fmt.Sprintf("/v3/catalog/products/%d/modifiers/%d/values", productID, modifierID), value)
+}
+
+// UpdateModifierValue updates a value of a modifier by ID
+func (bc *Client) UpdateModifierValue(productID, modifierID int64, value *OptionValue) (*OptionValue, error) {
+	return bc.updateOptionValue(fmt.Sprintf("/v3/catalog/products/%d/modifiers/%d/values", productID, modifierID), value)
+}
+
+// DeleteModifierValue deletes a value of a modifier
+func (bc *Client) DeleteModifierValue(productID, modifierID, valueID int64) error {
+	return bc.deleteOptionValue(fmt.Sprintf("/v3/catalog/products/%d/modifiers/%d/values/%d", productID, modifierID, valueID))
+}
+
+func (bc *Client) getAllOptionValues(path string) ([]OptionValue, error) {
+	ret := []OptionValue{}
+	page := 1
+	more := true
+	for more {
+		var vs []OptionValue
+		var err error
+		more, err = bc.getPage(path, page, &vs)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, vs...)
+		page++
+	}
+	return ret, nil
+}
+
+func (bc *Client) createOptionValue(path string, value *OptionValue) (*OptionValue, error) {
+	payload := *value
+	payload.ID = 0
+	var ret OptionValue
+	err := bc.sendJSON(http.MethodPost, path, payload, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (bc *Client) updateOptionValue(path string, value *OptionValue) (*OptionValue, error) {
+	if value.ID == 0 {
+		return nil, errors.New("option value has no ID")
+	}
+	var ret OptionValue
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("%s/%d", path, value.ID), value, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (bc *Client) deleteOptionValue(path string) error {
+	err := bc.sendJSON(http.MethodDelete, path, nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// GetVariantByOptions returns the variant of a product with the chosen option values
+// choices: option value ID by option ID
+func (bc *Client) GetVariantByOptions(productID int64, choices map[int64]int64) (*Variant, error) {
+	vs, err := bc.GetAllProductVariants(productID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ResolveVariant(vs, choices)
+}
+
+// ResolveVariant returns the variant with the chosen option values, ErrNotFound if no variant has them
+// choices: option value ID by option ID, it must have a value for every option of the variants
+func ResolveVariant(variants []Variant, choices map[int64]int64) (*Variant, error) {
+	for _, v := range variants {
+		for _, ov := range v.OptionValues {
+			if _, ok := choices[ov.OptionID]; !ok {
+				return nil, fmt.Errorf("no value chosen for option %s (%d)", ov.OptionDisplayName, ov.OptionID)
+			}
+		}
+	}
+	for i := range variants {
+		v := &variants[i]
+		if len(v.OptionValues) != len(choices) {
+			continue
+		}
+		match := true
+		for _, ov := range v.OptionValues {
+			if choices[ov.OptionID] != ov.ID {
+				match = false
+				break
+			}
+		}
+		if match {
+			return v, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// OptionChoices converts choices by option display name and value label, like they are posted by
+// a product page form, to option value IDs by option ID for ResolveVariant. Names are case insensitive.
+func OptionChoices(options []VariantOption, labels map[string]string) (map[int64]int64, error) {
+	ret := map[int64]int64{}
+	unknown := []string{}
+	for name, label := range labels {
+		found := false
+		for _, o := range options {
+			if !strings.EqualFold(o.DisplayName, name) {
+				continue
+			}
+			for _, ov := range o.OptionValues {
+				if strings.EqualFold(ov.Label, label) {
+					ret[o.ID] = ov.ID
+					found = true
+					break
+				}
+			}
+			break
+		}
+		if !found {
+			unknown = append(unknown, name+"="+label)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("unknown option values: %s", strings.Join(unknown, ", "))
+	}
+	return ret, nil
+}
@@ -0,0 +1,49 @@
+package bigcommerce_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+)
+
+func TestResolveVariant(t *testing.T) {
+	// options 1 Color (values 10 Red, 11 Blue) and 2 Size (values 20 S, 21 M)
+	value := func(optionID, valueID int64, option string) bigcommerce.OptionValue {
+		return bigcommerce.OptionValue{ID: valueID, OptionID: optionID, OptionDisplayName: option}
+	}
+	variants := []bigcommerce.Variant{
+		{ID: 100, OptionValues: []bigcommerce.OptionValue{value(1, 10, "Color"), value(2, 20, "Size")}},
+		{ID: 101, OptionValues: []bigcommerce.OptionValue{value(1, 10, "Color"), value(2, 21, "Size")}},
+		{ID: 102, OptionValues: []bigcommerce.OptionValue{value(1, 11, "Color"), value(2, 20, "Size")}},
+	}
+	tests := []struct {
+		name    string
+		choices map[int64]int64
+		want    int64
+		wantErr string
+	}{
+		{name: "all chosen", choices: map[int64]int64{1: 10, 2: 21}, want: 101},
+		{name: "no such variant", choices: map[int64]int64{1: 11, 2: 21}, wantErr: bigcommerce.ErrNotFound.Error()},
+		{name: "option not chosen", choices: map[int64]int64{1: 10}, wantErr: "no value chosen for option Size (2)"},
+		{name: "nothing chosen", choices: map[int64]int64{}, wantErr: "no value chosen for option Color (1)"},
+		{name: "unknown option chosen", choices: map[int64]int64{1: 10, 2: 20, 3: 30}, wantErr: bigcommerce.ErrNotFound.Error()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := bigcommerce.ResolveVariant(variants, tt.choices)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if v.ID != tt.want {
+				t.Errorf("resolved variant %d, want %d", v.ID, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package bigcommerce
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Patch is a partial update: only the fields in the patch are sent to the API, zero values included.
+// Keys are the API field names, e.g. "is_featured", "inventory_warning_level" or "sale_price"
+type Patch map[string]interface{}
+
+// Set sets a field of the patch and returns the patch, so calls can be chained:
+//
+//	bigcommerce.Patch{}.Set("is_featured", false).Set("sale_price", 0)
+func (p Patch) Set(field string, value interface{}) Patch {
+	p[field] = value
+	return p
+}
+
+// Fields returns the sorted field names of the patch
+func (p Patch) Fields() []string {
+	fields := []string{}
+	for f := range p {
+		fields = append(fields, f)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// PatchFrom builds a patch with the given fields of v, a struct or pointer to struct like Product or Variant.
+// Fields are the JSON names of the struct fields; unlike marshalling v, zero values are kept.
+func PatchFrom(v interface{}, fields ...string) (Patch, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil, errors.New("PatchFrom: nil value")
+		}
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("PatchFrom: %s is not a struct", rv.Type())
+	}
+	values := map[string]reflect.Value{}
+	jsonFields(rv, values)
+	p := Patch{}
+	for _, f := range fields {
+		fv, ok := values[f]
+		if !ok {
+			return nil, fmt.Errorf("PatchFrom: %s has no field %s", rv.Type(), f)
+		}
+		p[f] = fv.Interface()
+	}
+	return p, nil
+}
+
+// setFields builds a patch with the fields of v that are not zero values once marshalled, nested
+// objects included, so a struct holding only the fields to change leaves the others as they are
+func setFields(v interface{}) (Patch, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	err = dec.Decode(&fields)
+	if err != nil {
+		return nil, err
+	}
+	p := Patch{}
+	for k, f := range fields {
+		if f, ok := nonZero(f); ok {
+			p[k] = f
+		}
+	}
+	return p, nil
+}
+
+// zeroTime is a zero time.Time once marshalled
+var zeroTime = time.Time{}.Format(time.RFC3339Nano)
+
+// nonZero returns a decoded JSON value without its zero fields, and whether anything is left
+func nonZero(v interface{}) (interface{}, bool) {
+	switch t := v.(type) {
+	case nil:
+		return nil, false
+	case bool:
+		return t, t
+	case string:
+		return t, t != "" && t != zeroTime
+	case json.Number:
+		f, _ := t.Float64()
+		return t, f != 0
+	case []interface{}:
+		return t, len(t) > 0
+	case map[string]interface{}:
+		m := map[string]interface{}{}
+		for k, f := range t {
+			if f, ok := nonZero(f); ok {
+				m[k] = f
+			}
+		}
+		return m, len(m) > 0
+	}
+	return v, true
+}
+
+// patchID returns the "id" field of a patch, 0 if it has none
+func patchID(p Patch) int64 {
+	switch id := p["id"].(type) {
+	case int64:
+		return id
+	case int:
+		return int64(id)
+	case float64:
+		return int64(id)
+	case json.Number:
+		n, _ := id.Int64()
+		return n
+	}
+	return 0
+}
+
+// jsonFields collects the exported fields of a struct by JSON name, including embedded structs
+func jsonFields(rv reflect.Value, values map[string]reflect.Value) {
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+		tag := sf.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		if sf.Anonymous && name == "" && sf.Type.Kind() == reflect.Struct {
+			jsonFields(rv.Field(i), values)
+			continue
+		}
+		if name == "" {
+			name = sf.Name
+		}
+		values[name] = rv.Field(i)
+	}
+}
+
+// PatchProduct updates only the fields in the patch of a product
+func (bc *Client) PatchProduct(productID int64, patch Patch) (*Product, error) {
+	var ret Product
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/catalog/products/%d", productID), patch, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// PatchVariant updates only the fields in the patch of a product variant
+func (bc *Client) PatchVariant(productID, variantID int64, patch Patch) (*Variant, error) {
+	var ret Variant
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/catalog/products/%d/variants/%d", productID, variantID), patch, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// PatchCategory updates only the fields in the patch of a category
+func (bc *Client) PatchCategory(categoryID int64, patch Patch) (*Category, error) {
+	var ret Category
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/catalog/categories/%d", categoryID), patch, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// PatchBrand updates only the fields in the patch of a brand
+func (bc *Client) PatchBrand(brandID int64, patch Patch) (*Brand, error) {
+	var ret Brand
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/catalog/brands/%d", brandID), patch, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// PatchCustomer updates only the fields in the patch of a customer
+func (bc *Client) PatchCustomer(customerID int64, patch Patch) (*Customer, error) {
+	payload := Patch{}
+	for k, v := range patch {
+		payload[k] = v
+	}
+	payload["id"] = customerID
+	var ret []Customer
+	err := bc.sendJSON(http.MethodPut, "/v3/customers", []Patch{payload}, &ret)
+	if err != nil {
+		return nil, err
+	}
+	if len(ret) == 0 {
+		return nil, ErrNotFound
+	}
+	return &ret[0], nil
+}
@@ -0,0 +1,465 @@
+package bigcommerce
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Resources the ChangePoller can watch, also used as cursor names
+const (
+	PollProducts  = "products"
+	PollVariants  = "variants"
+	PollCustomers = "customers"
+	PollOrders    = "orders"
+	PollCoupons   = "coupons"
+)
+
+// pollPageSize is the page size used when querying for changes
+const pollPageSize = 250
+
+// pollMaxBackoff is the longest wait between polls after failures
+const pollMaxBackoff = 15 * time.Minute
+
+// CursorStore persists the last seen modification time per polled resource
+type CursorStore interface {
+	GetCursor(resource string) (time.Time, bool, error)
+	SetCursor(resource string, cursor time.Time) error
+}
+
+// MemoryCursorStore keeps cursors in memory only, changes are re-emitted after a restart
+type MemoryCursorStore struct {
+	mu      sync.Mutex
+	cursors map[string]time.Time
+}
+
+// GetCursor returns the cursor for a resource and whether it was set
+func (m *MemoryCursorStore) GetCursor(resource string) (time.Time, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	t, ok := m.cursors[resource]
+	return t, ok, nil
+}
+
+// SetCursor stores the cursor for a resource
+func (m *MemoryCursorStore) SetCursor(resource string, cursor time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.cursors == nil {
+		m.cursors = map[string]time.Time{}
+	}
+	m.cursors[resource] = cursor
+	return nil
+}
+
+// FileCursorStore keeps cursors in a JSON file, written on every change
+type FileCursorStore struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// NewFileCursorStore returns a cursor store backed by the JSON file at path
+func NewFileCursorStore(path string) *FileCursorStore {
+	return &FileCursorStore{Path: path}
+}
+
+func (f *FileCursorStore) load() (map[string]time.Time, error) {
+	cursors := map[string]time.Time{}
+	b, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return cursors, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(b, &cursors)
+	if err != nil {
+		return nil, fmt.Errorf("can't parse cursor file %s: %v", f.Path, err)
+	}
+	return cursors, nil
+}
+
+// GetCursor returns the cursor for a resource and whether it was set
+func (f *FileCursorStore) GetCursor(resource string) (time.Time, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	cursors, err := f.load()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	t, ok := cursors[resource]
+	return t, ok, nil
+}
+
+// SetCursor stores the cursor for a resource
+func (f *FileCursorStore) SetCursor(resource string, cursor time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	cursors, err := f.load()
+	if err != nil {
+		return err
+	}
+	cursors[resource] = cursor
+	b, err := json.MarshalIndent(cursors, "", "  ")
+	if err != nil {
+		return err
+	}
+	// write to a temp file first so a crash can't leave a truncated cursor file
+	tmp := f.Path + ".tmp"
+	err = ioutil.WriteFile(tmp, b, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.Path)
+}
+
+// ChangePoller periodically queries BigCommerce for modified resources and emits
+// a WebhookPayload for every change, so the same handler can serve webhooks (push)
+// and polling (pull). Delivery is at-least-once: a change can be emitted again
+// after a restart or when the handler fails.
+//
+// Emitted scopes match the webhook scopes (store/product/updated, store/sku/updated,
+// store/customer/created, store/order/updated...). BigCommerce has no coupon webhooks,
+// coupons are emitted as store/coupon/created and store/coupon/updated.
+//
+// Coupons have no modification date, so their edits are detected by comparing the content of
+// every coupon with the previous poll, ignoring num_uses. The first poll only records the
+// coupons, emitting those created since Since if it is set. The content is kept in memory:
+// coupons edited while the poller is stopped are not emitted.
+type ChangePoller struct {
+	Client    *Client
+	Cursors   CursorStore
+	Handler   func(*WebhookPayload) error
+	Interval  time.Duration
+	Resources []string
+	// Since is the starting point for resources without a stored cursor,
+	// zero value means only changes from the first poll on are emitted
+	Since time.Time
+	// OnError is called when a poll fails in Run, before waiting for the next one.
+	// Errors are logged when it is nil.
+	OnError func(error)
+
+	// IDs already emitted at the current cursor time, date filters are inclusive
+	seen map[string]map[int64]bool
+	// content hash of the coupons by ID, nil before the first poll
+	coupons map[int64]string
+}
+
+// NewChangePoller returns a poller for all supported resources with a one minute interval
+func NewChangePoller(client *Client, cursors CursorStore, handler func(*WebhookPayload) error) *ChangePoller {
+	return &ChangePoller{
+		Client:    client,
+		Cursors:   cursors,
+		Handler:   handler,
+		Interval:  time.Minute,
+		Resources: []string{PollProducts, PollVariants, PollCustomers, PollOrders, PollCoupons},
+	}
+}
+
+// polledChange is a single change found while polling
+type polledChange struct {
+	id       int64
+	modified time.Time
+	payload  *WebhookPayload
+}
+
+// Run polls until the context is cancelled. Failed polls are reported to OnError and retried,
+// the wait doubles after every consecutive failure up to pollMaxBackoff.
+func (p *ChangePoller) Run(ctx context.Context) error {
+	interval := p.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	wait := interval
+	for {
+		err := p.Poll()
+		if err == nil {
+			wait = interval
+		} else {
+			if p.OnError != nil {
+				p.OnError(err)
+			} else {
+				log.Printf("change poller: %v", err)
+			}
+			wait = pollBackoff(wait, interval)
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// pollBackoff returns the wait after another failed poll, starting from interval
+func pollBackoff(wait, interval time.Duration) time.Duration {
+	wait *= 2
+	if wait > pollMaxBackoff {
+		wait = pollMaxBackoff
+	}
+	if wait < interval {
+		wait = interval
+	}
+	return wait
+}
+
+// Poll checks every resource once and emits the changes found since the last poll
+func (p *ChangePoller) Poll() error {
+	if p.Cursors == nil {
+		p.Cursors = &MemoryCursorStore{}
+	}
+	if p.seen == nil {
+		p.seen = map[string]map[int64]bool{}
+	}
+	for _, resource := range p.Resources {
+		err := p.pollResource(resource)
+		if err != nil {
+			return fmt.Errorf("polling %s: %v", resource, err)
+		}
+	}
+	return nil
+}
+
+func (p *ChangePoller) pollResource(resource string) error {
+	if resource == PollCoupons {
+		return p.pollCoupons()
+	}
+	cursor, ok, err := p.Cursors.GetCursor(resource)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		cursor = p.Since
+		if cursor.IsZero() {
+			cursor = time.Now().UTC()
+		}
+		err = p.Cursors.SetCursor(resource, cursor)
+		if err != nil {
+			return err
+		}
+	}
+
+	var changes []polledChange
+	switch resource {
+	case PollProducts:
+		changes, err = p.productChanges(cursor)
+	case PollVariants:
+		changes, err = p.variantChanges(cursor)
+	case PollCustomers:
+		changes, err = p.customerChanges(cursor)
+	case PollOrders:
+		changes, err = p.orderChanges(cursor)
+	default:
+		return fmt.Errorf("unknown resource")
+	}
+	if err != nil {
+		return err
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].modified.Before(changes[j].modified)
+	})
+
+	seen := p.seen[resource]
+	for _, c := range changes {
+		if c.modified.Before(cursor) || (c.modified.Equal(cursor) && seen[c.id]) {
+			continue
+		}
+		err = p.Handler(c.payload)
+		if err != nil {
+			return err
+		}
+		if c.modified.After(cursor) {
+			cursor = c.modified
+			seen = map[int64]bool{}
+			err = p.Cursors.SetCursor(resource, cursor)
+			if err != nil {
+				return err
+			}
+		}
+		if seen == nil {
+			seen = map[int64]bool{}
+		}
+		seen[c.id] = true
+		p.seen[resource] = seen
+	}
+	return nil
+}
+
+// changeScope returns the created or updated scope depending on when the resource was created
+func changeScope(prefix string, created, cursor time.Time) string {
+	if !created.IsZero() && !created.Before(cursor) {
+		return prefix + "/created"
+	}
+	return prefix + "/updated"
+}
+
+func (p *ChangePoller) newPayload(scope, kind string, id int64) *WebhookPayload {
+	var wp WebhookPayload
+	wp.Scope = scope
+	wp.StoreID = p.Client.StoreHash
+	wp.Producer = "stores/" + p.Client.StoreHash
+	wp.CreatedAt = time.Now().Unix()
+	wp.Data.Type = kind
+	wp.Data.ID = id
+	return &wp
+}
+
+func (p *ChangePoller) productChanges(cursor time.Time) ([]polledChange, error) {
+	products, err := p.Client.GetAllProducts(map[string]string{
+		"date_modified:min": cursor.UTC().Format(time.RFC3339),
+		"include_fields":    "id,date_created,date_modified",
+		"limit":             strconv.Itoa(pollPageSize),
+	})
+	if err != nil {
+		return nil, err
+	}
+	changes := []polledChange{}
+	for _, pr := range products {
+		changes = append(changes, polledChange{
+			id:       pr.ID,
+			modified: pr.DateModified,
+			payload:  p.newPayload(changeScope("store/product", pr.DateCreated, cursor), "product", pr.ID),
+		})
+	}
+	return changes, nil
+}
+
+// variantChanges emits every variant of the products modified since cursor,
+// the variants endpoint itself can't be filtered by modification date
+func (p *ChangePoller) variantChanges(cursor time.Time) ([]polledChange, error) {
+	products, err := p.Client.GetAllProducts(map[string]string{
+		"date_modified:min": cursor.UTC().Format(time.RFC3339),
+		"include_fields":    "id,date_modified",
+		"include":           "variants",
+		"limit":             strconv.Itoa(pollPageSize),
+	})
+	if err != nil {
+		return nil, err
+	}
+	changes := []polledChange{}
+	for _, pr := range products {
+		for _, v := range pr.Variants {
+			wp := p.newPayload("store/sku/updated", "sku", v.ID)
+			wp.Data.Sku.ProductID = pr.ID
+			wp.Data.Sku.VariantID = v.ID
+			changes = append(changes, polledChange{id: v.ID, modified: pr.DateModified, payload: wp})
+		}
+	}
+	return changes, nil
+}
+
+func (p *ChangePoller) customerChanges(cursor time.Time) ([]polledChange, error) {
+	customers, err := p.Client.GetAllCustomers(map[string]string{
+		"date_modified:min": cursor.UTC().Format(time.RFC3339),
+		"limit":             strconv.Itoa(pollPageSize),
+	})
+	if err != nil {
+		return nil, err
+	}
+	changes := []polledChange{}
+	for _, c := range customers {
+		modified := parseAPITime(c.DateModified)
+		created := parseAPITime(c.DateCreated)
+		changes = append(changes, polledChange{
+			id:       c.ID,
+			modified: modified,
+			payload:  p.newPayload(changeScope("store/customer", created, cursor), "customer", c.ID),
+		})
+	}
+	return changes, nil
+}
+
+func (p *ChangePoller) orderChanges(cursor time.Time) ([]polledChange, error) {
+	changes := []polledChange{}
+	for page := 1; ; page++ {
+		orders, err := p.Client.GetOrders(map[string]string{
+			"min_date_modified": cursor.UTC().Format(time.RFC3339),
+			"limit":             strconv.Itoa(pollPageSize),
+			"page":              strconv.Itoa(page),
+		})
+		if err != nil && err != ErrNoContent {
+			return nil, err
+		}
+		for _, o := range orders {
+			modified := parseAPITime(o.DateModified)
+			created := parseAPITime(o.DateCreated)
+			changes = append(changes, polledChange{
+				id:       o.ID,
+				modified: modified,
+				payload:  p.newPayload(changeScope("store/order", created, cursor), "order", o.ID),
+			})
+		}
+		if len(orders) < pollPageSize {
+			break
+		}
+	}
+	return changes, nil
+}
+
+// couponHash returns a hash of the content of a coupon, num_uses changes on every order and is ignored
+func couponHash(c Coupon) string {
+	c.NumUses = 0
+	b, _ := json.Marshal(c)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// pollCoupons emits the coupons that are new or whose content changed since the previous poll.
+// When the handler fails the poll is discarded, its changes are emitted again on the next poll.
+func (p *ChangePoller) pollCoupons() error {
+	coupons, err := p.Client.GetAllCoupons(map[string]string{"limit": strconv.Itoa(pollPageSize)})
+	if err != nil {
+		return err
+	}
+	sort.Slice(coupons, func(i, j int) bool { return coupons[i].ID < coupons[j].ID })
+	first := p.coupons == nil
+	previous := p.coupons
+	p.coupons = map[int64]string{}
+	for _, c := range coupons {
+		hash := couponHash(c)
+		old, known := previous[c.ID]
+		scope := ""
+		switch {
+		case first:
+			if created := parseAPITime(c.DateCreated); !p.Since.IsZero() && !created.Before(p.Since) {
+				scope = "store/coupon/created"
+			}
+		case !known:
+			scope = "store/coupon/created"
+		case old != hash:
+			scope = "store/coupon/updated"
+		}
+		if scope != "" {
+			wp := p.newPayload(scope, "coupon", c.ID)
+			wp.Data.CouponID = strconv.FormatInt(c.ID, 10)
+			if err := p.Handler(wp); err != nil {
+				p.coupons = previous
+				return err
+			}
+		}
+		p.coupons[c.ID] = hash
+	}
+	return nil
+}
+
+// parseAPITime parses the date formats used by the v2 (RFC 1123) and v3 (RFC 3339) APIs
+func parseAPITime(s string) time.Time {
+	for _, layout := range []string{time.RFC3339, time.RFC1123Z, time.RFC1123} {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
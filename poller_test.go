@@ -0,0 +1,161 @@
+package bigcommerce_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+	"github.com/mvalenziano/bigcommerce-api-go/bctest"
+)
+
+func TestChangePollerCursor(t *testing.T) {
+	since := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(d time.Duration) time.Time { return since.Add(d) }
+	tests := []struct {
+		name     string
+		products []bigcommerce.Product
+		failOnce int64 // the handler fails the first time this product is emitted
+		// want lists the payloads handled by each of two polls as "scope id"
+		want [][]string
+		// cursor is the stored cursor after the polls
+		cursor time.Time
+	}{
+		{
+			name:     "modified before the cursor",
+			products: []bigcommerce.Product{{ID: 1, DateCreated: at(-2 * time.Hour), DateModified: at(-time.Hour)}},
+			want:     [][]string{{}, {}},
+			cursor:   since,
+		},
+		{
+			name:     "modified at the cursor",
+			products: []bigcommerce.Product{{ID: 1, DateCreated: at(-time.Hour), DateModified: since}},
+			want:     [][]string{{"store/product/updated 1"}, {}},
+			cursor:   since,
+		},
+		{
+			name:     "created after the cursor",
+			products: []bigcommerce.Product{{ID: 1, DateCreated: at(time.Minute), DateModified: at(time.Minute)}},
+			want:     [][]string{{"store/product/created 1"}, {}},
+			cursor:   at(time.Minute),
+		},
+		{
+			name: "in order of modification",
+			products: []bigcommerce.Product{
+				{ID: 1, DateCreated: at(-time.Hour), DateModified: at(2 * time.Minute)},
+				{ID: 2, DateCreated: at(-time.Hour), DateModified: at(time.Minute)},
+			},
+			want:   [][]string{{"store/product/updated 2", "store/product/updated 1"}, {}},
+			cursor: at(2 * time.Minute),
+		},
+		{
+			name: "same modification time",
+			products: []bigcommerce.Product{
+				{ID: 1, DateCreated: at(-time.Hour), DateModified: at(time.Minute)},
+				{ID: 2, DateCreated: at(-time.Hour), DateModified: at(time.Minute)},
+			},
+			want:   [][]string{{"store/product/updated 1", "store/product/updated 2"}, {}},
+			cursor: at(time.Minute),
+		},
+		{
+			name: "failed change emitted again",
+			products: []bigcommerce.Product{
+				{ID: 1, DateCreated: at(-time.Hour), DateModified: at(time.Minute)},
+				{ID: 2, DateCreated: at(-time.Hour), DateModified: at(time.Minute)},
+				{ID: 3, DateCreated: at(-time.Hour), DateModified: at(2 * time.Minute)},
+			},
+			failOnce: 2,
+			want:     [][]string{{"store/product/updated 1"}, {"store/product/updated 2", "store/product/updated 3"}},
+			cursor:   at(2 * time.Minute),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := bctest.NewServer()
+			for _, p := range tt.products {
+				p.Name = fmt.Sprintf("Product %d", p.ID)
+				p.Sku = fmt.Sprintf("P%d", p.ID)
+				s.AddProduct(p)
+			}
+			var handled []string
+			failed := false
+			cursors := &bigcommerce.MemoryCursorStore{}
+			poller := bigcommerce.NewChangePoller(s.Client(), cursors, func(wp *bigcommerce.WebhookPayload) error {
+				if wp.Data.ID == tt.failOnce && !failed {
+					failed = true
+					return errors.New("handler failed")
+				}
+				handled = append(handled, fmt.Sprintf("%s %d", wp.Scope, wp.Data.ID))
+				return nil
+			})
+			poller.Resources = []string{bigcommerce.PollProducts}
+			poller.Since = since
+
+			got := [][]string{}
+			for i := 0; i < 2; i++ {
+				handled = []string{}
+				err := poller.Poll()
+				if (err != nil) != (i == 0 && tt.failOnce != 0) {
+					t.Errorf("poll %d: error %v", i+1, err)
+				}
+				got = append(got, handled)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("handled %v, want %v", got, tt.want)
+			}
+			cursor, _, _ := cursors.GetCursor(bigcommerce.PollProducts)
+			if !cursor.Equal(tt.cursor) {
+				t.Errorf("cursor %s, want %s", cursor, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestChangePollerCoupons(t *testing.T) {
+	s := bctest.NewServer()
+	c := s.AddCoupon(bigcommerce.Coupon{Name: "Spring", Code: "SPRING", Type: "per_item_discount", Amount: "5"})
+	bc := s.Client()
+	var handled []string
+	poller := bigcommerce.NewChangePoller(bc, nil, func(wp *bigcommerce.WebhookPayload) error {
+		handled = append(handled, wp.Scope+" "+wp.Data.CouponID)
+		return nil
+	})
+	poller.Resources = []string{bigcommerce.PollCoupons}
+
+	steps := []struct {
+		name   string
+		change func() error
+		want   []string
+	}{
+		{"first poll records the coupons", func() error { return nil }, []string{}},
+		{"no change", func() error { return nil }, []string{}},
+		{"uses are not an edit", func() error {
+			c.NumUses = 3
+			_, err := bc.UpdateCoupon(c.ID, c)
+			return err
+		}, []string{}},
+		{"edit", func() error {
+			c.Amount = "10"
+			_, err := bc.UpdateCoupon(c.ID, c)
+			return err
+		}, []string{fmt.Sprintf("store/coupon/updated %d", c.ID)}},
+		{"new coupon", func() error {
+			_, err := bc.CreateCoupon(bigcommerce.Coupon{Name: "Summer", Code: "SUMMER", Type: "per_item_discount", Amount: "5"})
+			return err
+		}, []string{fmt.Sprintf("store/coupon/created %d", c.ID+1)}},
+	}
+	for _, step := range steps {
+		if err := step.change(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		handled = []string{}
+		if err := poller.Poll(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		if !reflect.DeepEqual(handled, step.want) {
+			t.Errorf("%s: handled %v, want %v", step.name, handled, step.want)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package bigcommerce
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+)
+
+// priceRecordBatchSize is the maximum number of records BigCommerce accepts in a batch upsert
+const priceRecordBatchSize = 1000
+
+// PriceList overrides the prices of variants for the customer groups and channels it is assigned to.
+// The dates are read-only, only the name and active flag are sent.
+type PriceList struct {
+	ID           int64     `json:"id,omitempty"`
+	Name         string    `json:"name"`
+	Active       bool      `json:"active"`
+	DateCreated  time.Time `json:"date_created,omitempty"`
+	DateModified time.Time `json:"date_modified,omitempty"`
+}
+
+// PriceRecord is the price of a variant in a currency of a price list. Records are upserted by
+// VariantID, or by Sku when VariantID is 0. Currency is a lowercase ISO code like "usd".
+// ProductID, CalculatedPrice and the dates are read-only and not sent.
+type PriceRecord struct {
+	PriceListID      int64             `json:"price_list_id,omitempty"`
+	VariantID        int64             `json:"variant_id,omitempty"`
+	ProductID        int64             `json:"product_id,omitempty"`
+	Sku              string            `json:"sku,omitempty"`
+	Currency         string            `json:"currency"`
+	Price            float64           `json:"price"`
+	SalePrice        float64           `json:"sale_price,omitempty"`
+	RetailPrice      float64           `json:"retail_price,omitempty"`
+	MapPrice         float64           `json:"map_price,omitempty"`
+	CalculatedPrice  float64           `json:"calculated_price,omitempty"`
+	BulkPricingTiers []BulkPricingRule `json:"bulk_pricing_tiers,omitempty"`
+	DateCreated      time.Time         `json:"date_created,omitempty"`
+	DateModified     time.Time         `json:"date_modified,omitempty"`
+}
+
+// PriceListAssignment assigns a price list to a customer group, a channel or both
+type PriceListAssignment struct {
+	ID              int64 `json:"id,omitempty"`
+	PriceListID     int64 `json:"price_list_id"`
+	CustomerGroupID int64 `json:"customer_group_id,omitempty"`
+	ChannelID       int64 `json:"channel_id,omitempty"`
+}
+
+// priceRecordPayload is a price record as sent on upsert, without the read-only fields
+type priceRecordPayload struct {
+	VariantID        int64             `json:"variant_id,omitempty"`
+	Sku              string            `json:"sku,omitempty"`
+	Currency         string            `json:"currency"`
+	Price            float64           `json:"price"`
+	SalePrice        float64           `json:"sale_price,omitempty"`
+	RetailPrice      float64           `json:"retail_price,omitempty"`
+	MapPrice         float64           `json:"map_price,omitempty"`
+	BulkPricingTiers []BulkPricingRule `json:"bulk_pricing_tiers,omitempty"`
+}
+
+func newPriceRecordPayload(r PriceRecord) priceRecordPayload {
+	p := priceRecordPayload{
+		VariantID:   r.VariantID,
+		Currency:    r.Currency,
+		Price:       r.Price,
+		SalePrice:   r.SalePrice,
+		RetailPrice: r.RetailPrice,
+		MapPrice:    r.MapPrice,
+	}
+	if r.VariantID == 0 {
+		p.Sku = r.Sku
+	}
+	for _, t := range r.BulkPricingTiers {
+		t.ID = 0
+		p.BulkPricingTiers = append(p.BulkPricingTiers, t)
+	}
+	return p
+}
+
+// withArgs adds args to the query of path
+func withArgs(path string, args map[string]string) string {
+	q := neturl.Values{}
+	for k, v := range args {
+		q.Set(k, v)
+	}
+	if len(q) == 0 {
+		return path
+	}
+	sep := "?"
+	if strings.Contains(path, "?") {
+		sep = "&"
+	}
+	return path + sep + q.Encode()
+}
+
+// GetAllPriceLists returns all the price lists
+// args is a map of arguments to pass to the API, e.g. name or active
+func (bc *Client) GetAllPriceLists(args map[string]string) ([]PriceList, error) {
+	ret := []PriceList{}
+	page := 1
+	more := true
+	for more {
+		var pls []PriceList
+		var err error
+		pls, more, err = bc.GetPriceLists(args, page)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, pls...)
+		page++
+	}
+	return ret, nil
+}
+
+// GetPriceLists returns a page of price lists, and whether there are more pages
+func (bc *Client) GetPriceLists(args map[string]string, page int) ([]PriceList, bool, error) {
+	var pls []PriceList
+	more, err := bc.getPage(withArgs("/v3/pricelists", args), page, &pls)
+	if err != nil {
+		return nil, false, err
+	}
+	return pls, more, nil
+}
+
+// GetPriceList returns a price list by ID
+func (bc *Client) GetPriceList(priceListID int64) (*PriceList, error) {
+	var ret PriceList
+	err := bc.sendJSON(http.MethodGet, fmt.Sprintf("/v3/pricelists/%d", priceListID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// CreatePriceList creates a price list
+func (bc *Client) CreatePriceList(priceList *PriceList) (*PriceList, error) {
+	var ret PriceList
+	err := bc.sendJSON(http.MethodPost, "/v3/pricelists", map[string]interface{}{"name": priceList.Name, "active": priceList.Active}, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// UpdatePriceList updates the name and active flag of a price list by ID
+func (bc *Client) UpdatePriceList(priceList *PriceList) (*PriceList, error) {
+	if priceList.ID == 0 {
+		return nil, errors.New("price list has no ID")
+	}
+	var ret PriceList
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/pricelists/%d", priceList.ID),
+		map[string]interface{}{"name": priceList.Name, "active": priceList.Active}, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// DeletePriceList deletes a price list with its records and assignments
+func (bc *Client) DeletePriceList(priceListID int64) error {
+	err := bc.sendJSON(http.MethodDelete, fmt.Sprintf("/v3/pricelists/%d", priceListID), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// GetAllPriceRecords returns all the records of a price list
+// args is a map of arguments to pass to the API, e.g. currency, variant_id:in or sku:in
+func (bc *Client) GetAllPriceRecords(priceListID int64, args map[string]string) ([]PriceRecord, error) {
+	q := map[string]string{"limit": "250"}
+	for k, v := range args {
+		q[k] = v
+	}
+	ret := []PriceRecord{}
+	page := 1
+	more := true
+	for more {
+		var rs []PriceRecord
+		var err error
+		rs, more, err = bc.GetPriceRecords(priceListID, q, page)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, rs...)
+		page++
+	}
+	return ret, nil
+}
+
+// GetPriceRecords returns a page of the records of a price list, and whether there are more pages
+func (bc *Client) GetPriceRecords(priceListID int64, args map[string]string, page int) ([]PriceRecord, bool, error) {
+	var rs []PriceRecord
+	more, err := bc.getPage(withArgs(fmt.Sprintf("/v3/pricelists/%d/records", priceListID), args), page, &rs)
+	if err != nil {
+		return nil, false, err
+	}
+	return rs, more, nil
+}
+
+// GetPriceRecord returns the record of a variant in a currency, ErrNotFound if there is none
+func (bc *Client) GetPriceRecord(priceListID, variantID int64, currency string) (*PriceRecord, error) {
+	var ret PriceRecord
+	err := bc.sendJSON(http.MethodGet, fmt.Sprintf("/v3/pricelists/%d/records/%d/%s", priceListID, variantID, neturl.PathEscape(currency)), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// UpsertPriceRecords creates or replaces price records, in batches of 1000. It returns the errors
+// of the records that were not saved by position in records, and an error if any record failed.
+// The API rejects a whole batch when one of its records is invalid.
+func (bc *Client) UpsertPriceRecords(priceListID int64, records []PriceRecord) (map[int]error, error) {
+	failed := map[int]error{}
+	for start := 0; start < len(records); start += priceRecordBatchSize {
+		end := start + priceRecordBatchSize
+		if end > len(records) {
+			end = len(records)
+		}
+		payload := []priceRecordPayload{}
+		for _, r := range records[start:end] {
+			payload = append(payload, newPriceRecordPayload(r))
+		}
+		itemErrs, err := bc.upsertPriceRecordBatch(priceListID, payload)
+		for i := start; i < end; i++ {
+			if e := itemErrs[i-start]; e != nil {
+				failed[i] = e
+			} else if err != nil {
+				failed[i] = err
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return failed, fmt.Errorf("%d of %d price records failed to save", len(failed), len(records))
+	}
+	return failed, nil
+}
+
+// upsertPriceRecordBatch sends one batch, returning the errors of the records by position in the batch
+func (bc *Client) upsertPriceRecordBatch(priceListID int64, records []priceRecordPayload) (map[int]error, error) {
+	return bc.sendBatch(http.MethodPut, fmt.Sprintf("/v3/pricelists/%d/records", priceListID), records, len(records), nil)
+}
+
+// DeletePriceRecords deletes the records of variants in all currencies
+func (bc *Client) DeletePriceRecords(priceListID int64, variantIDs []int64) error {
+	return bc.deletePriceRecords(priceListID, "variant_id:in", joinIDs(variantIDs))
+}
+
+// DeletePriceRecordsBySku deletes the records of the variants with the SKUs in all currencies
+func (bc *Client) DeletePriceRecordsBySku(priceListID int64, skus []string) error {
+	return bc.deletePriceRecords(priceListID, "sku:in", strings.Join(skus, ","))
+}
+
+func (bc *Client) deletePriceRecords(priceListID int64, filter, values string) error {
+	if values == "" {
+		return errors.New("no price records to delete")
+	}
+	path := withArgs(fmt.Sprintf("/v3/pricelists/%d/records", priceListID), map[string]string{filter: values})
+	err := bc.sendJSON(http.MethodDelete, path, nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// GetAllPriceListAssignments returns all the price list assignments
+// args is a map of arguments to pass to the API, e.g. price_list_id, customer_group_id:in or channel_id:in
+func (bc *Client) GetAllPriceListAssignments(args map[string]string) ([]PriceListAssignment, error) {
+	ret := []PriceListAssignment{}
+	page := 1
+	more := true
+	for more {
+		var as []PriceListAssignment
+		var err error
+		as, more, err = bc.GetPriceListAssignments(args, page)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, as...)
+		page++
+	}
+	return ret, nil
+}
+
+// GetPriceListAssignments returns a page of price list assignments, and whether there are more pages
+func (bc *Client) GetPriceListAssignments(args map[string]string, page int) ([]PriceListAssignment, bool, error) {
+	var as []PriceListAssignment
+	more, err := bc.getPage(withArgs("/v3/pricelists/assignments", args), page, &as)
+	if err != nil {
+		return nil, false, err
+	}
+	return as, more, nil
+}
+
+// CreatePriceListAssignments assigns price lists to customer groups and channels, a customer group
+// has at most one price list per channel
+func (bc *Client) CreatePriceListAssignments(assignments []PriceListAssignment) error {
+	payload := []PriceListAssignment{}
+	for _, a := range assignments {
+		if a.PriceListID == 0 {
+			return errors.New("price list assignment has no price list ID")
+		}
+		a.ID = 0
+		payload = append(payload, a)
+	}
+	err := bc.sendJSON(http.MethodPost, "/v3/pricelists/assignments", payload, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// DeletePriceListAssignments deletes the assignments matching args, e.g. id:in, price_list_id,
+// customer_group_id or channel_id. At least one filter is required.
+func (bc *Client) DeletePriceListAssignments(args map[string]string) error {
+	if len(args) == 0 {
+		return errors.New("no price list assignments to delete")
+	}
+	err := bc.sendJSON(http.MethodDelete, withArgs("/v3/pricelists/assignments", args), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package bigcommerce
+
+import (
+	"errors"
+	"net/http"
+)
+
+// pricingBatchSize is the maximum number of items BigCommerce prices in one request
+const pricingBatchSize = 50
+
+// PricingRequest asks for the prices of items as seen by a customer group, in a currency and channel.
+// CustomerGroupID 0 is the guest group, CurrencyCode empty is the default currency of the channel.
+type PricingRequest struct {
+	ChannelID       int64         `json:"channel_id"`
+	CurrencyCode    string        `json:"currency_code,omitempty"`
+	CustomerGroupID int64         `json:"customer_group_id"`
+	Items           []PricingItem `json:"items"`
+}
+
+// PricingItem is a product to price, with a variant or option selections. Without variant and options
+// the price range of the product is returned.
+type PricingItem struct {
+	ProductID int64                    `json:"product_id"`
+	VariantID int64                    `json:"variant_id,omitempty"`
+	Options   []PricingOptionSelection `json:"options,omitempty"`
+}
+
+// PricingOptionSelection is a value selected for an option or modifier
+type PricingOptionSelection struct {
+	OptionID int64 `json:"option_id"`
+	ValueID  int64 `json:"value_id"`
+}
+
+// PriceValue is a price with and without tax, AsEntered is the price as set in the catalog and
+// EnteredInclusive whether it includes tax
+type PriceValue struct {
+	AsEntered        float64 `json:"as_entered"`
+	EnteredInclusive bool    `json:"entered_inclusive"`
+	TaxExclusive     float64 `json:"tax_exclusive"`
+	TaxInclusive     float64 `json:"tax_inclusive"`
+}
+
+// PriceRange is the lowest and highest prices of the variants of a product
+type PriceRange struct {
+	Minimum PriceValue `json:"minimum"`
+	Maximum PriceValue `json:"maximum"`
+}
+
+// PricingBulkTier is a quantity discount, DiscountType is price, percent or fixed.
+// Maximum is 0 for no maximum.
+type PricingBulkTier struct {
+	Minimum           int     `json:"minimum"`
+	Maximum           int     `json:"maximum"`
+	DiscountAmount    float64 `json:"discount_amount"`
+	DiscountType      string  `json:"discount_type"`
+	TaxDiscountAmount []struct {
+		TaxInclusive float64 `json:"tax_inclusive"`
+		TaxExclusive float64 `json:"tax_exclusive"`
+	} `json:"tax_discount_amount"`
+}
+
+// ProductPrice is the computed price of a PricingItem, sale, retail and MAP prices are nil when not set.
+// CalculatedPrice is the price the customer pays: the sale price if any, the price otherwise.
+type ProductPrice struct {
+	ProductID              int64                    `json:"product_id"`
+	VariantID              int64                    `json:"variant_id"`
+	Options                []PricingOptionSelection `json:"options"`
+	ReferenceRequest       PricingItem              `json:"reference_request"`
+	Price                  PriceValue               `json:"price"`
+	SalePrice              *PriceValue              `json:"sale_price"`
+	RetailPrice            *PriceValue              `json:"retail_price"`
+	MinimumAdvertisedPrice *PriceValue              `json:"minimum_advertised_price"`
+	CalculatedPrice        PriceValue               `json:"calculated_price"`
+	PriceRange             PriceRange               `json:"price_range"`
+	RetailPriceRange       PriceRange               `json:"retail_price_range"`
+	BulkPricing            []PricingBulkTier        `json:"bulk_pricing"`
+}
+
+// GetProductPrices returns the prices computed by BigCommerce for the items of the request, in
+// the order of the items. Requests with more than 50 items are split in several calls.
+func (bc *Client) GetProductPrices(request PricingRequest) ([]ProductPrice, error) {
+	if request.ChannelID == 0 {
+		return nil, errors.New("pricing request has no channel ID")
+	}
+	ret := []ProductPrice{}
+	items := request.Items
+	for start := 0; start < len(items); start += pricingBatchSize {
+		end := start + pricingBatchSize
+		if end > len(items) {
+			end = len(items)
+		}
+		request.Items = items[start:end]
+		var prices []ProductPrice
+		err := bc.sendJSON(http.MethodPost, "/v3/pricing/products", request, &prices)
+		if err != nil {
+			return ret, err
+		}
+		ret = append(ret, prices...)
+	}
+	return ret, nil
+}
@@ -8,6 +8,9 @@ import (
 	"io/ioutil"
 	"log"
 	"net/http"
+	neturl "net/url"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -41,7 +44,7 @@ type Product struct {
 	TaxClassID              int64         `json:"tax_class_id"`
 	ProductTaxCode          string        `json:"product_tax_code,omitempty"`
 	CalculatedPrice         float64       `json:"calculated_price,omitempty"`
-	Categories              []interface{} `json:"categories,omitempty"`
+	Categories              []int64       `json:"categories,omitempty"`
 	BrandID                 int64         `json:"brand_id,omitempty"`
 	OptionSetID             interface{}   `json:"option_set_id,omitempty"`
 	OptionSetDisplay        string        `json:"option_set_display,omitempty"`
@@ -121,19 +124,15 @@ type Product struct {
 		InventoryLevel            int           `json:"inventory_level,omitempty"`
 		InventoryWarningLevel     int           `json:"inventory_warning_level,omitempty"`
 		BinPickingNumber          string        `json:"bin_picking_number,omitempty"`
-		OptionValues              []interface{} `json:"option_values,omitempty"`
+		OptionValues              []OptionValue `json:"option_values,omitempty"`
 	} `json:"variants,omitempty"`
-	Images       []Image       `json:"images,omitempty"`
-	PrimaryImage interface{}   `json:"primary_image,omitempty"`
-	Videos       []interface{} `json:"videos,omitempty"`
-	CustomFields []struct {
-		ID    int64  `json:"id,omitempty"`
-		Name  string `json:"name,omitempty"`
-		Value string `json:"value,omitempty"`
-	} `json:"custom_fields,omitempty"`
-	BulkPricingRules []interface{} `json:"bulk_pricing_rules,omitempty"`
-	Options          []interface{} `json:"options,omitempty"`
-	Modifiers        []interface{} `json:"modifiers,omitempty"`
+	Images           []Image           `json:"images,omitempty"`
+	PrimaryImage     interface{}       `json:"primary_image,omitempty"`
+	Videos           []Video           `json:"videos,omitempty"`
+	CustomFields     []CustomField     `json:"custom_fields,omitempty"`
+	BulkPricingRules []BulkPricingRule `json:"bulk_pricing_rules,omitempty"`
+	Options          []VariantOption   `json:"options,omitempty"`
+	Modifiers        []Modifier        `json:"modifiers,omitempty"`
 }
 
 type ProductInventory struct {
@@ -179,7 +178,7 @@ type Variant struct {
 	InventoryLevel            int           `json:"inventory_level"`
 	InventoryWarningLevel     int           `json:"inventory_warning_level,omitempty"`
 	BinPickingNumber          string        `json:"bin_picking_number,omitempty"`
-	OptionValues              []interface{} `json:"option_values,omitempty"`
+	OptionValues              []OptionValue `json:"option_values,omitempty"`
 }
 
 type VariantInventory struct {
@@ -195,12 +194,14 @@ type VariantSalePrice struct {
 	SalePrice float64 `json:"sale_price"`
 }
 
-// Metafield is a struct representing a BigCommerce product metafield
+// Metafield is a struct representing a BigCommerce metafield
 type Metafield struct {
-	ID            int64     `json:"id,omitempty"`
-	Key           string    `json:"key,omitempty"`
-	Value         string    `json:"value,omitempty"`
-	ResourceID    int64     `json:"resource_id,omitempty"`
+	ID         int64  `json:"id,omitempty"`
+	Key        string `json:"key,omitempty"`
+	Value      string `json:"value,omitempty"`
+	ResourceID int64  `json:"resource_id,omitempty"`
+	// ResourceKey is the string resource ID of cart metafields
+	ResourceKey   string    `json:"-"`
 	ResourceType  string    `json:"resource_type,omitempty"`
 	Description   string    `json:"description,omitempty"`
 	DateCreated   time.Time `json:"date_created,omitempty"`
@@ -209,11 +210,6 @@ type Metafield struct {
 	PermissionSet string    `json:"permission_set,omitempty"`
 }
 
-type ChannelAssignment struct {
-	ProductID int64 `json:"product_id,omitempty"`
-	ChannelID int64 `json:"channel_id,omitempty"`
-}
-
 // GetAllProducts gets all products from BigCommerce
 // args is a key-value map of additional arguments to pass to the API
 func (bc *Client) GetAllProducts(args map[string]string) ([]Product, error) {
@@ -355,28 +351,12 @@ func (bc *Client) GetProductByID(productID int64) (*Product, error) {
 // GetProductMetafields gets metafields values for a product
 // productID: BigCommerce product ID to get metafields for
 func (bc *Client) GetProductMetafields(productID int64) (map[string]Metafield, error) {
-	url := "/v3/catalog/products/" + strconv.FormatInt(productID, 10) + "/metafields"
-	req := bc.getAPIRequest(http.MethodGet, url, nil)
-	res, err := bc.HTTPClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-
-	defer res.Body.Close()
-	body, err := processBody(res)
-	if err != nil {
-		return nil, err
-	}
-
-	var metafieldsResponse struct {
-		Metafields []Metafield `json:"data,omitempty"`
-	}
-	err = json.Unmarshal(body, &metafieldsResponse)
+	ms, err := bc.GetAllMetafields(ProductMetafieldOwner(productID), nil)
 	if err != nil {
 		return nil, err
 	}
 	ret := map[string]Metafield{}
-	for _, mf := range metafieldsResponse.Metafields {
+	for _, mf := range ms {
 		ret[mf.Key] = mf
 	}
 	return ret, nil
@@ -724,42 +704,290 @@ func (bc *Client) UpdateVariantSalePrice(variantSalePricePayload *VariantSalePri
 	return &variantResponse.Data, nil
 }
 
-// deletes a product from a specific channel
+// DeleteProductFromChannel removes a product from a channel, see DeleteChannelAssignments for many
 func (bc *Client) DeleteProductFromChannel(productId int64, channelId int64) (bool, error) {
-	req := bc.getAPIRequest(http.MethodDelete, fmt.Sprintf("/v3/catalog/products/channel-assignments?product_id:in=%d&channel_id:in=%d", productId, channelId), nil)
-	res, err := bc.HTTPClient.Do(req)
+	err := bc.DeleteChannelAssignments([]int64{productId}, []int64{channelId})
 	if err != nil {
 		return false, err
 	}
-	defer res.Body.Close()
+	return true, nil
+}
 
-	if res.StatusCode > 200 && res.StatusCode < 300 {
-		return true, nil
+// AddProductToChannel assigns a product to a channel, see CreateChannelAssignments for many
+func (bc *Client) AddProductToChannel(productId int64, channelId int64) (bool, error) {
+	_, err := bc.CreateChannelAssignments([]ChannelAssignment{{ProductID: productId, ChannelID: channelId}})
+	if err != nil {
+		return false, err
 	}
+	return true, nil
+}
 
-	return false, nil
+// productBatchSize is the maximum number of products BigCommerce accepts in a batch update
+const productBatchSize = 10
+
+// ProductUpdateResult is the outcome of a single product in UpdateProducts
+type ProductUpdateResult struct {
+	ID      int64
+	Sku     string
+	Product *Product // the updated product, nil if the update failed
+	Err     error
 }
 
-// add a product to a specific channel
-func (bc *Client) AddProductToChannel(productId int64, channelId int64) (bool, error) {
-	var payload []ChannelAssignment
-	var channelAssignment ChannelAssignment
-	channelAssignment.ProductID = productId
-	channelAssignment.ChannelID = channelId
+// ProductUpdateResults holds the UpdateProducts results in input order, by product ID and by SKU.
+// Products that could not be resolved to an ID are not in ByID.
+type ProductUpdateResults struct {
+	Results []*ProductUpdateResult
+	ByID    map[int64]*ProductUpdateResult
+	BySku   map[string]*ProductUpdateResult
+}
 
-	payload = append(payload, channelAssignment)
-	b, _ := json.Marshal(&payload)
+// Failed returns the results of the products that were not updated
+func (r ProductUpdateResults) Failed() []*ProductUpdateResult {
+	ret := []*ProductUpdateResult{}
+	for _, res := range r.Results {
+		if res.Err != nil {
+			ret = append(ret, res)
+		}
+	}
+	return ret
+}
+
+func (r *ProductUpdateResults) add(res *ProductUpdateResult) {
+	r.Results = append(r.Results, res)
+	if res.ID != 0 {
+		r.ByID[res.ID] = res
+	}
+	if res.Sku != "" {
+		r.BySku[res.Sku] = res
+	}
+}
 
-	req := bc.getAPIRequest(http.MethodPut, "/v3/catalog/products/channel-assignments", bytes.NewBuffer(b))
+// GetProductBySku gets a product from BigCommerce by SKU, returns ErrNotFound if there is none
+// sku: product SKU, variant SKUs are not searched
+func (bc *Client) GetProductBySku(sku string) (*Product, error) {
+	url := "/v3/catalog/products?sku=" + neturl.QueryEscape(sku) + "&include=variants,images,custom_fields,bulk_pricing_rules,primary_image,modifiers,options,videos"
+	req := bc.getAPIRequest(http.MethodGet, url, nil)
 	res, err := bc.HTTPClient.Do(req)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+
+	defer res.Body.Close()
+	body, err := processBody(res)
+	if err != nil {
+		return nil, err
+	}
+
+	var productResponse struct {
+		Data []Product `json:"data"`
+	}
+	err = json.Unmarshal(body, &productResponse)
+	if err != nil {
+		return nil, err
+	}
+	if len(productResponse.Data) == 0 {
+		return nil, ErrNotFound
+	}
+	return &productResponse.Data[0], nil
+}
+
+// DeleteProduct deletes a product and its variants from BigCommerce
+// productID: BigCommerce product ID to delete
+func (bc *Client) DeleteProduct(productID int64) error {
+	req := bc.getAPIRequest(http.MethodDelete, "/v3/catalog/products/"+strconv.FormatInt(productID, 10), nil)
+	res, err := bc.HTTPClient.Do(req)
+	if err != nil {
+		return err
 	}
 	defer res.Body.Close()
+	_, err = processBody(res)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// DeleteProducts deletes products from BigCommerce, 250 IDs per request
+// productIDs: BigCommerce product IDs to delete, unknown IDs are ignored by the API
+func (bc *Client) DeleteProducts(productIDs []int64) error {
+	for start := 0; start < len(productIDs); start += 250 {
+		end := start + 250
+		if end > len(productIDs) {
+			end = len(productIDs)
+		}
+		ids := []string{}
+		for _, id := range productIDs[start:end] {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+		req := bc.getAPIRequest(http.MethodDelete, "/v3/catalog/products?id:in="+strings.Join(ids, ","), nil)
+		res, err := bc.HTTPClient.Do(req)
+		if err != nil {
+			return err
+		}
+		_, err = processBody(res)
+		res.Body.Close()
+		if err != nil && err != ErrNoContent {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateProducts updates products with the batch endpoint, in chunks of 10 products.
+// Products are identified by ID, products without ID are looked up by SKU first.
+// Only the fields that are not zero values are sent: a price of 0, is_visible false or an
+// empty list leave the product as it is, use PatchProducts to set them.
+// Every product gets a result, the error is non-nil if any product failed to update.
+func (bc *Client) UpdateProducts(products []Product) (ProductUpdateResults, error) {
+	items := make([]*ProductUpdateResult, len(products))
+	patches := make([]Patch, len(products))
+	pending := []int{}
+	for i, p := range products {
+		items[i] = &ProductUpdateResult{ID: p.ID, Sku: p.Sku}
+		if p.ID == 0 {
+			if p.Sku == "" {
+				items[i].Err = errors.New("product has no ID and no SKU")
+				continue
+			}
+			found, err := bc.GetProductBySku(p.Sku)
+			if err != nil {
+				items[i].Err = err
+				continue
+			}
+			items[i].ID = found.ID
+		}
+		patch, err := setFields(p)
+		if err != nil {
+			items[i].Err = err
+			continue
+		}
+		patch["id"] = items[i].ID
+		patches[i] = patch
+		pending = append(pending, i)
+	}
+	return bc.updateProducts(items, patches, pending)
+}
+
+// PatchProducts updates products with the batch endpoint like UpdateProducts, sending the
+// fields of the patches as they are, zero values included. Every patch must have an "id".
+func (bc *Client) PatchProducts(patches []Patch) (ProductUpdateResults, error) {
+	items := make([]*ProductUpdateResult, len(patches))
+	pending := []int{}
+	for i, p := range patches {
+		items[i] = &ProductUpdateResult{ID: patchID(p)}
+		if items[i].ID == 0 {
+			items[i].Err = errors.New("patch has no product ID")
+			continue
+		}
+		pending = append(pending, i)
+	}
+	return bc.updateProducts(items, patches, pending)
+}
 
-	if res.StatusCode >= 200 && res.StatusCode < 300 {
-		return true, nil
+// updateProducts sends the patches of the pending items in batches and fills in their results
+func (bc *Client) updateProducts(items []*ProductUpdateResult, patches []Patch, pending []int) (ProductUpdateResults, error) {
+	updated, failed := bc.updateBatches("/v3/catalog/products", productBatchSize, patches, pending)
+	results := ProductUpdateResults{
+		ByID:  map[int64]*ProductUpdateResult{},
+		BySku: map[string]*ProductUpdateResult{},
+	}
+	for i, res := range items {
+		if data, ok := updated[i]; ok {
+			var p Product
+			if err := json.Unmarshal(data, &p); err != nil {
+				res.Err = err
+			} else {
+				res.Product = &p
+				if res.Sku == "" {
+					res.Sku = p.Sku
+				}
+			}
+		} else if err, ok := failed[i]; ok {
+			res.Err = err
+		}
+		results.add(res)
+	}
+	if failed := results.Failed(); len(failed) > 0 {
+		return results, fmt.Errorf("%d of %d products failed to update", len(failed), len(items))
 	}
+	return results, nil
+}
 
-	return false, nil
+// updateBatches sends the patches of the pending items to a batch update endpoint, size at a
+// time. It returns the updated objects of the items by index, and the errors of the pending
+// items that were not updated.
+func (bc *Client) updateBatches(path string, size int, patches []Patch, pending []int) (map[int]json.RawMessage, map[int]error) {
+	updated := map[int]json.RawMessage{}
+	failed := map[int]error{}
+	for start := 0; start < len(pending); start += size {
+		end := start + size
+		if end > len(pending) {
+			end = len(pending)
+		}
+		chunk := []Patch{}
+		for _, i := range pending[start:end] {
+			chunk = append(chunk, patches[i])
+		}
+		var data []json.RawMessage
+		itemErrs, err := bc.sendBatch(http.MethodPut, path, chunk, len(chunk), &data)
+		byID := map[int64]json.RawMessage{}
+		for _, d := range data {
+			var obj struct {
+				ID int64 `json:"id"`
+			}
+			if json.Unmarshal(d, &obj) == nil {
+				byID[obj.ID] = d
+			}
+		}
+		for n, i := range pending[start:end] {
+			switch {
+			case byID[patchID(patches[i])] != nil:
+				updated[i] = byID[patchID(patches[i])]
+			case itemErrs[n] != nil:
+				failed[i] = itemErrs[n]
+			case err != nil:
+				failed[i] = err
+			default:
+				failed[i] = errors.New("not returned by batch update")
+			}
+		}
+	}
+	return updated, failed
+}
+
+// batchItemKey matches the item position in batch error keys like "0.name", "[1].sku" or "products.2.price"
+var batchItemKey = regexp.MustCompile(`^(?:[a-z_]+\.)?\[?(\d+)\]?\.?(.*)$`)
+
+// batchErrors splits the errors of a batch response by item position, errors that don't
+// refer to an item are joined in the returned error
+func batchErrors(e ErrorResult, size int) (map[int]error, error) {
+	byItem := map[int][]string{}
+	other := []string{}
+	for k, msg := range e.Errors {
+		m := batchItemKey.FindStringSubmatch(k)
+		if m != nil {
+			i, _ := strconv.Atoi(m[1])
+			if i < size {
+				byItem[i] = append(byItem[i], msg)
+				continue
+			}
+		}
+		other = append(other, msg)
+	}
+	ret := map[int]error{}
+	for i, msgs := range byItem {
+		sort.Strings(msgs)
+		ret[i] = errors.New(strings.Join(msgs, ", "))
+	}
+	if len(other) > 0 {
+		sort.Strings(other)
+		return ret, errors.New(strings.Join(other, ", "))
+	}
+	if len(ret) == 0 {
+		if e.Title != "" {
+			return ret, errors.New(e.Title)
+		}
+		return ret, errors.New("unknown error")
+	}
+	return ret, nil
 }
@@ -0,0 +1,82 @@
+package bigcommerce
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBatchErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		errors   map[string]string
+		title    string
+		size     int
+		wantItem map[int]string
+		wantErr  string
+	}{
+		{
+			name:     "dotted index",
+			errors:   map[string]string{"0.name": "name is required", "2.price": "price is invalid"},
+			size:     3,
+			wantItem: map[int]string{0: "name is required", 2: "price is invalid"},
+		},
+		{
+			name:     "bracketed index",
+			errors:   map[string]string{"[1].sku": "sku is taken"},
+			size:     2,
+			wantItem: map[int]string{1: "sku is taken"},
+		},
+		{
+			name:     "prefixed index",
+			errors:   map[string]string{"products.1.price": "price is invalid", "settings.0.identity.sku": "sku not found"},
+			size:     2,
+			wantItem: map[int]string{0: "sku not found", 1: "price is invalid"},
+		},
+		{
+			name:     "messages of an item are sorted and joined",
+			errors:   map[string]string{"0.sku": "sku is taken", "0.name": "name is required"},
+			size:     1,
+			wantItem: map[int]string{0: "name is required, sku is taken"},
+		},
+		{
+			name:     "index out of the batch",
+			errors:   map[string]string{"0.name": "name is required", "5.name": "name is too long"},
+			size:     2,
+			wantItem: map[int]string{0: "name is required"},
+			wantErr:  "name is too long",
+		},
+		{
+			name:     "errors of the whole batch",
+			errors:   map[string]string{"id": "id 99 not found", "limit": "too many items"},
+			size:     2,
+			wantItem: map[int]string{},
+			wantErr:  "id 99 not found, too many items",
+		},
+		{
+			name:     "title only",
+			title:    "JSON data is missing or invalid",
+			size:     2,
+			wantItem: map[int]string{},
+			wantErr:  "JSON data is missing or invalid",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items, err := batchErrors(ErrorResult{Title: tt.title, Errors: tt.errors}, tt.size)
+			got := map[int]string{}
+			for i, e := range items {
+				got[i] = e.Error()
+			}
+			if !reflect.DeepEqual(got, tt.wantItem) {
+				t.Errorf("item errors %v, want %v", got, tt.wantItem)
+			}
+			gotErr := ""
+			if err != nil {
+				gotErr = err.Error()
+			}
+			if gotErr != tt.wantErr {
+				t.Errorf("error %q, want %q", gotErr, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,214 @@
+package bigcommerce
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrSettingConflict is returned when a setting was changed since it was last read
+var ErrSettingConflict = errors.New("setting was changed since it was read")
+
+// defaultChannelID is the channel of the default storefront, it holds the store-level settings
+const defaultChannelID = 1
+
+// settingsCacheTTL is the default time values are cached by a SettingsStore
+const settingsCacheTTL = 5 * time.Minute
+
+// maxSettingSize is the maximum length of a metafield value
+const maxSettingSize = 65535
+
+// SettingsStore keeps JSON encoded values in app_only metafields of a namespace, so only the app
+// that wrote them can read them. Values are cached for TTL, 0 disables the cache.
+//
+// Set and Delete fail with ErrSettingConflict when the setting was changed by someone else since
+// it was read by this store, compared by DateModified. Call Get again and retry to resolve it.
+// Settings that were never read are written without checking. The check is best effort: the API
+// has no conditional writes, so a change made between the check and the write, or in the same
+// second as the read as DateModified has a precision of one second, is overwritten. Settings
+// written by several processes at once need a lock of their own.
+type SettingsStore struct {
+	Client    MetafieldClient
+	Owner     MetafieldOwner
+	Namespace string
+	TTL       time.Duration
+
+	mu     sync.Mutex
+	cache  map[string]Metafield
+	loaded time.Time
+	// seen is the version of each setting last read or written, a zero time if it did not exist
+	seen map[string]time.Time
+}
+
+// NewSettingsStore returns a settings store in the namespace of the metafields of owner
+func NewSettingsStore(client MetafieldClient, owner MetafieldOwner, namespace string) *SettingsStore {
+	return &SettingsStore{
+		Client:    client,
+		Owner:     owner,
+		Namespace: namespace,
+		TTL:       settingsCacheTTL,
+	}
+}
+
+// Settings returns the store-level settings in namespace, kept on the default channel
+func (bc *Client) Settings(namespace string) *SettingsStore {
+	return NewSettingsStore(bc, ChannelMetafieldOwner(defaultChannelID), namespace)
+}
+
+// ChannelSettings returns the settings of a channel in namespace
+func (bc *Client) ChannelSettings(channelID int64, namespace string) *SettingsStore {
+	return NewSettingsStore(bc, ChannelMetafieldOwner(channelID), namespace)
+}
+
+// load fetches all the settings of the namespace unless the cache is still valid
+func (s *SettingsStore) load() error {
+	if s.cache != nil && time.Since(s.loaded) < s.TTL {
+		return nil
+	}
+	ms, err := s.Client.GetAllMetafields(s.Owner, map[string]string{"namespace": s.Namespace})
+	if err != nil {
+		return err
+	}
+	if s.seen == nil {
+		s.seen = map[string]time.Time{}
+	}
+	s.cache = map[string]Metafield{}
+	for _, m := range ms {
+		if m.Namespace != s.Namespace {
+			continue
+		}
+		s.cache[m.Key] = m
+		s.seen[m.Key] = m.DateModified
+	}
+	s.loaded = time.Now()
+	return nil
+}
+
+// Get unmarshals the setting key into v, ErrNotFound if it is not set
+func (s *SettingsStore) Get(key string, v interface{}) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.load()
+	if err != nil {
+		return err
+	}
+	m, ok := s.cache[key]
+	if !ok {
+		s.seen[key] = time.Time{}
+		return ErrNotFound
+	}
+	err = json.Unmarshal([]byte(m.Value), v)
+	if err != nil {
+		return fmt.Errorf("can't parse setting %s: %v", key, err)
+	}
+	return nil
+}
+
+// Keys returns the sorted keys of the settings
+func (s *SettingsStore) Keys() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	err := s.load()
+	if err != nil {
+		return nil, err
+	}
+	keys := []string{}
+	for k := range s.cache {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// current fetches the setting key bypassing the cache, and checks it was not changed since it was
+// seen. It can't rule out a change made after it returns, see SettingsStore.
+func (s *SettingsStore) current(key string) (*Metafield, error) {
+	m, err := s.Client.GetMetafieldByKey(s.Owner, s.Namespace, key)
+	if err == ErrNotFound {
+		m, err = nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	seen, ok := s.seen[key]
+	if !ok {
+		return m, nil
+	}
+	if (m == nil && !seen.IsZero()) || (m != nil && !m.DateModified.Equal(seen)) {
+		s.cache = nil
+		return nil, ErrSettingConflict
+	}
+	return m, nil
+}
+
+// Set stores v as JSON in the setting key
+func (s *SettingsStore) Set(key string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if len(b) > maxSettingSize {
+		return fmt.Errorf("setting %s is %d bytes, the maximum is %d", key, len(b), maxSettingSize)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, err := s.current(key)
+	if err != nil {
+		return err
+	}
+	want := &Metafield{
+		Namespace:     s.Namespace,
+		Key:           key,
+		Value:         string(b),
+		PermissionSet: "app_only",
+	}
+	if m == nil {
+		m, err = s.Client.CreateMetafield(s.Owner, want)
+	} else {
+		want.ID = m.ID
+		m, err = s.Client.UpdateMetafield(s.Owner, want)
+	}
+	if err != nil {
+		return err
+	}
+	if s.seen == nil {
+		s.seen = map[string]time.Time{}
+	}
+	s.seen[key] = m.DateModified
+	if s.cache != nil {
+		s.cache[key] = *m
+	}
+	return nil
+}
+
+// Delete removes the setting key, deleting a setting that is not set is not an error
+func (s *SettingsStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m, err := s.current(key)
+	if err != nil {
+		return err
+	}
+	if m != nil {
+		err = s.Client.DeleteMetafield(s.Owner, m.ID)
+		if err != nil && err != ErrNotFound {
+			return err
+		}
+	}
+	if s.seen == nil {
+		s.seen = map[string]time.Time{}
+	}
+	s.seen[key] = time.Time{}
+	delete(s.cache, key)
+	return nil
+}
+
+// Invalidate drops the cached values, they are fetched again on the next read
+func (s *SettingsStore) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache = nil
+}
@@ -0,0 +1,165 @@
+package bigcommerce
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+)
+
+// Site is the storefront URL of a channel, a channel has at most one site
+type Site struct {
+	ID        int64     `json:"id,omitempty"`
+	URL       string    `json:"url"`
+	ChannelID int64     `json:"channel_id"`
+	SSLStatus string    `json:"ssl_status,omitempty"`
+	URLs      []SiteURL `json:"urls,omitempty"`
+	CreatedAt string    `json:"created_at,omitempty"`
+	UpdatedAt string    `json:"updated_at,omitempty"`
+}
+
+// SiteURL is one of the URLs of a site, Type is "primary", "canonical" or "checkout"
+type SiteURL struct {
+	URL       string `json:"url"`
+	Type      string `json:"type"`
+	CreatedAt string `json:"created_at,omitempty"`
+	UpdatedAt string `json:"updated_at,omitempty"`
+}
+
+// SiteRoute maps a type of storefront page to a path of a headless site, like type "product",
+// Matching "*" and Route "/products/{id}". Matching is an entity ID or "*" for all.
+type SiteRoute struct {
+	ID       int64  `json:"id,omitempty"`
+	Type     string `json:"type"`
+	Matching string `json:"matching"`
+	Route    string `json:"route"`
+}
+
+// GetSites returns the sites, only those of the given channels if any
+func (bc *Client) GetSites(channelIDs ...int64) ([]Site, error) {
+	path := "/v3/sites?limit=250"
+	if len(channelIDs) > 0 {
+		path += "&channel_id:in=" + joinIDs(channelIDs)
+	}
+	ret := []Site{}
+	page := 1
+	more := true
+	for more {
+		var ss []Site
+		var err error
+		more, err = bc.getPage(path, page, &ss)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, ss...)
+		page++
+	}
+	return ret, nil
+}
+
+// GetSite returns a site by ID
+func (bc *Client) GetSite(siteID int64) (*Site, error) {
+	var ret Site
+	err := bc.sendJSON(http.MethodGet, fmt.Sprintf("/v3/sites/%d", siteID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// GetChannelSite returns the site of a channel, ErrNotFound if it has none
+func (bc *Client) GetChannelSite(channelID int64) (*Site, error) {
+	var ret Site
+	err := bc.sendJSON(http.MethodGet, fmt.Sprintf("/v3/channels/%d/site", channelID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// CreateSite creates the site of a channel, URL and ChannelID are required
+func (bc *Client) CreateSite(site *Site) (*Site, error) {
+	if site.ChannelID == 0 {
+		return nil, errors.New("site has no channel ID")
+	}
+	payload := map[string]interface{}{"url": site.URL, "channel_id": site.ChannelID}
+	var ret Site
+	err := bc.sendJSON(http.MethodPost, "/v3/sites", payload, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// UpdateSite changes the URL of a site by ID
+func (bc *Client) UpdateSite(site *Site) (*Site, error) {
+	if site.ID == 0 {
+		return nil, errors.New("site has no ID")
+	}
+	var ret Site
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/sites/%d", site.ID), map[string]interface{}{"url": site.URL}, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// DeleteSite deletes a site with its routes
+func (bc *Client) DeleteSite(siteID int64) error {
+	err := bc.sendJSON(http.MethodDelete, fmt.Sprintf("/v3/sites/%d", siteID), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// GetSiteRoutes returns the routes of a site, only those of routeType if it is not empty
+func (bc *Client) GetSiteRoutes(siteID int64, routeType string) ([]SiteRoute, error) {
+	path := fmt.Sprintf("/v3/sites/%d/routes?limit=250", siteID)
+	if routeType != "" {
+		path += "&type=" + neturl.QueryEscape(routeType)
+	}
+	ret := []SiteRoute{}
+	page := 1
+	more := true
+	for more {
+		var rs []SiteRoute
+		var err error
+		more, err = bc.getPage(path, page, &rs)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, rs...)
+		page++
+	}
+	return ret, nil
+}
+
+// CreateSiteRoute adds a route to a site
+func (bc *Client) CreateSiteRoute(siteID int64, route *SiteRoute) (*SiteRoute, error) {
+	var ret SiteRoute
+	err := bc.sendJSON(http.MethodPost, fmt.Sprintf("/v3/sites/%d/routes", siteID), route, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// UpsertSiteRoutes updates the routes with ID and creates the others
+func (bc *Client) UpsertSiteRoutes(siteID int64, routes []SiteRoute) ([]SiteRoute, error) {
+	var ret []SiteRoute
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/sites/%d/routes", siteID), routes, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// DeleteSiteRoute deletes a route of a site
+func (bc *Client) DeleteSiteRoute(siteID, routeID int64) error {
+	err := bc.sendJSON(http.MethodDelete, fmt.Sprintf("/v3/sites/%d/routes/%d", siteID, routeID), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
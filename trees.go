@@ -0,0 +1,120 @@
+package bigcommerce
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// CatalogTree is a category tree of the catalog, a channel has at most one tree
+type CatalogTree struct {
+	ID       int64   `json:"id,omitempty"`
+	Name     string  `json:"name"`
+	Channels []int64 `json:"channels"`
+}
+
+// categoryNode is a category in the nested response of the tree categories endpoint
+type categoryNode struct {
+	ID        int64          `json:"id"`
+	ParentID  int64          `json:"parent_id"`
+	Name      string         `json:"name"`
+	IsVisible bool           `json:"is_visible"`
+	URL       string         `json:"url"`
+	Children  []categoryNode `json:"children"`
+}
+
+// flatten appends the node and its children to categories, the endpoint has no sort order
+// so SortOrder is set to the position in the tree
+func (n categoryNode) flatten(treeID int64, categories []Category) []Category {
+	c := Category{
+		ID:       n.ID,
+		Name:     n.Name,
+		ParentID: n.ParentID,
+		Visible:  n.IsVisible,
+		URL:      n.URL,
+		TreeID:   treeID,
+	}
+	c.CustomURL.URL = n.URL
+	c.SortOrder = int64(len(categories))
+	categories = append(categories, c)
+	for _, child := range n.Children {
+		categories = child.flatten(treeID, categories)
+	}
+	return categories
+}
+
+// GetCatalogTrees returns the category trees, only those of the given channels if any
+func (bc *Client) GetCatalogTrees(channelIDs ...int64) ([]CatalogTree, error) {
+	path := "/v3/catalog/trees?limit=250"
+	if len(channelIDs) > 0 {
+		ids := []string{}
+		for _, id := range channelIDs {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+		path += "&channel_id:in=" + strings.Join(ids, ",")
+	}
+	ret := []CatalogTree{}
+	page := 1
+	more := true
+	for more {
+		var ts []CatalogTree
+		var err error
+		more, err = bc.getPage(path, page, &ts)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, ts...)
+		page++
+	}
+	return ret, nil
+}
+
+// UpsertCatalogTrees creates the trees without ID and updates the others
+func (bc *Client) UpsertCatalogTrees(trees []CatalogTree) ([]CatalogTree, error) {
+	var ret []CatalogTree
+	err := bc.sendJSON(http.MethodPut, "/v3/catalog/trees", trees, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return ret, nil
+}
+
+// DeleteCatalogTrees deletes category trees with their categories
+func (bc *Client) DeleteCatalogTrees(treeIDs []int64) error {
+	ids := []string{}
+	for _, id := range treeIDs {
+		ids = append(ids, strconv.FormatInt(id, 10))
+	}
+	err := bc.sendJSON(http.MethodDelete, "/v3/catalog/trees?id:in="+strings.Join(ids, ","), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// GetCategoryTree returns the categories of a tree, in the order of the tree
+func (bc *Client) GetCategoryTree(treeID int64) (*CategoryTree, error) {
+	var nodes []categoryNode
+	err := bc.sendJSON(http.MethodGet, fmt.Sprintf("/v3/catalog/trees/%d/categories", treeID), nil, &nodes)
+	if err != nil {
+		return nil, err
+	}
+	categories := []Category{}
+	for _, n := range nodes {
+		categories = n.flatten(treeID, categories)
+	}
+	return NewCategoryTree(categories), nil
+}
+
+// GetChannelCategoryTree returns the categories of the tree of a channel
+func (bc *Client) GetChannelCategoryTree(channelID int64) (*CategoryTree, error) {
+	trees, err := bc.GetCatalogTrees(channelID)
+	if err != nil {
+		return nil, err
+	}
+	if len(trees) == 0 {
+		return nil, ErrNotFound
+	}
+	return bc.GetCategoryTree(trees[0].ID)
+}
@@ -0,0 +1,369 @@
+package bigcommerce
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// MatrixOption is an option of a variant matrix with the labels of its values
+type MatrixOption struct {
+	Name   string // display name of the option, e.g. "Color"
+	Type   string // option type, rectangles when empty
+	Values []string
+}
+
+// VariantMatrix describes the variants of a product as the cartesian product of its options
+type VariantMatrix struct {
+	Options []MatrixOption
+	// SkuTemplate builds the variant SKUs, e.g. "{{base}}-{{color}}-{{size}}": {{base}} is the
+	// product SKU, the other placeholders are the option names in lower case with underscores
+	// for spaces. Values are inserted with dashes for spaces. Empty joins base and values with dashes.
+	SkuTemplate string
+	// Variant holds the fields of new variants, like price, weight or inventory level
+	Variant Variant
+}
+
+// MatrixVariant is a combination of option values of a matrix
+type MatrixVariant struct {
+	Sku    string
+	Values map[string]string // value label by option name
+}
+
+// MatrixPlan lists the changes that bring a product to a variant matrix.
+// Variants without option values, like the base variant of a product without options, are left alone.
+type MatrixPlan struct {
+	ProductID      int64
+	DeleteVariants []Variant
+	DeleteOptions  []VariantOption // options not in the matrix, BigCommerce deletes their variants too
+	CreateOptions  []MatrixOption
+	CreateValues   map[string][]string // labels of new values by option name, for existing options
+	CreateVariants []MatrixVariant
+	UpdateSkus     []Variant // existing variants with the SKU of the template
+	Unchanged      []Variant
+	matrix         VariantMatrix
+}
+
+// Empty returns true if the product already matches the matrix
+func (p *MatrixPlan) Empty() bool {
+	return len(p.DeleteVariants) == 0 && len(p.DeleteOptions) == 0 && len(p.CreateOptions) == 0 &&
+		len(p.CreateValues) == 0 && len(p.CreateVariants) == 0 && len(p.UpdateSkus) == 0
+}
+
+// String returns a readable summary of the plan, one change per line
+func (p *MatrixPlan) String() string {
+	lines := []string{}
+	for _, v := range p.DeleteVariants {
+		lines = append(lines, fmt.Sprintf("delete variant %d %s", v.ID, v.Sku))
+	}
+	for _, o := range p.DeleteOptions {
+		lines = append(lines, fmt.Sprintf("delete option %d %s", o.ID, o.DisplayName))
+	}
+	for _, o := range p.CreateOptions {
+		lines = append(lines, fmt.Sprintf("create option %s: %s", o.Name, strings.Join(o.Values, ", ")))
+	}
+	for _, o := range p.matrix.Options {
+		if vs, ok := p.CreateValues[o.Name]; ok {
+			lines = append(lines, fmt.Sprintf("add values to option %s: %s", o.Name, strings.Join(vs, ", ")))
+		}
+	}
+	for _, v := range p.CreateVariants {
+		lines = append(lines, "create variant "+v.Sku)
+	}
+	for _, v := range p.UpdateSkus {
+		lines = append(lines, fmt.Sprintf("set SKU of variant %d to %s", v.ID, v.Sku))
+	}
+	if len(lines) == 0 {
+		return "no changes"
+	}
+	return strings.Join(lines, "\n")
+}
+
+var skuPlaceholder = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_]+)\s*\}\}`)
+
+func matrixKey(name string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), " ", "_")
+}
+
+// Variants returns the combinations of the matrix with their SKUs, the first option varies slowest
+// baseSku: SKU of the product, for the {{base}} placeholder
+func (m VariantMatrix) Variants(baseSku string) ([]MatrixVariant, error) {
+	if len(m.Options) == 0 {
+		return nil, errors.New("variant matrix has no options")
+	}
+	keys := map[string]bool{"base": true}
+	for _, o := range m.Options {
+		if len(o.Values) == 0 {
+			return nil, fmt.Errorf("option %s has no values", o.Name)
+		}
+		k := matrixKey(o.Name)
+		if keys[k] {
+			return nil, fmt.Errorf("duplicate option %s", o.Name)
+		}
+		keys[k] = true
+	}
+	for _, ph := range skuPlaceholder.FindAllStringSubmatch(m.SkuTemplate, -1) {
+		if !keys[strings.ToLower(ph[1])] {
+			return nil, fmt.Errorf("unknown placeholder %s in SKU template", ph[0])
+		}
+	}
+
+	combos := []map[string]string{{}}
+	for _, o := range m.Options {
+		next := []map[string]string{}
+		for _, c := range combos {
+			for _, v := range o.Values {
+				nc := map[string]string{}
+				for k, cv := range c {
+					nc[k] = cv
+				}
+				nc[o.Name] = v
+				next = append(next, nc)
+			}
+		}
+		combos = next
+	}
+
+	ret := []MatrixVariant{}
+	skus := map[string]bool{}
+	for _, c := range combos {
+		sku := m.sku(baseSku, c)
+		if skus[sku] {
+			return nil, fmt.Errorf("SKU template gives duplicate SKU %s", sku)
+		}
+		skus[sku] = true
+		ret = append(ret, MatrixVariant{Sku: sku, Values: c})
+	}
+	return ret, nil
+}
+
+func (m VariantMatrix) sku(baseSku string, values map[string]string) string {
+	dashed := func(s string) string {
+		return strings.Join(strings.Fields(s), "-")
+	}
+	if m.SkuTemplate == "" {
+		parts := []string{}
+		if baseSku != "" {
+			parts = append(parts, baseSku)
+		}
+		for _, o := range m.Options {
+			parts = append(parts, dashed(values[o.Name]))
+		}
+		return strings.Join(parts, "-")
+	}
+	byKey := map[string]string{"base": baseSku}
+	for _, o := range m.Options {
+		byKey[matrixKey(o.Name)] = dashed(values[o.Name])
+	}
+	return skuPlaceholder.ReplaceAllStringFunc(m.SkuTemplate, func(ph string) string {
+		return byKey[strings.ToLower(skuPlaceholder.FindStringSubmatch(ph)[1])]
+	})
+}
+
+// variantLabels returns the value label of a variant by lower case option name
+func variantLabels(v Variant) map[string]string {
+	ret := map[string]string{}
+	for _, ov := range v.OptionValues {
+		ret[strings.ToLower(ov.OptionDisplayName)] = strings.ToLower(ov.Label)
+	}
+	return ret
+}
+
+// PlanVariantMatrix compares the options and variants of a product with a matrix and returns the changes
+// ReconcileVariantMatrix would make. Options and values are matched by name, case insensitive.
+func (bc *Client) PlanVariantMatrix(product *Product, matrix VariantMatrix) (*MatrixPlan, error) {
+	combos, err := matrix.Variants(product.Sku)
+	if err != nil {
+		return nil, err
+	}
+	options, err := bc.GetVariantOptions(product.ID)
+	if err != nil {
+		return nil, err
+	}
+	variants, err := bc.GetAllProductVariants(product.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+	plan := &MatrixPlan{ProductID: product.ID, CreateValues: map[string][]string{}, matrix: matrix}
+
+	inMatrix := map[string]bool{}
+	for _, o := range matrix.Options {
+		inMatrix[strings.ToLower(o.Name)] = true
+	}
+	existing := map[string]VariantOption{}
+	for _, o := range options {
+		if inMatrix[strings.ToLower(o.DisplayName)] {
+			existing[strings.ToLower(o.DisplayName)] = o
+		} else {
+			plan.DeleteOptions = append(plan.DeleteOptions, o)
+		}
+	}
+	for _, mo := range matrix.Options {
+		o, ok := existing[strings.ToLower(mo.Name)]
+		if !ok {
+			plan.CreateOptions = append(plan.CreateOptions, mo)
+			continue
+		}
+		labels := map[string]bool{}
+		for _, ov := range o.OptionValues {
+			labels[strings.ToLower(ov.Label)] = true
+		}
+		for _, v := range mo.Values {
+			if !labels[strings.ToLower(v)] {
+				plan.CreateValues[mo.Name] = append(plan.CreateValues[mo.Name], v)
+			}
+		}
+	}
+
+	// a variant is kept if its option values are a combination of the matrix
+	byCombo := map[string]Variant{}
+	for _, v := range variants {
+		if len(v.OptionValues) == 0 {
+			continue
+		}
+		labels := variantLabels(v)
+		key := []string{}
+		for _, mo := range matrix.Options {
+			key = append(key, labels[strings.ToLower(mo.Name)])
+		}
+		k := strings.Join(key, "\x00")
+		_, dup := byCombo[k]
+		if len(labels) != len(matrix.Options) || dup {
+			plan.DeleteVariants = append(plan.DeleteVariants, v)
+			continue
+		}
+		byCombo[k] = v
+	}
+	matched := map[string]bool{}
+	for _, c := range combos {
+		key := []string{}
+		for _, mo := range matrix.Options {
+			key = append(key, strings.ToLower(c.Values[mo.Name]))
+		}
+		k := strings.Join(key, "\x00")
+		v, ok := byCombo[k]
+		if !ok {
+			plan.CreateVariants = append(plan.CreateVariants, c)
+			continue
+		}
+		matched[k] = true
+		if v.Sku != c.Sku {
+			v.Sku = c.Sku
+			plan.UpdateSkus = append(plan.UpdateSkus, v)
+		} else {
+			plan.Unchanged = append(plan.Unchanged, v)
+		}
+	}
+	for k, v := range byCombo {
+		if !matched[k] {
+			plan.DeleteVariants = append(plan.DeleteVariants, v)
+		}
+	}
+	sort.Slice(plan.DeleteVariants, func(i, j int) bool {
+		return plan.DeleteVariants[i].ID < plan.DeleteVariants[j].ID
+	})
+	return plan, nil
+}
+
+// ApplyVariantMatrix makes the changes of a plan: it deletes variants and options, creates options, values
+// and variants, then updates SKUs. It stops at the first error, the changes made until then are kept.
+func (bc *Client) ApplyVariantMatrix(plan *MatrixPlan) error {
+	for _, v := range plan.DeleteVariants {
+		if err := bc.DeleteVariant(plan.ProductID, v.ID); err != nil && err != ErrNotFound {
+			return fmt.Errorf("deleting variant %d: %w", v.ID, err)
+		}
+	}
+	for _, o := range plan.DeleteOptions {
+		if err := bc.DeleteVariantOption(plan.ProductID, o.ID); err != nil && err != ErrNotFound {
+			return fmt.Errorf("deleting option %s: %w", o.DisplayName, err)
+		}
+	}
+	for _, mo := range plan.CreateOptions {
+		o := &VariantOption{DisplayName: mo.Name, Type: mo.Type}
+		if o.Type == "" {
+			o.Type = "rectangles"
+		}
+		for i, label := range mo.Values {
+			o.OptionValues = append(o.OptionValues, OptionValue{Label: label, SortOrder: i})
+		}
+		if _, err := bc.CreateVariantOption(plan.ProductID, o); err != nil {
+			return fmt.Errorf("creating option %s: %w", mo.Name, err)
+		}
+	}
+
+	options, err := bc.GetVariantOptions(plan.ProductID)
+	if err != nil {
+		return err
+	}
+	byName := map[string]VariantOption{}
+	for _, o := range options {
+		byName[strings.ToLower(o.DisplayName)] = o
+	}
+	for _, mo := range plan.matrix.Options {
+		o := byName[strings.ToLower(mo.Name)]
+		for _, label := range plan.CreateValues[mo.Name] {
+			ov, err := bc.CreateOptionValue(plan.ProductID, o.ID, &OptionValue{Label: label, SortOrder: len(o.OptionValues)})
+			if err != nil {
+				return fmt.Errorf("adding value %s to option %s: %w", label, mo.Name, err)
+			}
+			o.OptionValues = append(o.OptionValues, *ov)
+		}
+		byName[strings.ToLower(mo.Name)] = o
+	}
+
+	for _, mv := range plan.CreateVariants {
+		v := plan.matrix.Variant
+		v.ID = 0
+		v.Sku = mv.Sku
+		v.OptionValues = nil
+		for _, mo := range plan.matrix.Options {
+			o := byName[strings.ToLower(mo.Name)]
+			found := false
+			for _, ov := range o.OptionValues {
+				if strings.EqualFold(ov.Label, mv.Values[mo.Name]) {
+					v.OptionValues = append(v.OptionValues, OptionValue{
+						ID:                ov.ID,
+						Label:             ov.Label,
+						OptionID:          o.ID,
+						OptionDisplayName: o.DisplayName,
+					})
+					found = true
+					break
+				}
+			}
+			if !found {
+				return fmt.Errorf("creating variant %s: option %s has no value %s", mv.Sku, mo.Name, mv.Values[mo.Name])
+			}
+		}
+		if _, err := bc.CreateVariant(plan.ProductID, &v); err != nil {
+			return fmt.Errorf("creating variant %s: %w", mv.Sku, err)
+		}
+	}
+
+	if len(plan.UpdateSkus) > 0 {
+		// only the SKU is sent, the other fields of the variants may have changed since the plan
+		updates := []Patch{}
+		for _, v := range plan.UpdateSkus {
+			updates = append(updates, Patch{"id": v.ID, "sku": v.Sku})
+		}
+		if _, err := bc.PatchVariants(updates); err != nil {
+			return fmt.Errorf("updating SKUs: %w", err)
+		}
+	}
+	return nil
+}
+
+// ReconcileVariantMatrix brings the options and variants of a product to a matrix, creating them for
+// a new product. With dryRun the plan is returned without changing anything.
+func (bc *Client) ReconcileVariantMatrix(product *Product, matrix VariantMatrix, dryRun bool) (*MatrixPlan, error) {
+	plan, err := bc.PlanVariantMatrix(product, matrix)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun || plan.Empty() {
+		return plan, nil
+	}
+	return plan, bc.ApplyVariantMatrix(plan)
+}
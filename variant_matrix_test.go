@@ -0,0 +1,161 @@
+package bigcommerce_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+	"github.com/mvalenziano/bigcommerce-api-go/bctest"
+)
+
+// seedColors adds a Color option with Red and Blue to a new product, and a variant per color
+// with the given SKUs
+func seedColors(s *bctest.Server, redSku, blueSku string) (bigcommerce.Product, []bigcommerce.Variant) {
+	p := s.AddProduct(bigcommerce.Product{Name: "Tee", Sku: "TEE", Price: 20})
+	opt := s.AddVariantOption(p.ID, bigcommerce.VariantOption{
+		DisplayName:  "Color",
+		Type:         "rectangles",
+		OptionValues: []bigcommerce.OptionValue{{Label: "Red"}, {Label: "Blue"}},
+	})
+	variants := []bigcommerce.Variant{}
+	for i, sku := range []string{redSku, blueSku} {
+		ov := opt.OptionValues[i]
+		ov.OptionID = opt.ID
+		ov.OptionDisplayName = opt.DisplayName
+		variants = append(variants, s.AddVariant(p.ID, bigcommerce.Variant{
+			Sku:            sku,
+			Price:          25,
+			InventoryLevel: 7,
+			OptionValues:   []bigcommerce.OptionValue{ov},
+		}))
+	}
+	return p, variants
+}
+
+func TestPlanVariantMatrix(t *testing.T) {
+	colors := func(values ...string) []bigcommerce.MatrixOption {
+		return []bigcommerce.MatrixOption{{Name: "Color", Values: values}}
+	}
+	tests := []struct {
+		name    string
+		redSku  string
+		blueSku string
+		matrix  bigcommerce.VariantMatrix
+		// want is the plan as listed by String, {red} and {blue} stand for the variant IDs
+		want string
+	}{
+		{
+			name:    "matching product",
+			redSku:  "TEE-Red",
+			blueSku: "TEE-Blue",
+			matrix:  bigcommerce.VariantMatrix{Options: colors("Red", "Blue")},
+			want:    "no changes",
+		},
+		{
+			name:    "SKU from template",
+			redSku:  "TEE-Red",
+			blueSku: "TEE-Blue",
+			matrix:  bigcommerce.VariantMatrix{Options: colors("Red", "Blue"), SkuTemplate: "{{base}}/{{color}}"},
+			want:    "set SKU of variant {red} to TEE/Red\nset SKU of variant {blue} to TEE/Blue",
+		},
+		{
+			name:    "labels matched ignoring case",
+			redSku:  "TEE-red",
+			blueSku: "TEE-BLUE",
+			matrix:  bigcommerce.VariantMatrix{Options: []bigcommerce.MatrixOption{{Name: "color", Values: []string{"red", "BLUE"}}}},
+			want:    "no changes",
+		},
+		{
+			name:    "new value",
+			redSku:  "TEE-Red",
+			blueSku: "TEE-Blue",
+			matrix:  bigcommerce.VariantMatrix{Options: colors("Red", "Blue", "Green")},
+			want:    "add values to option Color: Green\ncreate variant TEE-Green",
+		},
+		{
+			name:    "dropped value",
+			redSku:  "TEE-Red",
+			blueSku: "TEE-Blue",
+			matrix:  bigcommerce.VariantMatrix{Options: colors("Red")},
+			want:    "delete variant {blue} TEE-Blue",
+		},
+		{
+			name:    "new option replaces the variants",
+			redSku:  "TEE-Red",
+			blueSku: "TEE-Blue",
+			matrix: bigcommerce.VariantMatrix{Options: []bigcommerce.MatrixOption{
+				{Name: "Color", Values: []string{"Red", "Blue"}},
+				{Name: "Size", Values: []string{"S"}},
+			}},
+			want: "delete variant {red} TEE-Red\ndelete variant {blue} TEE-Blue\ncreate option Size: S\n" +
+				"create variant TEE-Red-S\ncreate variant TEE-Blue-S",
+		},
+		{
+			name:    "option not in the matrix",
+			redSku:  "TEE-Red",
+			blueSku: "TEE-Blue",
+			matrix:  bigcommerce.VariantMatrix{Options: []bigcommerce.MatrixOption{{Name: "Size", Values: []string{"S", "M"}}}},
+			want: "delete variant {red} TEE-Red\ndelete variant {blue} TEE-Blue\ndelete option 1 Color\n" +
+				"create option Size: S, M\ncreate variant TEE-S\ncreate variant TEE-M",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := bctest.NewServer()
+			p, variants := seedColors(s, tt.redSku, tt.blueSku)
+			plan, err := s.Client().PlanVariantMatrix(&p, tt.matrix)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := strings.NewReplacer(
+				"{red}", strconv.FormatInt(variants[0].ID, 10),
+				"{blue}", strconv.FormatInt(variants[1].ID, 10),
+			).Replace(tt.want)
+			if got := plan.String(); got != want {
+				t.Errorf("plan:\n%s\nwant:\n%s", got, want)
+			}
+			if plan.Empty() != (want == "no changes") {
+				t.Errorf("plan empty: %v", plan.Empty())
+			}
+		})
+	}
+}
+
+func TestApplyVariantMatrixSendsOnlySkus(t *testing.T) {
+	s := bctest.NewServer()
+	p, variants := seedColors(s, "OLD-RED", "TEE-Blue")
+	bc := s.Client()
+
+	matrix := bigcommerce.VariantMatrix{Options: []bigcommerce.MatrixOption{{Name: "Color", Values: []string{"Red", "Blue"}}}}
+	plan, err := bc.ReconcileVariantMatrix(&p, matrix, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.UpdateSkus) != 1 || plan.UpdateSkus[0].ID != variants[0].ID {
+		t.Fatalf("plan updates %v, want variant %d", plan.UpdateSkus, variants[0].ID)
+	}
+
+	var bodies []string
+	for _, r := range s.Requests() {
+		if r.Method == http.MethodPut && r.Path == "/v3/catalog/variants" {
+			bodies = append(bodies, string(r.Body))
+		}
+	}
+	want, _ := json.Marshal([]map[string]interface{}{{"id": variants[0].ID, "sku": "TEE-Red"}})
+	if len(bodies) != 1 || bodies[0] != string(want) {
+		t.Fatalf("batch update bodies %q, want %s", bodies, want)
+	}
+
+	got, err := bc.GetAllProductVariants(p.ID, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range got {
+		if v.ID == variants[0].ID && (v.Sku != "TEE-Red" || v.Price != 25 || v.InventoryLevel != 7) {
+			t.Errorf("variant after apply: sku %s, price %v, inventory %d", v.Sku, v.Price, v.InventoryLevel)
+		}
+	}
+}
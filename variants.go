@@ -0,0 +1,226 @@
+package bigcommerce
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+)
+
+// variantBatchSize is the maximum number of variants BigCommerce accepts in a batch update
+const variantBatchSize = 50
+
+// VariantUpdateResult is the outcome of a single variant in UpdateVariants
+type VariantUpdateResult struct {
+	ID      int64
+	Sku     string
+	Variant *Variant // the updated variant, nil if the update failed
+	Err     error
+}
+
+// VariantUpdateResults holds the UpdateVariants results in input order, by variant ID and by SKU.
+// Variants that could not be resolved to an ID are not in ByID.
+type VariantUpdateResults struct {
+	Results []*VariantUpdateResult
+	ByID    map[int64]*VariantUpdateResult
+	BySku   map[string]*VariantUpdateResult
+}
+
+// Failed returns the results of the variants that were not updated
+func (r VariantUpdateResults) Failed() []*VariantUpdateResult {
+	ret := []*VariantUpdateResult{}
+	for _, res := range r.Results {
+		if res.Err != nil {
+			ret = append(ret, res)
+		}
+	}
+	return ret
+}
+
+func (r *VariantUpdateResults) add(res *VariantUpdateResult) {
+	r.Results = append(r.Results, res)
+	if res.ID != 0 {
+		r.ByID[res.ID] = res
+	}
+	if res.Sku != "" {
+		r.BySku[res.Sku] = res
+	}
+}
+
+// GetAllProductVariants returns all the variants of a product, handling pagination
+// args is a map of arguments to pass to the API
+func (bc *Client) GetAllProductVariants(productID int64, args map[string]string) ([]Variant, error) {
+	ret := []Variant{}
+	page := 1
+	more := true
+	for more {
+		var vs []Variant
+		var err error
+		vs, more, err = bc.GetProductVariants(productID, args, page)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, vs...)
+		page++
+	}
+	return ret, nil
+}
+
+// GetProductVariants returns a page of the variants of a product, and whether there are more pages
+// args is a map of arguments to pass to the API
+func (bc *Client) GetProductVariants(productID int64, args map[string]string, page int) ([]Variant, bool, error) {
+	var vs []Variant
+	more, err := bc.getPage(withArgs(fmt.Sprintf("/v3/catalog/products/%d/variants", productID), args), page, &vs)
+	if err != nil {
+		return nil, false, err
+	}
+	return vs, more, nil
+}
+
+// GetVariant gets a variant of a product by ID
+func (bc *Client) GetVariant(productID, variantID int64) (*Variant, error) {
+	var ret Variant
+	err := bc.sendJSON(http.MethodGet, fmt.Sprintf("/v3/catalog/products/%d/variants/%d", productID, variantID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// GetVariantBySku gets a variant by SKU with a filtered request, returns ErrNotFound if there is none
+func (bc *Client) GetVariantBySku(sku string) (*Variant, error) {
+	var ret []Variant
+	err := bc.sendJSON(http.MethodGet, "/v3/catalog/variants?sku="+neturl.QueryEscape(sku), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	for i := range ret {
+		// the filter is exact on the API, check anyway in case it ever does a partial match
+		if ret[i].Sku == sku {
+			return &ret[i], nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// CreateVariant creates a variant of a product, the variant needs option_values
+// unless the product has no options
+func (bc *Client) CreateVariant(productID int64, variant *Variant) (*Variant, error) {
+	payload := *variant
+	payload.ID = 0
+	payload.ProductID = productID
+	var ret Variant
+	err := bc.sendJSON(http.MethodPost, fmt.Sprintf("/v3/catalog/products/%d/variants", productID), payload, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// UpdateVariant updates a variant of a product by ID. Only the fields that are not zero values
+// are sent: an inventory_level or sale_price of 0 leaves the variant as it is, use PatchVariant to set them.
+func (bc *Client) UpdateVariant(productID int64, variant *Variant) (*Variant, error) {
+	if variant.ID == 0 {
+		return nil, errors.New("variant has no ID")
+	}
+	payload, err := setFields(variant)
+	if err != nil {
+		return nil, err
+	}
+	var ret Variant
+	err = bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/catalog/products/%d/variants/%d", productID, variant.ID), payload, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// DeleteVariant deletes a variant of a product
+func (bc *Client) DeleteVariant(productID, variantID int64) error {
+	err := bc.sendJSON(http.MethodDelete, fmt.Sprintf("/v3/catalog/products/%d/variants/%d", productID, variantID), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}
+
+// UpdateVariants updates variants of any product with the batch endpoint, in chunks of 50 variants.
+// Variants are identified by ID, variants without ID are looked up by SKU first.
+// Only the fields that are not zero values are sent: an inventory_level or sale_price of 0
+// leaves the variant as it is, use PatchVariants to set them.
+// Every variant gets a result, the error is non-nil if any variant failed to update.
+func (bc *Client) UpdateVariants(variants []Variant) (VariantUpdateResults, error) {
+	items := make([]*VariantUpdateResult, len(variants))
+	patches := make([]Patch, len(variants))
+	pending := []int{}
+	for i, v := range variants {
+		items[i] = &VariantUpdateResult{ID: v.ID, Sku: v.Sku}
+		if v.ID == 0 {
+			if v.Sku == "" {
+				items[i].Err = errors.New("variant has no ID and no SKU")
+				continue
+			}
+			found, err := bc.GetVariantBySku(v.Sku)
+			if err != nil {
+				items[i].Err = err
+				continue
+			}
+			items[i].ID = found.ID
+		}
+		patch, err := setFields(v)
+		if err != nil {
+			items[i].Err = err
+			continue
+		}
+		patch["id"] = items[i].ID
+		patches[i] = patch
+		pending = append(pending, i)
+	}
+	return bc.updateVariants(items, patches, pending)
+}
+
+// PatchVariants updates variants with the batch endpoint like UpdateVariants, sending the
+// fields of the patches as they are, zero values included. Every patch must have an "id".
+func (bc *Client) PatchVariants(patches []Patch) (VariantUpdateResults, error) {
+	items := make([]*VariantUpdateResult, len(patches))
+	pending := []int{}
+	for i, p := range patches {
+		items[i] = &VariantUpdateResult{ID: patchID(p)}
+		if items[i].ID == 0 {
+			items[i].Err = errors.New("patch has no variant ID")
+			continue
+		}
+		pending = append(pending, i)
+	}
+	return bc.updateVariants(items, patches, pending)
+}
+
+// updateVariants sends the patches of the pending items in batches and fills in their results
+func (bc *Client) updateVariants(items []*VariantUpdateResult, patches []Patch, pending []int) (VariantUpdateResults, error) {
+	updated, failed := bc.updateBatches("/v3/catalog/variants", variantBatchSize, patches, pending)
+	results := VariantUpdateResults{
+		ByID:  map[int64]*VariantUpdateResult{},
+		BySku: map[string]*VariantUpdateResult{},
+	}
+	for i, res := range items {
+		if data, ok := updated[i]; ok {
+			var v Variant
+			if err := json.Unmarshal(data, &v); err != nil {
+				res.Err = err
+			} else {
+				res.Variant = &v
+				if res.Sku == "" {
+					res.Sku = v.Sku
+				}
+			}
+		} else if err, ok := failed[i]; ok {
+			res.Err = err
+		}
+		results.add(res)
+	}
+	if failed := results.Failed(); len(failed) > 0 {
+		return results, fmt.Errorf("%d of %d variants failed to update", len(failed), len(items))
+	}
+	return results, nil
+}
@@ -0,0 +1,108 @@
+package bigcommerce_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/mvalenziano/bigcommerce-api-go"
+	"github.com/mvalenziano/bigcommerce-api-go/bctest"
+)
+
+func TestUpdateVariants(t *testing.T) {
+	tests := []struct {
+		name string
+		// unknown replaces the ID of the variant at this position with one that does not exist, -1 for none
+		unknown  int
+		wantPuts int
+		// wantFailed is the number of variants not updated: the batch of an unknown variant is rejected
+		wantFailed int
+	}{
+		{name: "all updated", unknown: -1, wantPuts: 2, wantFailed: 0},
+		{name: "unknown variant in the first batch", unknown: 3, wantPuts: 2, wantFailed: 50},
+		{name: "unknown variant in the last batch", unknown: 52, wantPuts: 2, wantFailed: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := bctest.NewServer()
+			p := s.AddProduct(bigcommerce.Product{Name: "Tee", Sku: "TEE", Price: 20})
+			variants := []bigcommerce.Variant{}
+			for i := 0; i < 55; i++ {
+				v := s.AddVariant(p.ID, bigcommerce.Variant{Sku: fmt.Sprintf("TEE-%d", i), Price: 25, InventoryLevel: 7})
+				variants = append(variants, bigcommerce.Variant{ID: v.ID, Price: 30})
+			}
+			if tt.unknown >= 0 {
+				variants[tt.unknown].ID = 99999
+			}
+			bc := s.Client()
+
+			results, err := bc.UpdateVariants(variants)
+			if (err != nil) != (tt.wantFailed > 0) {
+				t.Errorf("error %v", err)
+			}
+			if got := len(results.Failed()); got != tt.wantFailed {
+				t.Errorf("%d variants failed, want %d", got, tt.wantFailed)
+			}
+			puts := 0
+			for _, r := range s.Requests() {
+				if r.Method == http.MethodPut && r.Path == "/v3/catalog/variants" {
+					puts++
+				}
+			}
+			if puts != tt.wantPuts {
+				t.Errorf("%d batch updates, want %d", puts, tt.wantPuts)
+			}
+			for i, res := range results.Results {
+				if res.Err != nil {
+					continue
+				}
+				if res.Variant == nil || res.Variant.Price != 30 || res.Variant.InventoryLevel != 7 {
+					t.Errorf("variant %d after update: %+v", i, res.Variant)
+				}
+				if res.Sku != fmt.Sprintf("TEE-%d", i) || results.BySku[res.Sku] != res {
+					t.Errorf("variant %d has SKU %q", i, res.Sku)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAllProductVariants(t *testing.T) {
+	s := bctest.NewServer()
+	p := s.AddProduct(bigcommerce.Product{Name: "Tee", Sku: "TEE", Price: 20})
+	for i := 0; i < 60; i++ {
+		s.AddVariant(p.ID, bigcommerce.Variant{Sku: fmt.Sprintf("TEE-%d", i), Price: 25})
+	}
+	unavailable := errors.New("store unavailable")
+	tests := []struct {
+		name       string
+		failPage   string
+		maxRetries int
+		want       int
+	}{
+		{name: "all pages", want: 61}, // the base variant and the added ones
+		{name: "first page fails", failPage: "1", maxRetries: 0},
+		{name: "second page fails", failPage: "2", maxRetries: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bc := s.Client()
+			bc.MaxRetries = tt.maxRetries
+			bc.HTTPClient = failPage{HTTPClient: bc.HTTPClient, page: tt.failPage, err: unavailable}
+			vs, err := bc.GetAllProductVariants(p.ID, nil)
+			if tt.failPage != "" {
+				if !errors.Is(err, unavailable) || vs != nil {
+					t.Fatalf("got %d variants and error %v, want no variants and %v", len(vs), err, unavailable)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(vs) != tt.want {
+				t.Errorf("got %d variants, want %d", len(vs), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package bigcommerce
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Video is a BigCommerce product video, only YouTube videos are supported
+type Video struct {
+	ID          int64  `json:"id,omitempty"`
+	ProductID   int64  `json:"product_id,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+	SortOrder   int    `json:"sort_order"`
+	Type        string `json:"type,omitempty"`
+	VideoID     string `json:"video_id,omitempty"`
+	Length      string `json:"length,omitempty"`
+}
+
+// GetProductVideos returns all the videos of a product
+func (bc *Client) GetProductVideos(productID int64) ([]Video, error) {
+	ret := []Video{}
+	page := 1
+	more := true
+	for more {
+		var vs []Video
+		var err error
+		more, err = bc.getPage(fmt.Sprintf("/v3/catalog/products/%d/videos?limit=250", productID), page, &vs)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, vs...)
+		page++
+	}
+	return ret, nil
+}
+
+// GetProductVideo gets a video of a product by ID
+func (bc *Client) GetProductVideo(productID, videoID int64) (*Video, error) {
+	var ret Video
+	err := bc.sendJSON(http.MethodGet, fmt.Sprintf("/v3/catalog/products/%d/videos/%d", productID, videoID), nil, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// CreateProductVideo adds a YouTube video to a product, VideoID is the YouTube video ID
+func (bc *Client) CreateProductVideo(productID int64, video *Video) (*Video, error) {
+	payload := *video
+	payload.ID = 0
+	payload.ProductID = 0
+	payload.Length = ""
+	if payload.Type == "" {
+		payload.Type = "youtube"
+	}
+	var ret Video
+	err := bc.sendJSON(http.MethodPost, fmt.Sprintf("/v3/catalog/products/%d/videos", productID), payload, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// UpdateProductVideo updates the title, description and sort order of a product video
+func (bc *Client) UpdateProductVideo(productID int64, video *Video) (*Video, error) {
+	if video.ID == 0 {
+		return nil, errors.New("video has no ID")
+	}
+	payload := Patch{
+		"title":       video.Title,
+		"description": video.Description,
+		"sort_order":  video.SortOrder,
+	}
+	var ret Video
+	err := bc.sendJSON(http.MethodPut, fmt.Sprintf("/v3/catalog/products/%d/videos/%d", productID, video.ID), payload, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+// DeleteProductVideo deletes a video of a product
+func (bc *Client) DeleteProductVideo(productID, videoID int64) error {
+	err := bc.sendJSON(http.MethodDelete, fmt.Sprintf("/v3/catalog/products/%d/videos/%d", productID, videoID), nil, nil)
+	if err != nil && err != ErrNoContent {
+		return err
+	}
+	return nil
+}